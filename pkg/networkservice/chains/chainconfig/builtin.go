@@ -0,0 +1,311 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainconfig
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/excludedprefixes"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/passthrough/replacelabels"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/policyroute"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/setextracontext"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/swapip"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/groupipam"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/point2pointipam"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/singlepointipam"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/strictipam"
+	"github.com/networkservicemesh/sdk/pkg/tools/fs"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// Default returns a new Registry with the factories of the sdk chain elements:
+//
+//	server: setextracontext, policyroute, excludedprefixes, swapip, mechanisms, kernel,
+//	        point2pointipam, singlepointipam, strictipam, groupipam
+//	client: replacelabels, excludedprefixes, swapip, mechanisms, kernel
+func Default() *Registry {
+	r := NewRegistry()
+
+	RegisterServer(r, "setextracontext", func(_ context.Context, o *setExtraContextOptions) (networkservice.NetworkServiceServer, error) {
+		return setextracontext.NewServer(o.Values), nil
+	})
+	RegisterServer(r, "policyroute", func(_ context.Context, o *policyRouteOptions) (networkservice.NetworkServiceServer, error) {
+		return policyroute.NewServer(func() []*networkservice.PolicyRoute { return o.Policies }), nil
+	})
+	RegisterServer(r, "excludedprefixes", func(ctx context.Context, o *excludedPrefixesServerOptions) (networkservice.NetworkServiceServer, error) {
+		var opts []excludedprefixes.ServerOption
+		if o.ConfigPath != "" {
+			opts = append(opts, excludedprefixes.WithConfigPath(o.ConfigPath))
+		}
+		return excludedprefixes.NewServer(ctx, opts...), nil
+	})
+	RegisterServer(r, "swapip", func(ctx context.Context, o *swapIPOptions) (networkservice.NetworkServiceServer, error) {
+		return swapip.NewServer(o.updates(ctx)), nil
+	})
+	RegisterServer(r, "kernel", func(_ context.Context, o *kernelOptions) (networkservice.NetworkServiceServer, error) {
+		return kernel.NewServer(o.options()...), nil
+	})
+	RegisterServer(r, "point2pointipam", func(_ context.Context, o *prefixesOptions) (networkservice.NetworkServiceServer, error) {
+		return point2pointipam.NewServer(o.prefixes...), nil
+	})
+	RegisterServer(r, "singlepointipam", func(_ context.Context, o *prefixesOptions) (networkservice.NetworkServiceServer, error) {
+		return singlepointipam.NewServer(o.prefixes...), nil
+	})
+	RegisterServer(r, "strictipam", func(_ context.Context, o *strictIPAMOptions) (networkservice.NetworkServiceServer, error) {
+		return strictipam.NewServer(ipams[o.IPAM], o.prefixes...), nil
+	})
+	RegisterServer(r, "groupipam", func(_ context.Context, o *groupIPAMOptions) (networkservice.NetworkServiceServer, error) {
+		var opts []groupipam.Option
+		if o.IPAM != "" {
+			opts = append(opts, groupipam.WithCustomIPAMServer(ipams[o.IPAM]))
+		}
+		return groupipam.NewServer(o.groups, opts...), nil
+	})
+	r.servers["mechanisms"] = newMechanismsFactory(r.servers, func(m map[string][]networkservice.NetworkServiceServer) networkservice.NetworkServiceServer {
+		var result = make(map[string]networkservice.NetworkServiceServer, len(m))
+		for mechanism, elements := range m {
+			result[mechanism] = chain.NewNetworkServiceServer(elements...)
+		}
+		return mechanisms.NewServer(result)
+	})
+
+	RegisterClient(r, "replacelabels", func(_ context.Context, o *replaceLabelsOptions) (networkservice.NetworkServiceClient, error) {
+		return replacelabels.NewClient(o.Labels), nil
+	})
+	RegisterClient(r, "excludedprefixes", func(_ context.Context, o *excludedPrefixesClientOptions) (networkservice.NetworkServiceClient, error) {
+		var opts []excludedprefixes.ClientOption
+		if len(o.awarenessGroups) != 0 {
+			opts = append(opts, excludedprefixes.WithAwarenessGroups(o.awarenessGroups))
+		}
+		return excludedprefixes.NewClient(opts...), nil
+	})
+	RegisterClient(r, "swapip", func(ctx context.Context, o *swapIPOptions) (networkservice.NetworkServiceClient, error) {
+		return swapip.NewClient(o.updates(ctx)), nil
+	})
+	RegisterClient(r, "kernel", func(_ context.Context, o *kernelOptions) (networkservice.NetworkServiceClient, error) {
+		return kernel.NewClient(o.options()...), nil
+	})
+	r.clients["mechanisms"] = newMechanismsFactory(r.clients, func(m map[string][]networkservice.NetworkServiceClient) networkservice.NetworkServiceClient {
+		var result = make(map[string]networkservice.NetworkServiceClient, len(m))
+		for mechanism, elements := range m {
+			result[mechanism] = chain.NewNetworkServiceClient(elements...)
+		}
+		return mechanisms.NewClient(result)
+	})
+
+	return r
+}
+
+var ipams = map[string]func(...*net.IPNet) networkservice.NetworkServiceServer{
+	"point2pointipam": point2pointipam.NewServer,
+	"singlepointipam": singlepointipam.NewServer,
+}
+
+// newMechanismsFactory creates a factory of the mechanisms element. Its options map the mechanism type to the list of
+// the elements handling it, the elements are created by the same factories.
+func newMechanismsFactory[E any](factories map[string]*factory[E], build func(map[string][]E) E) *factory[E] {
+	var validate = func(options json.RawMessage) (map[string][]*Element, error) {
+		o, err := decodeOptions[map[string][]*Element](options)
+		if err != nil {
+			return nil, err
+		}
+		if len(*o) == 0 {
+			return nil, errors.New("invalid options: no mechanisms")
+		}
+		for mechanism, elements := range *o {
+			if err := validateElements(mechanism, factories, elements); err != nil {
+				return nil, err
+			}
+		}
+		return *o, nil
+	}
+	return &factory[E]{
+		validate: func(options json.RawMessage) error {
+			_, err := validate(options)
+			return err
+		},
+		build: func(ctx context.Context, options json.RawMessage) (e E, err error) {
+			o, err := validate(options)
+			if err != nil {
+				return e, err
+			}
+			var result = make(map[string][]E, len(o))
+			for mechanism, elements := range o {
+				if result[mechanism], err = buildElements(ctx, mechanism, factories, elements); err != nil {
+					return e, err
+				}
+			}
+			return build(result), nil
+		},
+	}
+}
+
+type setExtraContextOptions struct {
+	Values map[string]string `json:"values"`
+}
+
+type replaceLabelsOptions struct {
+	Labels map[string]string `json:"labels"`
+}
+
+type policyRouteOptions struct {
+	Policies []*networkservice.PolicyRoute `json:"policies"`
+}
+
+type excludedPrefixesServerOptions struct {
+	ConfigPath string `json:"config_path,omitempty"`
+}
+
+type excludedPrefixesClientOptions struct {
+	AwarenessGroups [][]string `json:"awareness_groups,omitempty"`
+	awarenessGroups [][]*url.URL
+}
+
+func (o *excludedPrefixesClientOptions) Validate() error {
+	for _, group := range o.AwarenessGroups {
+		var urls []*url.URL
+		for _, rawURL := range group {
+			u, err := url.Parse(rawURL)
+			if err != nil {
+				return errors.Wrapf(err, "invalid awareness group url %s", rawURL)
+			}
+			urls = append(urls, u)
+		}
+		o.awarenessGroups = append(o.awarenessGroups, urls)
+	}
+	return nil
+}
+
+// swapIPOptions sets the internal to external IP map of swapip either directly or by the file watched for updates
+type swapIPOptions struct {
+	IPMap      map[string]string `json:"ip_map,omitempty"`
+	ConfigPath string            `json:"config_path,omitempty"`
+}
+
+func (o *swapIPOptions) Validate() error {
+	if (o.IPMap == nil) == (o.ConfigPath == "") {
+		return errors.New("exactly one of ip_map and config_path should be set")
+	}
+	return nil
+}
+
+func (o *swapIPOptions) updates(ctx context.Context) <-chan map[string]string {
+	var ch = make(chan map[string]string, 1)
+	if o.IPMap != nil {
+		ch <- o.IPMap
+		close(ch)
+		return ch
+	}
+	go func() {
+		defer close(ch)
+		for data := range fs.WatchFile(ctx, o.ConfigPath) {
+			var ipMap map[string]string
+			if err := yaml.Unmarshal(data, &ipMap); err != nil {
+				log.FromContext(ctx).Errorf("failed to parse ip map %s: %v", o.ConfigPath, err.Error())
+				continue
+			}
+			select {
+			case ch <- ipMap:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+type kernelOptions struct {
+	InterfaceName string `json:"interface_name,omitempty"`
+}
+
+func (o *kernelOptions) options() []kernel.Option {
+	if o.InterfaceName == "" {
+		return nil
+	}
+	return []kernel.Option{kernel.WithInterfaceName(o.InterfaceName)}
+}
+
+type prefixesOptions struct {
+	Prefixes []string `json:"prefixes"`
+	prefixes []*net.IPNet
+}
+
+func (o *prefixesOptions) Validate() (err error) {
+	if len(o.Prefixes) == 0 {
+		return errors.New("prefixes should be set")
+	}
+	o.prefixes, err = parsePrefixes(o.Prefixes)
+	return err
+}
+
+type strictIPAMOptions struct {
+	prefixesOptions
+	IPAM string `json:"ipam"`
+}
+
+func (o *strictIPAMOptions) Validate() error {
+	if _, ok := ipams[o.IPAM]; !ok {
+		return errors.Errorf("unknown ipam %q", o.IPAM)
+	}
+	return o.prefixesOptions.Validate()
+}
+
+type groupIPAMOptions struct {
+	Groups [][]string `json:"groups"`
+	IPAM   string     `json:"ipam,omitempty"`
+	groups [][]*net.IPNet
+}
+
+func (o *groupIPAMOptions) Validate() error {
+	if _, ok := ipams[o.IPAM]; o.IPAM != "" && !ok {
+		return errors.Errorf("unknown ipam %q", o.IPAM)
+	}
+	if len(o.Groups) == 0 {
+		return errors.New("groups should be set")
+	}
+	for _, group := range o.Groups {
+		prefixes, err := parsePrefixes(group)
+		if err != nil {
+			return err
+		}
+		o.groups = append(o.groups, prefixes)
+	}
+	return nil
+}
+
+func parsePrefixes(rawPrefixes []string) ([]*net.IPNet, error) {
+	var result = make([]*net.IPNet, 0, len(rawPrefixes))
+	for _, rawPrefix := range rawPrefixes {
+		_, prefix, err := net.ParseCIDR(rawPrefix)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid prefix %s", rawPrefix)
+		}
+		result = append(result, prefix)
+	}
+	return result, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Element describes a chain element: the name of its factory and the options passed to the factory
+type Element struct {
+	Name    string          `json:"name"`
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+// GetName returns the name of the element, it is safe to call on nil
+func (e *Element) GetName() string {
+	if e == nil {
+		return ""
+	}
+	return e.Name
+}
+
+// Config describes the server and the client chains
+type Config struct {
+	Server []*Element `json:"server,omitempty"`
+	Client []*Element `json:"client,omitempty"`
+}
+
+// Parse parses YAML or JSON description of the chains
+func Parse(data []byte) (*Config, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert chain config to JSON")
+	}
+
+	var cfg = new(Config)
+	if err := decodeStrict(jsonData, cfg); err != nil {
+		return nil, errors.Wrap(err, "failed to parse chain config")
+	}
+	return cfg, nil
+}
+
+// ReadFile reads and parses YAML or JSON description of the chains from the file
+func ReadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read chain config %s", path)
+	}
+	return Parse(data)
+}
+
+// decodeStrict decodes JSON data to v, unknown fields are reported as errors
+func decodeStrict(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chainconfig builds additional functionality of endpoints, NSMgrs and forwarders from a declarative
+// description instead of Go code, so the behavior can vary per environment without recompiling.
+//
+// The description lists named chain elements with their options, for example:
+//
+//	server:
+//	  - name: setextracontext
+//	    options:
+//	      values:
+//	        env: prod
+//	  - name: strictipam
+//	    options:
+//	      ipam: point2pointipam
+//	      prefixes: ["172.16.0.0/24"]
+//	  - name: mechanisms
+//	    options:
+//	      KERNEL:
+//	        - name: kernel
+//	client:
+//	  - name: replacelabels
+//	    options:
+//	      labels:
+//	        app: icmp-responder
+//
+// Elements are created by the factories of a Registry, Default returns a Registry with the factories of the sdk chain
+// elements. The whole description is validated before any element is created:
+//
+//	cfg, err := chainconfig.ReadFile(path)
+//	...
+//	servers, err := chainconfig.Default().Servers(ctx, cfg.Server)
+//	...
+//	endpoint.NewServer(ctx, tokenGenerator, endpoint.WithAdditionalFunctionality(servers...))
+package chainconfig
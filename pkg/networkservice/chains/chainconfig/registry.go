@@ -0,0 +1,152 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainconfig
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// Validator is implemented by options which should be checked after decoding
+type Validator interface {
+	Validate() error
+}
+
+type factory[E any] struct {
+	validate func(options json.RawMessage) error
+	build    func(ctx context.Context, options json.RawMessage) (E, error)
+}
+
+func newFactory[E, T any](build func(ctx context.Context, options *T) (E, error)) *factory[E] {
+	return &factory[E]{
+		validate: func(options json.RawMessage) error {
+			_, err := decodeOptions[T](options)
+			return err
+		},
+		build: func(ctx context.Context, options json.RawMessage) (e E, err error) {
+			o, err := decodeOptions[T](options)
+			if err != nil {
+				return e, err
+			}
+			return build(ctx, o)
+		},
+	}
+}
+
+// decodeOptions decodes options to T, missing options are decoded as the zero value
+func decodeOptions[T any](options json.RawMessage) (*T, error) {
+	var result = new(T)
+	if len(options) != 0 && string(options) != "null" {
+		if err := decodeStrict(options, result); err != nil {
+			return nil, errors.Wrap(err, "invalid options")
+		}
+	}
+	if v, ok := interface{}(result).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid options")
+		}
+	}
+	return result, nil
+}
+
+// Registry keeps named factories of the chain elements. Factories should be registered before the Registry is used.
+type Registry struct {
+	servers map[string]*factory[networkservice.NetworkServiceServer]
+	clients map[string]*factory[networkservice.NetworkServiceClient]
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		servers: make(map[string]*factory[networkservice.NetworkServiceServer]),
+		clients: make(map[string]*factory[networkservice.NetworkServiceClient]),
+	}
+}
+
+// RegisterServer registers the server element factory with the name. Options of the element are decoded to T and
+// validated if T implements Validator.
+func RegisterServer[T any](r *Registry, name string, build func(ctx context.Context, options *T) (networkservice.NetworkServiceServer, error)) {
+	r.servers[name] = newFactory(build)
+}
+
+// RegisterClient registers the client element factory with the name. Options of the element are decoded to T and
+// validated if T implements Validator.
+func RegisterClient[T any](r *Registry, name string, build func(ctx context.Context, options *T) (networkservice.NetworkServiceClient, error)) {
+	r.clients[name] = newFactory(build)
+}
+
+// Validate checks that all elements of the config are registered and have valid options. Nothing is created.
+func (r *Registry) Validate(cfg *Config) error {
+	if err := validateElements("server", r.servers, cfg.Server); err != nil {
+		return err
+	}
+	return validateElements("client", r.clients, cfg.Client)
+}
+
+// Servers validates and creates the server elements in order. ctx is the lifecycle of the created elements.
+func (r *Registry) Servers(ctx context.Context, elements []*Element) ([]networkservice.NetworkServiceServer, error) {
+	return buildElements(ctx, "server", r.servers, elements)
+}
+
+// Clients validates and creates the client elements in order. ctx is the lifecycle of the created elements.
+func (r *Registry) Clients(ctx context.Context, elements []*Element) ([]networkservice.NetworkServiceClient, error) {
+	return buildElements(ctx, "client", r.clients, elements)
+}
+
+func validateElements[E any](kind string, factories map[string]*factory[E], elements []*Element) error {
+	for i, element := range elements {
+		f, ok := factories[element.GetName()]
+		if !ok {
+			return errors.Errorf("%s[%d]: unknown element %q, known elements: %s", kind, i, element.GetName(), names(factories))
+		}
+		if err := f.validate(element.Options); err != nil {
+			return errors.Wrapf(err, "%s[%d]: %s", kind, i, element.GetName())
+		}
+	}
+	return nil
+}
+
+func buildElements[E any](ctx context.Context, kind string, factories map[string]*factory[E], elements []*Element) ([]E, error) {
+	if err := validateElements(kind, factories, elements); err != nil {
+		return nil, err
+	}
+
+	var result = make([]E, 0, len(elements))
+	for i, element := range elements {
+		e, err := factories[element.GetName()].build(ctx, element.Options)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s[%d]: failed to create %s", kind, i, element.GetName())
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+func names[E any](factories map[string]*factory[E]) string {
+	var result = make([]string, 0, len(factories))
+	for name := range factories {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return strings.Join(result, ", ")
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainconfig_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
+	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/chainconfig"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/null"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/checks/checkrequest"
+)
+
+const testConfig = `
+server:
+  - name: setextracontext
+    options:
+      values:
+        env: prod
+  - name: strictipam
+    options:
+      ipam: point2pointipam
+      prefixes: ["172.16.0.0/24"]
+  - name: mechanisms
+    options:
+      KERNEL:
+        - name: kernel
+          options:
+            interface_name: nsm-1
+client:
+  - name: replacelabels
+    options:
+      labels:
+        app: icmp-responder
+`
+
+func TestRegistry_Servers(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := chainconfig.Parse([]byte(testConfig))
+	require.NoError(t, err)
+
+	registry := chainconfig.Default()
+	require.NoError(t, registry.Validate(cfg))
+
+	servers, err := registry.Servers(ctx, cfg.Server)
+	require.NoError(t, err)
+	require.Len(t, servers, 3)
+
+	conn, err := chain.NewNetworkServiceServer(servers...).Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id"},
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Cls: cls.LOCAL, Type: kernelmech.MECHANISM},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "prod", conn.GetContext().GetExtraContext()["env"])
+	require.Equal(t, []string{"172.16.0.1/32"}, conn.GetContext().GetIpContext().GetSrcIpAddrs())
+	require.Equal(t, kernelmech.MECHANISM, conn.GetMechanism().GetType())
+	require.Equal(t, "nsm-1", kernelmech.ToMechanism(conn.GetMechanism()).GetInterfaceName())
+}
+
+func TestRegistry_Clients(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := chainconfig.Parse([]byte(testConfig))
+	require.NoError(t, err)
+
+	clients, err := chainconfig.Default().Clients(ctx, cfg.Client)
+	require.NoError(t, err)
+
+	_, err = chain.NewNetworkServiceClient(append(clients,
+		checkrequest.NewClient(t, func(t *testing.T, request *networkservice.NetworkServiceRequest) {
+			require.Equal(t, map[string]string{"app": "icmp-responder"}, request.GetConnection().GetLabels())
+		}),
+	)...).Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id", Labels: map[string]string{"app": "client"}},
+	})
+	require.NoError(t, err)
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	samples := map[string]string{
+		"unknown element": `
+server:
+  - name: unknown
+`,
+		"unknown option": `
+server:
+  - name: setextracontext
+    options:
+      value: {}
+`,
+		"invalid prefix": `
+server:
+  - name: point2pointipam
+    options:
+      prefixes: ["172.16.0.0"]
+`,
+		"unknown ipam": `
+server:
+  - name: strictipam
+    options:
+      ipam: unknown
+      prefixes: ["172.16.0.0/24"]
+`,
+		"unknown mechanism element": `
+client:
+  - name: mechanisms
+    options:
+      KERNEL:
+        - name: unknown
+`,
+		"swapip without map": `
+server:
+  - name: swapip
+`,
+	}
+	for name, sample := range samples {
+		sample := sample
+		t.Run(name, func(t *testing.T) {
+			cfg, err := chainconfig.Parse([]byte(sample))
+			require.NoError(t, err)
+
+			registry := chainconfig.Default()
+			require.Error(t, registry.Validate(cfg))
+
+			_, err = registry.Servers(context.Background(), cfg.Server)
+			_, clientErr := registry.Clients(context.Background(), cfg.Client)
+			require.True(t, err != nil || clientErr != nil)
+		})
+	}
+}
+
+func TestParse_JSON(t *testing.T) {
+	cfg, err := chainconfig.Parse([]byte(`{"server": [{"name": "setextracontext", "options": {"values": {"env": "dev"}}}]}`))
+	require.NoError(t, err)
+	require.NoError(t, chainconfig.Default().Validate(cfg))
+
+	_, err = chainconfig.Parse([]byte(`{"servers": []}`))
+	require.Error(t, err)
+}
+
+type customOptions struct {
+	Enabled bool `json:"enabled"`
+}
+
+func TestRegisterServer(t *testing.T) {
+	registry := chainconfig.NewRegistry()
+
+	var created *customOptions
+	chainconfig.RegisterServer(registry, "custom", func(_ context.Context, o *customOptions) (networkservice.NetworkServiceServer, error) {
+		created = o
+		return null.NewServer(), nil
+	})
+
+	servers, err := registry.Servers(context.Background(), []*chainconfig.Element{
+		{Name: "custom", Options: []byte(`{"enabled": true}`)},
+	})
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	require.True(t, created.Enabled)
+}
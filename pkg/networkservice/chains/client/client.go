@@ -43,6 +43,7 @@ func NewClient(ctx context.Context, clientOpts ...Option) networkservice.Network
 		name:            "client-" + uuid.New().String(),
 		authorizeClient: null.NewClient(),
 		healClient:      null.NewClient(),
+		multipathClient: null.NewClient(),
 		refreshClient:   refresh.NewClient(ctx),
 	}
 	for _, opt := range clientOpts {
@@ -52,6 +53,7 @@ func NewClient(ctx context.Context, clientOpts ...Option) networkservice.Network
 	return chain.NewNetworkServiceClient(
 		append(
 			[]networkservice.NetworkServiceClient{
+				opts.multipathClient,
 				updatepath.NewClient(opts.name),
 				begin.NewClient(),
 				metadata.NewClient(),
@@ -34,6 +34,7 @@ type clientOptions struct {
 	authorizeClient         networkservice.NetworkServiceClient
 	refreshClient           networkservice.NetworkServiceClient
 	healClient              networkservice.NetworkServiceClient
+	multipathClient         networkservice.NetworkServiceClient
 	dialOptions             []grpc.DialOption
 	dialTimeout             time.Duration
 }
@@ -89,6 +90,16 @@ func WithHealClient(healClient networkservice.NetworkServiceClient) Option {
 	})
 }
 
+// WithMultipathClient sets multipathClient for the client chain. Note: this adds into head of the client chain.
+func WithMultipathClient(multipathClient networkservice.NetworkServiceClient) Option {
+	if multipathClient == nil {
+		panic("multipathClient cannot be nil")
+	}
+	return Option(func(c *clientOptions) {
+		c.multipathClient = multipathClient
+	})
+}
+
 // WithDialOptions sets dial options
 func WithDialOptions(dialOptions ...grpc.DialOption) Option {
 	return Option(func(c *clientOptions) {
@@ -0,0 +1,96 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsmgr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/client"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/multipath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/count"
+	registryclient "github.com/networkservicemesh/sdk/pkg/registry/chains/client"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+func TestNSMGR_MultipathFailover(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	domain := sandbox.NewBuilder(ctx, t).
+		SetNodesCount(1).
+		SetNSMgrProxySupplier(nil).
+		SetRegistryProxySupplier(nil).
+		Build()
+
+	nsRegistryClient := domain.NewNSRegistryClient(ctx, sandbox.GenerateTestToken)
+	nsReg, err := nsRegistryClient.Register(ctx, defaultRegistryService(t.Name()))
+	require.NoError(t, err)
+
+	counters := make(map[string]*count.Server)
+	cancels := make(map[string]context.CancelFunc)
+	for _, name := range []string{"nse-1", "nse-2"} {
+		nseReg := defaultRegistryEndpoint(nsReg.Name)
+		nseReg.Name = name
+		counters[name] = new(count.Server)
+		cancels[name] = domain.Nodes[0].NewEndpoint(ctx, nseReg, sandbox.GenerateTestToken, counters[name]).Cancel
+	}
+
+	nseRegistryClient := registryclient.NewNetworkServiceEndpointRegistryClient(ctx,
+		registryclient.WithClientURL(sandbox.CloneURL(domain.Nodes[0].NSMgr.URL)),
+		registryclient.WithDialOptions(sandbox.DialOptions(sandbox.WithTokenGenerator(sandbox.GenerateTestToken))...))
+
+	failoverCh := make(chan *networkservice.Connection, 1)
+	nsc := domain.Nodes[0].NewClient(ctx, sandbox.GenerateTestToken, client.WithMultipathClient(
+		multipath.NewClient(ctx,
+			multipath.WithNSEClient(nseRegistryClient),
+			multipath.WithFailoverCallback(func(conn *networkservice.Connection) {
+				failoverCh <- conn
+			}),
+		),
+	))
+
+	conn, err := nsc.Request(ctx, defaultRequest(nsReg.Name))
+	require.NoError(t, err)
+	require.Equal(t, 1, counters["nse-1"].UniqueRequests())
+	require.Equal(t, 1, counters["nse-2"].UniqueRequests())
+
+	primaryNSE, standbyNSE := "nse-1", "nse-2"
+	if conn.GetNetworkServiceEndpointName() != primaryNSE {
+		primaryNSE, standbyNSE = standbyNSE, primaryNSE
+	}
+
+	cancels[primaryNSE]()
+
+	select {
+	case failoverConn := <-failoverCh:
+		require.Equal(t, conn.GetId(), failoverConn.GetId())
+		require.Equal(t, standbyNSE, failoverConn.GetNetworkServiceEndpointName())
+	case <-ctx.Done():
+		require.FailNow(t, "no failover")
+	}
+
+	_, err = nsc.Close(ctx, conn)
+	require.NoError(t, err)
+	// The primary path may have healed to the standby endpoint as well
+	require.GreaterOrEqual(t, counters[standbyNSE].UniqueCloses(), 1)
+}
@@ -18,15 +18,19 @@ package nsmgr_test
 
 import (
 	"context"
+	"sync"
 	"testing"
 
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/registry"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/client"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/nsmgr"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/count"
 	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
 )
@@ -330,3 +334,89 @@ func TestReselect_Close_RegistryDied(t *testing.T) {
 	require.Equal(t, 1, counterFwd.Closes())
 	require.Equal(t, 1, counterNse.Closes())
 }
+
+// eventsServer records the Requests and Closes of the endpoints in the order they are received
+type eventsServer struct {
+	name   string
+	mu     *sync.Mutex
+	events *[]string
+}
+
+func (s *eventsServer) add(event string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.events = append(*s.events, s.name+" "+event)
+}
+
+func (s *eventsServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.add("request")
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *eventsServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.add("close")
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+// The connection migrated to the matching endpoint is closed on the old endpoint only once the new one has it
+func TestReselect_MigrationMakesBeforeBreak(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	domain := sandbox.NewBuilder(ctx, t).
+		SetNodesCount(1).
+		SetNSMgrProxySupplier(nil).
+		SetRegistryProxySupplier(nil).
+		Build()
+
+	nsRegistryClient := domain.NewNSRegistryClient(ctx, sandbox.GenerateTestToken)
+
+	nsReg := defaultRegistryService(t.Name())
+	_, err := nsRegistryClient.Register(ctx, nsReg)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var events []string
+	for _, app := range []string{"old", "new"} {
+		nseReg := defaultRegistryEndpoint(nsReg.Name)
+		nseReg.Name += "-" + app
+		nseReg.NetworkServiceLabels = map[string]*registry.NetworkServiceLabels{
+			nsReg.Name: {Labels: map[string]string{"app": app}},
+		}
+		domain.Nodes[0].NewEndpoint(ctx, nseReg, sandbox.GenerateTestToken, &eventsServer{name: app, mu: &mu, events: &events})
+
+		if app == "old" {
+			nsc := domain.Nodes[0].NewClient(ctx, sandbox.GenerateTestToken)
+			_, err = nsc.Request(ctx, defaultRequest(nsReg.Name))
+			require.NoError(t, err)
+		}
+	}
+
+	// The network service now selects only the new endpoint, so the connection migrates to it
+	nsReg.Matches = []*registry.Match{
+		{
+			SourceSelector: map[string]string{},
+			Routes: []*registry.Destination{
+				{DestinationSelector: map[string]string{"app": "new"}},
+			},
+		},
+	}
+	_, err = nsRegistryClient.Register(ctx, nsReg)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, event := range events {
+			if event == "old close" {
+				return true
+			}
+		}
+		return false
+	}, timeout, tick)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"old request", "new request", "old close"}, events)
+}
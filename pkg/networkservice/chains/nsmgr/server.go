@@ -38,10 +38,12 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/connect"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discoverforwarder"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/excludedprefixes"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/flightrecorder"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/recvfd"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/sendfd"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/metrics"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/netsvcmonitor"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/null"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/registry"
 	registryauthorize "github.com/networkservicemesh/sdk/pkg/registry/common/authorize"
@@ -55,9 +57,11 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/common/grpcmetadata"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/localbypass"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/querycache"
 	registryrecvfd "github.com/networkservicemesh/sdk/pkg/registry/common/recvfd"
 	registrysendfd "github.com/networkservicemesh/sdk/pkg/registry/common/sendfd"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/watchhub"
 
 	registryadapter "github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/chain"
@@ -92,6 +96,8 @@ type serverOptions struct {
 	name                             string
 	url                              string
 	forwarderServiceName             string
+	migrationOptions                 []netsvcmonitor.Option
+	flightRecorderServer             networkservice.NetworkServiceServer
 }
 
 // Option modifies server option value
@@ -112,6 +118,29 @@ func WithForwarderServiceName(forwarderServiceName string) Option {
 	}
 }
 
+// WithMigrationRate limits the number of connections migrated per second after a network service update, see
+// netsvcmonitor.WithMigrationRate
+func WithMigrationRate(perSecond int) Option {
+	return func(o *serverOptions) {
+		o.migrationOptions = append(o.migrationOptions, netsvcmonitor.WithMigrationRate(perSecond))
+	}
+}
+
+// WithMigrationGracePeriod sets how long connections are kept on endpoints that don't match the updated network
+// service, see netsvcmonitor.WithGracePeriod
+func WithMigrationGracePeriod(gracePeriod time.Duration) Option {
+	return func(o *serverOptions) {
+		o.migrationOptions = append(o.migrationOptions, netsvcmonitor.WithGracePeriod(gracePeriod))
+	}
+}
+
+// WithFlightRecorder records the recent Request and Close events of the nsmgr connections to recorder
+func WithFlightRecorder(recorder *flightrecorder.Recorder) Option {
+	return func(o *serverOptions) {
+		o.flightRecorderServer = flightrecorder.NewServer(recorder)
+	}
+}
+
 // WithDefaultExpiration sets the default expiration for endpoints
 func WithDefaultExpiration(d time.Duration) Option {
 	return func(o *serverOptions) {
@@ -226,6 +255,7 @@ func NewServer(ctx context.Context, tokenGenerator token.GeneratorFunc, options
 		dialTimeout:                      time.Millisecond * 300,
 		name:                             "nsmgr-" + uuid.New().String(),
 		forwarderServiceName:             "forwarder",
+		flightRecorderServer:             null.NewServer(),
 	}
 	for _, opt := range options {
 		opt(opts)
@@ -294,23 +324,32 @@ func NewServer(ctx context.Context, tokenGenerator token.GeneratorFunc, options
 		registrysendfd.NewNetworkServiceEndpointRegistryServer(),
 		remoteOrLocalRegistry,
 	)
+	// Watches of the network services and endpoints are shared between all connections
+	var nsWatchClient = chain.NewNetworkServiceRegistryClient(
+		querycache.NewNetworkServiceRegistryClient(ctx),
+		watchhub.NewNetworkServiceRegistryClient(ctx),
+		registryadapter.NetworkServiceServerToClient(nsRegistry),
+	)
+	var nseWatchClient = chain.NewNetworkServiceEndpointRegistryClient(
+		watchhub.NewNetworkServiceEndpointRegistryClient(ctx),
+		registryadapter.NetworkServiceEndpointServerToClient(remoteOrLocalRegistry),
+	)
+
 	// Construct Endpoint
 	rv.Endpoint = endpoint.NewServer(ctx, tokenGenerator,
 		endpoint.WithName(opts.name),
 		endpoint.WithAuthorizeServer(opts.authorizeServer),
 		endpoint.WithAuthorizeMonitorConnectionServer(opts.authorizeMonitorConnectionServer),
 		endpoint.WithAdditionalFunctionality(
+			opts.flightRecorderServer,
 			adapters.NewClientToServer(clientinfo.NewClient()),
 			discoverforwarder.NewServer(
-				registryadapter.NetworkServiceServerToClient(nsRegistry),
-				registryadapter.NetworkServiceEndpointServerToClient(remoteOrLocalRegistry),
+				nsWatchClient,
+				nseWatchClient,
 				discoverforwarder.WithForwarderServiceName(opts.forwarderServiceName),
 				discoverforwarder.WithNSMgrURL(opts.url),
 			),
-			netsvcmonitor.NewServer(ctx,
-				registryadapter.NetworkServiceServerToClient(nsRegistry),
-				registryadapter.NetworkServiceEndpointServerToClient(remoteOrLocalRegistry),
-			),
+			netsvcmonitor.NewServer(ctx, nsWatchClient, nseWatchClient, opts.migrationOptions...),
 			excludedprefixes.NewServer(ctx),
 			recvfd.NewServer(), // Receive any files passed
 			metrics.NewServer(),
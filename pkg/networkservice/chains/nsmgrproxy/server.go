@@ -47,8 +47,10 @@ import (
 	registryconnect "github.com/networkservicemesh/sdk/pkg/registry/common/connect"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/dial"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/grpcmetadata"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/querycache"
 	registryswapip "github.com/networkservicemesh/sdk/pkg/registry/common/swapip"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/watchhub"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/chain"
 	"github.com/networkservicemesh/sdk/pkg/tools/fs"
 	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
@@ -226,6 +228,7 @@ func NewServer(ctx context.Context, regURL, proxyURL *url.URL, tokenGenerator to
 
 	nseClient := chain.NewNetworkServiceEndpointRegistryClient(
 		begin.NewNetworkServiceEndpointRegistryClient(),
+		watchhub.NewNetworkServiceEndpointRegistryClient(ctx),
 		clienturl.NewNetworkServiceEndpointRegistryClient(regURL),
 		clientconn.NewNetworkServiceEndpointRegistryClient(),
 		dial.NewNetworkServiceEndpointRegistryClient(ctx,
@@ -236,7 +239,9 @@ func NewServer(ctx context.Context, regURL, proxyURL *url.URL, tokenGenerator to
 	)
 
 	nsClient := chain.NewNetworkServiceRegistryClient(
+		querycache.NewNetworkServiceRegistryClient(ctx),
 		begin.NewNetworkServiceRegistryClient(),
+		watchhub.NewNetworkServiceRegistryClient(ctx),
 		clienturl.NewNetworkServiceRegistryClient(regURL),
 		clientconn.NewNetworkServiceRegistryClient(),
 		dial.NewNetworkServiceRegistryClient(ctx,
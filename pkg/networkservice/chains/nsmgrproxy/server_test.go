@@ -18,11 +18,20 @@ package nsmgrproxy_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
 	"fmt"
+	"math/big"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"google.golang.org/grpc"
@@ -33,6 +42,7 @@ import (
 	"github.com/networkservicemesh/api/pkg/api/registry"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/client"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/authorize"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/clienturl"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/connect"
 	kernelmech "github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/kernel"
@@ -42,6 +52,8 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/tools/interdomain"
 	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
 )
 
 // TestNSMGR_InterdomainUseCase covers simple interdomain scenario:
@@ -769,3 +781,133 @@ func Test_Interdomain_PassThroughUsecase(t *testing.T) {
 	_, err = nsc.Close(ctx, conn)
 	require.NoError(t, err)
 }
+
+type x509Source struct {
+	svid *x509svid.SVID
+}
+
+func (s *x509Source) GetX509SVID() (*x509svid.SVID, error) {
+	return s.svid, nil
+}
+
+// newTrustDomain returns the CA of the trust domain and the token generator of its workloads putting the certificate
+// chain into the tokens
+func newTrustDomain(t *testing.T, td spiffeid.TrustDomain, audience ...string) (*x509.Certificate, token.GeneratorFunc) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id := spiffeid.RequireFromPath(td, "/nsm")
+	der, err = x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		URIs:         []*url.URL{id.URL()},
+	}, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	source := &x509Source{svid: &x509svid.SVID{
+		ID:           id,
+		Certificates: []*x509.Certificate{cert},
+		PrivateKey:   key,
+	}}
+	return ca, spiffejwt.TokenGeneratorFunc(source, time.Hour, spiffejwt.WithCertificateChain(), spiffejwt.WithAudience(audience...))
+}
+
+// TestNSMGR_InterdomainFederatedTokens covers interdomain scenario with the whole path verified by the final endpoint
+// against the federated trust bundles:
+//
+//	nsc -> nsmgr1 ->  forwarder1 -> nsmgr1 -> nsmgr-proxy1 -> nsmg-proxy2 -> nsmgr2 ->forwarder2 -> nsmgr2 -> nse
+func TestNSMGR_InterdomainFederatedTokens(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	td1 := spiffeid.RequireTrustDomainFromString("cluster1.example")
+	td2 := spiffeid.RequireTrustDomainFromString("cluster2.example")
+	audience := []string{spiffeid.RequireFromPath(td1, "/nsm").String(), spiffeid.RequireFromPath(td2, "/nsm").String()}
+	ca1, tokenGenerator1 := newTrustDomain(t, td1, audience...)
+	ca2, tokenGenerator2 := newTrustDomain(t, td2, audience...)
+
+	var dnsServer = sandbox.NewFakeResolver()
+
+	cluster1 := sandbox.NewBuilder(ctx, t).
+		SetNodesCount(1).
+		SetDNSResolver(dnsServer).
+		SetDNSDomainName("cluster1").
+		SetTokenGenerateFunc(tokenGenerator1).
+		Build()
+
+	cluster2 := sandbox.NewBuilder(ctx, t).
+		SetNodesCount(1).
+		SetDNSDomainName("cluster2").
+		SetDNSResolver(dnsServer).
+		SetTokenGenerateFunc(tokenGenerator2).
+		Build()
+
+	federated := spiffebundle.NewSet(
+		spiffebundle.FromX509Authorities(td1, []*x509.Certificate{ca1}),
+		spiffebundle.FromX509Authorities(td2, []*x509.Certificate{ca2}),
+	)
+	local := spiffebundle.NewSet(spiffebundle.FromX509Authorities(td2, []*x509.Certificate{ca2}))
+
+	nsRegistryClient := cluster2.NewNSRegistryClient(ctx, tokenGenerator2)
+	for name, bundles := range map[string]spiffejwt.BundleSource{"federated": federated, "local": local} {
+		nsReg, err := nsRegistryClient.Register(ctx, &registry.NetworkService{Name: name})
+		require.NoError(t, err)
+
+		nseReg := &registry.NetworkServiceEndpoint{
+			Name:                name + "-endpoint",
+			NetworkServiceNames: []string{nsReg.Name},
+		}
+		cluster2.Nodes[0].NewEndpoint(ctx, nseReg, tokenGenerator2, authorize.NewServer(
+			authorize.Any(),
+			authorize.WithCustomPolicies(spiffejwt.PathPolicy(spiffejwt.NewVerifier(spiffejwt.WithBundles(bundles)))),
+		))
+	}
+
+	nsc := cluster1.Nodes[0].NewClient(ctx, tokenGenerator1)
+
+	request := &networkservice.NetworkServiceRequest{
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Cls: cls.LOCAL, Type: kernel.MECHANISM},
+		},
+		Connection: &networkservice.Connection{
+			Id:             "1",
+			NetworkService: fmt.Sprint("federated@", cluster2.Name),
+			Context:        &networkservice.ConnectionContext{},
+		},
+	}
+
+	conn, err := nsc.Request(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 8, len(conn.GetPath().GetPathSegments()))
+
+	_, err = nsc.Close(ctx, conn)
+	require.NoError(t, err)
+
+	// The endpoint trusting only its own trust domain rejects the tokens issued in cluster1
+	request.Connection.Id = "2"
+	request.GetConnection().NetworkService = fmt.Sprint("local@", cluster2.Name)
+	requestCtx, requestCancel := context.WithTimeout(ctx, time.Second)
+	defer requestCancel()
+	_, err = nsc.Request(requestCtx, request)
+	require.Error(t, err)
+}
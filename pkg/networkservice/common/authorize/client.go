@@ -24,6 +24,7 @@ import (
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/peer"
 
@@ -33,11 +34,13 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
 	"github.com/networkservicemesh/sdk/pkg/tools/opa"
 	"github.com/networkservicemesh/sdk/pkg/tools/postpone"
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
 )
 
 type authorizeClient struct {
-	policies   policiesList
-	serverPeer atomic.Value
+	policies       policiesList
+	revocationList *revocation.List
+	serverPeer     atomic.Value
 }
 
 // NewClient - returns a new authorization networkservicemesh.NetworkServiceClient
@@ -61,9 +64,11 @@ func NewClient(opts ...Option) networkservice.NetworkServiceClient {
 	for _, p := range policies {
 		policyList = append(policyList, p)
 	}
+	policyList = append(policyList, o.policies...)
 
 	var result = &authorizeClient{
-		policies: policyList,
+		policies:       policyList,
+		revocationList: o.revocationList,
 	}
 	return result
 }
@@ -84,7 +89,11 @@ func (a *authorizeClient) Request(ctx context.Context, request *networkservice.N
 		ctx = peer.NewContext(ctx, &p)
 	}
 
-	if err = a.policies.check(ctx, conn.GetPath()); err != nil {
+	err = a.policies.check(ctx, conn.GetPath())
+	if err == nil {
+		err = newRevocableConnection(conn.GetPath(), spiffeid.ID{}).check(a.revocationList)
+	}
+	if err != nil {
 		if !load(ctx, metadata.IsClient(a)) {
 			closeCtx, cancelClose := postponeCtxFunc()
 			defer cancelClose()
@@ -23,8 +23,12 @@ import (
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
 
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/begin"
 	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
 )
 
 // Policy represents authorization policy for network service.
@@ -41,6 +45,8 @@ func (l *policiesList) check(ctx context.Context, p *networkservice.Path) error
 	if l == nil {
 		return nil
 	}
+	// Tokens are verified once for all policies of the check
+	ctx = spiffejwt.WithVerificationCache(ctx)
 	for _, policy := range *l {
 		if policy == nil {
 			continue
@@ -52,3 +58,25 @@ func (l *policiesList) check(ctx context.Context, p *networkservice.Path) error
 	}
 	return nil
 }
+
+// revocableConnection keeps the credentials of the connection to close it with eventFactory once they are revoked
+type revocableConnection struct {
+	tokens       []string
+	spiffeID     spiffeid.ID
+	eventFactory begin.EventFactory
+}
+
+func newRevocableConnection(p *networkservice.Path, spiffeID spiffeid.ID) *revocableConnection {
+	r := &revocableConnection{spiffeID: spiffeID}
+	for _, segment := range p.GetPathSegments() {
+		r.tokens = append(r.tokens, segment.GetToken())
+	}
+	return r
+}
+
+func (r *revocableConnection) check(list *revocation.List) error {
+	if err := list.CheckSpiffeID(r.spiffeID); err != nil {
+		return err
+	}
+	return list.CheckTokens(r.tokens...)
+}
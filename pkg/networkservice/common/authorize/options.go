@@ -19,12 +19,19 @@
 package authorize
 
 import (
+	"context"
+
 	"github.com/edwarnicke/genericsync"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
 )
 
 type options struct {
+	revocationCtx         context.Context
 	policyPaths           []string
+	policies              []Policy
+	revocationList        *revocation.List
 	spiffeIDConnectionMap *genericsync.Map[spiffeid.ID, *genericsync.Map[string, struct{}]]
 }
 
@@ -44,6 +51,25 @@ func WithPolicies(policyPaths ...string) Option {
 	}
 }
 
+// WithCustomPolicies adds policies implemented in Go, e.g. spiffejwt.PrevTokenPolicy, to the policies read from
+// policyPaths
+func WithCustomPolicies(policies ...Policy) Option {
+	return func(o *options) {
+		o.policies = append(o.policies, policies...)
+	}
+}
+
+// WithRevocationList sets list of revoked tokens and SPIFFE IDs. Server rejects requests with revoked tokens on the left
+// side of the path or from the revoked peer and closes the established connections once their credentials are revoked,
+// so it should be placed after the begin chain element. Client rejects connections with revoked tokens in the path.
+//   - chainCtx - context for the lifecycle of the chain, server stops following the list updates when it is done
+func WithRevocationList(chainCtx context.Context, list *revocation.List) Option {
+	return func(o *options) {
+		o.revocationCtx = chainCtx
+		o.revocationList = list
+	}
+}
+
 // WithSpiffeIDConnectionMap sets map to keep spiffeIDConnectionMap to authorize connections with MonitorConnectionServer
 func WithSpiffeIDConnectionMap(s *genericsync.Map[spiffeid.ID, *genericsync.Map[string, struct{}]]) Option {
 	return func(o *options) {
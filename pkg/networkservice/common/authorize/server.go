@@ -30,13 +30,18 @@ import (
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/begin"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
 	"github.com/networkservicemesh/sdk/pkg/tools/opa"
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
 	"github.com/networkservicemesh/sdk/pkg/tools/spire"
 )
 
 type authorizeServer struct {
 	policies              policiesList
+	revocationList        *revocation.List
+	revocableConnections  genericsync.Map[string, *revocableConnection]
 	spiffeIDConnectionMap *genericsync.Map[spiffeid.ID, *genericsync.Map[string, struct{}]]
 }
 
@@ -62,11 +67,20 @@ func NewServer(opts ...Option) networkservice.NetworkServiceServer {
 	for _, p := range policies {
 		policyList = append(policyList, p)
 	}
+	policyList = append(policyList, o.policies...)
 
 	var s = &authorizeServer{
 		policies:              policyList,
+		revocationList:        o.revocationList,
 		spiffeIDConnectionMap: o.spiffeIDConnectionMap,
 	}
+	if s.revocationList != nil {
+		unsubscribe := s.revocationList.Subscribe(s.revoke)
+		go func() {
+			<-o.revocationCtx.Done()
+			unsubscribe()
+		}()
+	}
 	return s
 }
 
@@ -84,6 +98,10 @@ func (a *authorizeServer) Request(ctx context.Context, request *networkservice.N
 	}
 
 	spiffeID, loadErr := spire.PeerSpiffeIDFromContext(ctx)
+	revocable := newRevocableConnection(leftSide, spiffeID)
+	if err := revocable.check(a.revocationList); err != nil {
+		return nil, errors.Wrap(err, "networkservice: connection credentials are revoked")
+	}
 	if loadErr == nil {
 		connID := conn.GetPath().GetPathSegments()[index-1].GetId()
 		ids, ok := a.spiffeIDConnectionMap.Load(spiffeID)
@@ -98,11 +116,18 @@ func (a *authorizeServer) Request(ctx context.Context, request *networkservice.N
 	if loadErr == nil && err != nil {
 		a.spiffeIDConnectionMap.Delete(spiffeID)
 	}
+	if err == nil && a.revocationList != nil {
+		if revocable.eventFactory = begin.FromContext(ctx); revocable.eventFactory != nil {
+			a.revocableConnections.Store(conn.GetId(), revocable)
+		}
+	}
 
 	return conn, err
 }
 
 func (a *authorizeServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	a.revocableConnections.Delete(conn.GetId())
+
 	var index = conn.GetPath().GetIndex()
 	var leftSide = &networkservice.Path{
 		Index:        index,
@@ -137,3 +162,15 @@ func (a *authorizeServer) Close(ctx context.Context, conn *networkservice.Connec
 	}
 	return next.Server(ctx).Close(ctx, conn)
 }
+
+// revoke closes the connections with the revoked credentials
+func (a *authorizeServer) revoke() {
+	a.revocableConnections.Range(func(id string, revocable *revocableConnection) bool {
+		if err := revocable.check(a.revocationList); err != nil {
+			log.L().Warnf("closing connection %s: %s", id, err.Error())
+			a.revocableConnections.Delete(id)
+			revocable.eventFactory.Close()
+		}
+		return true
+	})
+}
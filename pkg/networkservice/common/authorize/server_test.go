@@ -37,6 +37,7 @@ import (
 	mathrand "math/rand"
 
 	"github.com/edwarnicke/genericsync"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/pkg/errors"
@@ -49,8 +50,11 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/authorize"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/begin"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/count"
 	"github.com/networkservicemesh/sdk/pkg/tools/nanoid"
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
 )
 
 func generateCert(u *url.URL) []byte {
@@ -309,3 +313,60 @@ func TestAuthorize_SpiffeIDConnectionMapHaveNoLeaks(t *testing.T) {
 	})
 	require.Equal(t, mapLen, 0)
 }
+
+func TestAuthorize_RevocationList(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	tokenWithID := func(jti string) string {
+		tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"jti": jti}).SignedString([]byte("key"))
+		require.NoError(t, err)
+		return tok
+	}
+	request := func(connID, jti string) *networkservice.NetworkServiceRequest {
+		return &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{
+				Id: connID,
+				Path: &networkservice.Path{
+					Index: 1,
+					PathSegments: []*networkservice.PathSegment{
+						{Id: "client-" + connID, Token: tokenWithID(jti)},
+						{Id: connID, Token: tokenWithID(jti + "-nsmgr")},
+					},
+				},
+			},
+		}
+	}
+
+	ctx, err := withPeer(context.Background(), generateCert(&url.URL{Scheme: "spiffe", Host: "test.com", Path: "nsc"}))
+	require.NoError(t, err)
+
+	chainCtx, chainCancel := context.WithCancel(context.Background())
+	defer chainCancel()
+
+	list := revocation.NewList(nil)
+	counter := new(count.Server)
+	server := next.NewNetworkServiceServer(
+		begin.NewServer(),
+		authorize.NewServer(authorize.Any(), authorize.WithRevocationList(chainCtx, list)),
+		counter,
+	)
+
+	_, err = server.Request(ctx, request("conn-1", "id-1"))
+	require.NoError(t, err)
+	_, err = server.Request(ctx, request("conn-2", "id-2"))
+	require.NoError(t, err)
+
+	// Revoked token closes the connection and rejects the new requests
+	list.Update(&revocation.Entries{JTIs: []string{"id-1"}})
+	require.Eventually(t, func() bool { return counter.Closes() == 1 }, time.Second, 10*time.Millisecond)
+
+	_, err = server.Request(ctx, request("conn-1", "id-1"))
+	require.ErrorIs(t, err, revocation.ErrRevoked)
+
+	// Revoked peer closes all its connections
+	list.Update(&revocation.Entries{SpiffeIDs: []string{"spiffe://test.com/nsc"}})
+	require.Eventually(t, func() bool { return counter.Closes() == 2 }, time.Second, 10*time.Millisecond)
+
+	_, err = server.Request(ctx, request("conn-3", "id-3"))
+	require.ErrorIs(t, err, revocation.ErrRevoked)
+}
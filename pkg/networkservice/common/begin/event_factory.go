@@ -201,9 +201,15 @@ func (f *eventFactoryServer) Request(opts ...Option) <-chan error {
 		select {
 		case <-o.cancelCtx.Done():
 		default:
+			request := f.request
+			if o.reselectEndpoint && request.GetConnection() != nil {
+				// Server side reselect keeps the connection with the client, only the endpoint is selected again
+				request = request.Clone()
+				request.GetConnection().NetworkServiceEndpointName = ""
+			}
 			ctx, cancel := f.ctxFunc()
 			defer cancel()
-			conn, err := f.server.Request(ctx, f.request)
+			conn, err := f.server.Request(ctx, request)
 			if err == nil && f.request != nil {
 				f.request.Connection = conn
 			}
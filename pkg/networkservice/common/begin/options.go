@@ -1,4 +1,4 @@
-// Copyright (c) 2021 Cisco and/or its affiliates.
+// Copyright (c) 2021-2024 Cisco and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -21,8 +21,9 @@ import (
 )
 
 type option struct {
-	cancelCtx context.Context
-	reselect  bool
+	cancelCtx        context.Context
+	reselect         bool
+	reselectEndpoint bool
 }
 
 // Option - event option
@@ -41,3 +42,11 @@ func WithReselect() Option {
 		o.reselect = true
 	}
 }
+
+// WithReselectEndpoint - optionally clear NetworkServiceEndpointName of the server EventFactory request, so the
+// endpoint is selected again while the connection with the client is kept. The client EventFactory ignores it.
+func WithReselectEndpoint() Option {
+	return func(o *option) {
+		o.reselectEndpoint = true
+	}
+}
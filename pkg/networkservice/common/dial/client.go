@@ -21,6 +21,7 @@ package dial
 
 import (
 	"context"
+	"net/url"
 	"time"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
@@ -31,6 +32,7 @@ import (
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/clientconn"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
 	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
 	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
 	"github.com/networkservicemesh/sdk/pkg/tools/log"
@@ -56,7 +58,6 @@ func NewClient(chainCtx context.Context, opts ...Option) networkservice.NetworkS
 }
 
 func (d *dialClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
-	closeContextFunc := postpone.ContextWithValues(ctx)
 	// If no clientURL, we have no work to do
 	// call the next in the chain
 	clientURL := clienturlctx.ClientURL(ctx)
@@ -72,17 +73,9 @@ func (d *dialClient) Request(ctx context.Context, request *networkservice.Networ
 		return next.Client(ctx).Request(ctx, request, opts...)
 	}
 
-	// If our existing dialer has a different URL close down the chain
+	// If our existing dialer has a different URL, the connection moves to the new one
 	if di.clientURL != nil && di.clientURL.String() != clientURL.String() {
-		closeCtx, closeCancel := closeContextFunc()
-		defer closeCancel()
-		err := di.Dial(closeCtx, di.clientURL)
-		if err != nil {
-			log.FromContext(ctx).Errorf("can not redial to %v, err %v. Deleting clientconn...", grpcutils.URLToTarget(di.clientURL), err)
-			clientconn.Delete(ctx)
-			return nil, err
-		}
-		_, _ = next.Client(ctx).Close(clienturlctx.WithClientURL(closeCtx, di.clientURL), request.GetConnection(), opts...)
+		return d.move(ctx, di, clientURL, request, opts...)
 	}
 
 	err := di.Dial(ctx, clientURL)
@@ -107,6 +100,39 @@ func (d *dialClient) Request(ctx context.Context, request *networkservice.Networ
 	return conn, nil
 }
 
+// move requests the connection with the new URL first and closes the path to the old URL only once the new one is
+// established (make-before-break). The old path is closed with the copy of the metadata taken before the request, so
+// the chain elements close their old state and keep the new one. If the request fails, the old dialer is kept.
+func (d *dialClient) move(ctx context.Context, old *dialer, clientURL *url.URL, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	closeContextFunc := postpone.ContextWithValues(metadata.WithCopy(ctx))
+	oldConn := request.GetConnection().Clone()
+
+	di := newDialer(d.chainCtx, d.dialTimeout, d.dialOptions...)
+	if err := di.Dial(ctx, clientURL); err != nil {
+		log.FromContext(ctx).Errorf("Can not dial to %v", grpcutils.URLToTarget(clientURL))
+		_ = di.Close()
+		return nil, err
+	}
+	clientconn.Store(ctx, di)
+
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		_ = di.Close()
+		clientconn.Store(ctx, old)
+		return nil, err
+	}
+
+	closeCtx, closeCancel := closeContextFunc()
+	defer closeCancel()
+	if err := old.Dial(closeCtx, old.clientURL); err != nil {
+		log.FromContext(ctx).Errorf("can not redial to %v, err %v", grpcutils.URLToTarget(old.clientURL), err)
+	} else {
+		_, _ = next.Client(ctx).Close(clienturlctx.WithClientURL(closeCtx, old.clientURL), oldConn, opts...)
+	}
+	_ = old.Close()
+	return conn, nil
+}
+
 func (d *dialClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cc, _ := clientconn.Load(ctx)
 	di, ok := cc.(*dialer)
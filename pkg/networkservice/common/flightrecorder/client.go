@@ -0,0 +1,53 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flightrecorder
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type flightRecorderClient struct {
+	recorder *Recorder
+}
+
+// NewClient returns a new flight recorder client chain element recording the events to recorder
+func NewClient(recorder *Recorder) networkservice.NetworkServiceClient {
+	return &flightRecorderClient{
+		recorder: recorder,
+	}
+}
+
+func (c *flightRecorderClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	return recordRequest(ctx, c.recorder, true, request, func() (*networkservice.Connection, error) {
+		return next.Client(ctx).Request(ctx, request, opts...)
+	})
+}
+
+func (c *flightRecorderClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	var rv *empty.Empty
+	err := recordClose(ctx, c.recorder, true, conn, func() (err error) {
+		rv, err = next.Client(ctx).Close(ctx, conn, opts...)
+		return err
+	})
+	return rv, err
+}
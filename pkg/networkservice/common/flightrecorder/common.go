@@ -0,0 +1,99 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flightrecorder
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/trace/traceverbose"
+)
+
+const (
+	methodRequest = "Request"
+	methodClose   = "Close"
+)
+
+func recordRequest(ctx context.Context, r *Recorder, isClient bool, req *networkservice.NetworkServiceRequest,
+	call func() (*networkservice.Connection, error)) (*networkservice.Connection, error) {
+	last := loadLastMessages(ctx, isClient)
+	event := &Event{
+		Time:   r.clk.Now(),
+		ID:     req.GetConnection().GetId(),
+		Method: methodRequest,
+		Client: isClient,
+	}
+	req = req.Clone()
+	req.Connection = withoutTokens(req.GetConnection())
+	event.Request = diff(last.request, req)
+	last.request = req
+
+	conn, err := call()
+
+	event.Duration = r.clk.Since(event.Time)
+	if err != nil {
+		event.Error = err.Error()
+	} else {
+		response := withoutTokens(conn)
+		event.Response = diff(last.response, response)
+		last.response = response
+	}
+	r.record(event)
+
+	return conn, err
+}
+
+func recordClose(ctx context.Context, r *Recorder, isClient bool, conn *networkservice.Connection, call func() error) error {
+	last := loadLastMessages(ctx, isClient)
+	event := &Event{
+		Time:    r.clk.Now(),
+		ID:      conn.GetId(),
+		Method:  methodClose,
+		Client:  isClient,
+		Request: diff(last.response, withoutTokens(conn)),
+	}
+
+	err := call()
+
+	event.Duration = r.clk.Since(event.Time)
+	if err != nil {
+		event.Error = err.Error()
+	}
+	r.record(event)
+
+	return err
+}
+
+// withoutTokens returns the clone of the connection without the path segment tokens, so the tokens are not kept in
+// memory and are not dumped
+func withoutTokens(conn *networkservice.Connection) *networkservice.Connection {
+	conn = conn.Clone()
+	for _, segment := range conn.GetPath().GetPathSegments() {
+		segment.Token = ""
+	}
+	return conn
+}
+
+func diff(oldMessage, newMessage proto.Message) map[string]interface{} {
+	if newMessage == nil {
+		return nil
+	}
+	d, _ := traceverbose.Diff(oldMessage.ProtoReflect(), newMessage.ProtoReflect())
+	return d
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package flightrecorder provides chain elements keeping the recent Request and Close events in memory.
+//
+// The elements are cheap enough to be always on: every event is a compact record with the diffs of the request and
+// the response from the previous ones of the same connection, the error and the duration. The events are kept in a
+// bounded ring buffer of the Recorder and are dumped on demand: with the Recorder http.Handler, on SIGUSR1 and
+// automatically when the errors rate crosses the threshold set by WithErrorThreshold.
+//
+// The elements use per Connection.Id metadata, so metadata element should be placed before them.
+package flightrecorder
@@ -0,0 +1,41 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flightrecorder
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type keyType struct{}
+
+// lastMessages are the last request and response of the connection
+type lastMessages struct {
+	request  *networkservice.NetworkServiceRequest
+	response *networkservice.Connection
+}
+
+func loadLastMessages(ctx context.Context, isClient bool) *lastMessages {
+	last, _ := metadata.Map(ctx, isClient).LoadOrStore(keyType{}, &lastMessages{
+		request:  new(networkservice.NetworkServiceRequest),
+		response: new(networkservice.Connection),
+	})
+	return last.(*lastMessages)
+}
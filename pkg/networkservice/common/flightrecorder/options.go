@@ -0,0 +1,50 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flightrecorder
+
+import (
+	"io"
+	"time"
+)
+
+// Option changes default settings for the Recorder
+type Option func(*Recorder)
+
+// WithSize sets the number of the recent events kept by the Recorder. Default is 1024.
+func WithSize(size int) Option {
+	return func(r *Recorder) {
+		if size > 0 {
+			r.events = make([]*Event, size)
+		}
+	}
+}
+
+// WithErrorThreshold dumps the events automatically when count events fail within window.
+// By default the events are dumped only on demand.
+func WithErrorThreshold(count int, window time.Duration) Option {
+	return func(r *Recorder) {
+		r.errorCount = count
+		r.errorWindow = window
+	}
+}
+
+// WithOutput sets where the events are dumped on SIGUSR1 and on the errors threshold. Default is os.Stderr.
+func WithOutput(output io.Writer) Option {
+	return func(r *Recorder) {
+		r.output = output
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flightrecorder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/edwarnicke/serialize"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const defaultSize = 1024
+
+// Event is a Request or Close call recorded by the flight recorder elements
+type Event struct {
+	Time time.Time `json:"time"`
+	// ID is the connection ID
+	ID     string `json:"id"`
+	Method string `json:"method"`
+	Client bool   `json:"client,omitempty"`
+	// Duration is the time spent in the rest of the chain
+	Duration time.Duration `json:"duration"`
+	// Request is the diff of the request from the previous request of the connection, for Close it is the diff of the
+	// closed connection from the last response
+	Request map[string]interface{} `json:"request,omitempty"`
+	// Response is the diff of the response from the previous response of the connection
+	Response map[string]interface{} `json:"response,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+// Recorder keeps the recent events in a ring buffer
+type Recorder struct {
+	ctx    context.Context
+	clk    clock.Clock
+	output io.Writer
+
+	errorCount  int
+	errorWindow time.Duration
+	// executor serializes the dumps, they are written out of the Request and Close calls
+	executor serialize.Executor
+
+	mu     sync.Mutex
+	events []*Event
+	next   int
+	errors []time.Time
+}
+
+// New returns a new Recorder. The Recorder dumps the events to the output on SIGUSR1 until ctx is done.
+func New(ctx context.Context, opts ...Option) *Recorder {
+	r := &Recorder{
+		ctx:    ctx,
+		clk:    clock.FromContext(ctx),
+		output: os.Stderr,
+		events: make([]*Event, defaultSize),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	r.dumpOnSignal(ctx)
+
+	return r
+}
+
+// Events returns the recorded events of the connection with the given id, or all the recorded events if id is empty.
+// The events are ordered by time.
+func (r *Recorder) Events(id string) []*Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var events []*Event
+	for i := range r.events {
+		event := r.events[(r.next+i)%len(r.events)]
+		if event != nil && (id == "" || event.ID == id) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Dump writes the recorded events of the connection with the given id, or all the recorded events if id is empty, to
+// w as JSON lines
+func (r *Recorder) Dump(w io.Writer, id string) error {
+	encoder := json.NewEncoder(w)
+	for _, event := range r.Events(id) {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServeHTTP dumps the recorded events, the connection is selected by "id" query parameter
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	_ = r.Dump(w, req.URL.Query().Get("id"))
+}
+
+func (r *Recorder) record(event *Event) {
+	if r.store(event) {
+		r.dumpAsync(fmt.Sprintf("%d errors within %s", r.errorCount, r.errorWindow))
+	}
+}
+
+// store stores the event and returns true if the errors threshold is crossed
+func (r *Recorder) store(event *Event) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events[r.next] = event
+	r.next = (r.next + 1) % len(r.events)
+
+	if event.Error == "" || r.errorCount <= 0 {
+		return false
+	}
+	r.errors = append(r.errors, event.Time)
+	for event.Time.Sub(r.errors[0]) > r.errorWindow {
+		r.errors = r.errors[1:]
+	}
+	if len(r.errors) < r.errorCount {
+		return false
+	}
+	r.errors = nil
+	return true
+}
+
+// dumpAsync dumps all the recorded events to the output in the executor
+func (r *Recorder) dumpAsync(reason string) {
+	r.executor.AsyncExec(func() {
+		_, _ = fmt.Fprintf(r.output, "# flight recorder dump: %s\n", reason)
+		if err := r.Dump(r.output, ""); err != nil {
+			log.FromContext(r.ctx).Errorf("failed to dump the flight recorder events: %s", err.Error())
+		}
+	})
+}
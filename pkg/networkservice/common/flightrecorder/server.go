@@ -0,0 +1,52 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flightrecorder
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type flightRecorderServer struct {
+	recorder *Recorder
+}
+
+// NewServer returns a new flight recorder server chain element recording the events to recorder
+func NewServer(recorder *Recorder) networkservice.NetworkServiceServer {
+	return &flightRecorderServer{
+		recorder: recorder,
+	}
+}
+
+func (s *flightRecorderServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return recordRequest(ctx, s.recorder, false, request, func() (*networkservice.Connection, error) {
+		return next.Server(ctx).Request(ctx, request)
+	})
+}
+
+func (s *flightRecorderServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	var rv *empty.Empty
+	err := recordClose(ctx, s.recorder, false, conn, func() (err error) {
+		rv, err = next.Server(ctx).Close(ctx, conn)
+		return err
+	})
+	return rv, err
+}
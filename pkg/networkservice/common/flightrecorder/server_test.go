@@ -0,0 +1,269 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flightrecorder_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/flightrecorder"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/checks/checkrequest"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+)
+
+type syncBuffer struct {
+	mu sync.Mutex
+	sb strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sb.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sb.String()
+}
+
+func request(id string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             id,
+			NetworkService: "ns",
+		},
+	}
+}
+
+func TestFlightRecorder_RequestClose(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := clockmock.New(ctx)
+	recorder := flightrecorder.New(clock.WithClock(ctx, clk))
+
+	server := next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		flightrecorder.NewServer(recorder),
+		checkrequest.NewServer(t, func(*testing.T, *networkservice.NetworkServiceRequest) {
+			clk.Add(time.Second)
+		}),
+		injecterror.NewServer(injecterror.WithRequestErrorTimes(2), injecterror.WithCloseErrorTimes()),
+	)
+
+	conn, err := server.Request(ctx, request("id"))
+	require.NoError(t, err)
+
+	req := request("id")
+	req.Connection = conn.Clone()
+	req.Connection.Labels = map[string]string{"key": "value"}
+	conn, err = server.Request(ctx, req)
+	require.NoError(t, err)
+
+	_, err = server.Request(ctx, req)
+	require.Error(t, err)
+
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+
+	_, err = server.Request(ctx, request("other"))
+	require.NoError(t, err)
+
+	events := recorder.Events("id")
+	require.Len(t, events, 4)
+
+	require.Equal(t, "Request", events[0].Method)
+	require.Equal(t, time.Second, events[0].Duration)
+	require.Equal(t, "ns", events[0].Request["connection"].(map[string]interface{})["network_service"])
+	require.NotEmpty(t, events[0].Response)
+	require.Empty(t, events[0].Error)
+
+	require.Equal(t, map[string]interface{}{
+		"connection": map[string]interface{}{
+			"labels": map[string]interface{}{"+key": "value"},
+		},
+	}, events[1].Request)
+	require.Equal(t, map[string]interface{}{
+		"labels": map[string]interface{}{"+key": "value"},
+	}, events[1].Response)
+
+	require.Empty(t, events[2].Request)
+	require.Empty(t, events[2].Response)
+	require.NotEmpty(t, events[2].Error)
+
+	require.Equal(t, "Close", events[3].Method)
+	require.Empty(t, events[3].Request)
+	require.Empty(t, events[3].Error)
+
+	require.Len(t, recorder.Events(""), 5)
+}
+
+func TestFlightRecorder_Tokens(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	recorder := flightrecorder.New(ctx)
+	server := next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		flightrecorder.NewServer(recorder),
+	)
+
+	req := request("id")
+	req.GetConnection().Path = &networkservice.Path{
+		PathSegments: []*networkservice.PathSegment{{Name: "nsc", Token: "secret"}},
+	}
+	conn, err := server.Request(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "secret", conn.GetPath().GetPathSegments()[0].GetToken())
+
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+
+	output := new(strings.Builder)
+	require.NoError(t, recorder.Dump(output, "id"))
+	require.Contains(t, output.String(), `"nsc"`)
+	require.NotContains(t, output.String(), "secret")
+}
+
+func TestFlightRecorder_Size(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	recorder := flightrecorder.New(ctx, flightrecorder.WithSize(3))
+	server := next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		flightrecorder.NewServer(recorder),
+	)
+
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		_, err := server.Request(ctx, request(id))
+		require.NoError(t, err)
+	}
+
+	var ids []string
+	for _, event := range recorder.Events("") {
+		ids = append(ids, event.ID)
+	}
+	require.Equal(t, []string{"3", "4", "5"}, ids)
+}
+
+func TestFlightRecorder_ErrorThreshold(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := clockmock.New(ctx)
+	output := new(syncBuffer)
+	recorder := flightrecorder.New(clock.WithClock(ctx, clk),
+		flightrecorder.WithErrorThreshold(2, time.Minute),
+		flightrecorder.WithOutput(output),
+	)
+
+	client := next.NewNetworkServiceClient(
+		metadata.NewClient(),
+		flightrecorder.NewClient(recorder),
+		injecterror.NewClient(injecterror.WithError(errors.New("error"))),
+	)
+
+	_, err := client.Request(ctx, request("1"))
+	require.Error(t, err)
+
+	// The first error is out of the window
+	clk.Add(2 * time.Minute)
+	_, err = client.Request(ctx, request("2"))
+	require.Error(t, err)
+	require.Empty(t, output.String())
+
+	_, err = client.Request(ctx, request("3"))
+	require.Error(t, err)
+
+	// The events are dumped out of the Request call
+	require.Eventually(t, func() bool {
+		return strings.Count(output.String(), "\n") == 4
+	}, time.Second, 10*time.Millisecond)
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, "# flight recorder dump: 2 errors within 1m0s", lines[0])
+	require.Contains(t, lines[3], `"id":"3"`)
+	require.Contains(t, lines[3], `"client":true`)
+	require.Contains(t, lines[3], `"error":"error"`)
+}
+
+func TestFlightRecorder_ServeHTTP(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	recorder := flightrecorder.New(ctx)
+	server := next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		flightrecorder.NewServer(recorder),
+	)
+	for _, id := range []string{"1", "2", "1"} {
+		_, err := server.Request(ctx, request(id))
+		require.NoError(t, err)
+	}
+
+	httpServer := httptest.NewServer(recorder)
+	defer httpServer.Close()
+
+	client := &http.Client{Timeout: time.Second}
+	get := func(query string) []string {
+		resp, err := client.Get(httpServer.URL + query)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var lines []string
+		for scanner := bufio.NewScanner(resp.Body); scanner.Scan(); {
+			lines = append(lines, scanner.Text())
+		}
+		return lines
+	}
+
+	require.Len(t, get(""), 3)
+	lines := get("?id=1")
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		require.Contains(t, line, `"id":"1"`)
+	}
+}
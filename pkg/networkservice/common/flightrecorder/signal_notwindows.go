@@ -0,0 +1,82 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package flightrecorder
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// signalHandler is the process wide SIGUSR1 handler dumping all the Recorders. It is started with the first Recorder
+// and is stopped with the last one.
+var signalHandler struct {
+	mu        sync.Mutex
+	recorders map[*Recorder]struct{}
+	stop      chan struct{}
+}
+
+func (r *Recorder) dumpOnSignal(ctx context.Context) {
+	signalHandler.mu.Lock()
+	defer signalHandler.mu.Unlock()
+
+	if len(signalHandler.recorders) == 0 {
+		signalHandler.recorders = make(map[*Recorder]struct{})
+		signalHandler.stop = make(chan struct{})
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGUSR1)
+		go handleSignals(signals, signalHandler.recorders, signalHandler.stop)
+	}
+	signalHandler.recorders[r] = struct{}{}
+
+	recorders, stop := signalHandler.recorders, signalHandler.stop
+	go func() {
+		<-ctx.Done()
+
+		signalHandler.mu.Lock()
+		defer signalHandler.mu.Unlock()
+
+		delete(recorders, r)
+		if len(recorders) == 0 {
+			close(stop)
+		}
+	}()
+}
+
+// handleSignals dumps the recorders on SIGUSR1 until stop is closed, recorders are accessed under signalHandler.mu
+func handleSignals(signals chan os.Signal, recorders map[*Recorder]struct{}, stop <-chan struct{}) {
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-signals:
+			signalHandler.mu.Lock()
+			for r := range recorders {
+				r.dumpAsync("SIGUSR1")
+			}
+			signalHandler.mu.Unlock()
+		}
+	}
+}
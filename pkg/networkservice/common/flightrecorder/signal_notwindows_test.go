@@ -0,0 +1,83 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package flightrecorder_test
+
+import (
+	"context"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/flightrecorder"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+func TestFlightRecorder_SIGUSR1(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	output := new(syncBuffer)
+	recorder := flightrecorder.New(ctx, flightrecorder.WithOutput(output))
+	server := next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		flightrecorder.NewServer(recorder),
+	)
+	_, err := server.Request(ctx, request("id"))
+	require.NoError(t, err)
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	require.Eventually(t, func() bool {
+		return strings.HasPrefix(output.String(), "# flight recorder dump: SIGUSR1\n")
+	}, time.Second, 10*time.Millisecond)
+	require.Contains(t, output.String(), `"id":"id"`)
+}
+
+func TestFlightRecorder_SIGUSR1Shared(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var outputs []*syncBuffer
+	for i := 0; i < 2; i++ {
+		output := new(syncBuffer)
+		flightrecorder.New(ctx, flightrecorder.WithOutput(output))
+		outputs = append(outputs, output)
+	}
+
+	// Every Recorder is dumped once on the single signal
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	for _, output := range outputs {
+		output := output
+		require.Eventually(t, func() bool {
+			return output.String() == "# flight recorder dump: SIGUSR1\n"
+		}, time.Second, 10*time.Millisecond)
+	}
+	require.Never(t, func() bool {
+		return outputs[0].String() != "# flight recorder dump: SIGUSR1\n"
+	}, 100*time.Millisecond, 10*time.Millisecond)
+}
@@ -86,6 +86,7 @@ func (h *healClient) Request(ctx context.Context, request *networkservice.Networ
 		_, _ = next.Client(closeCtx).Close(closeCtx, conn)
 		return nil, eventLoopErr
 	}
+	notify(ctx, conn, networkservice.State_UP)
 	return conn, nil
 }
 
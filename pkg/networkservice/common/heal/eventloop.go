@@ -162,6 +162,7 @@ func (cev *eventLoop) eventLoop() {
 	if canceled {
 		return
 	}
+	notify(cev.chainCtx, cev.conn, networkservice.State_DOWN)
 
 	for {
 		select {
@@ -0,0 +1,42 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heal
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+type observerKey struct{}
+
+// Observer - callback notified with State_DOWN when heal detects the connection is down and with State_UP and the
+// resulting connection after every successful Request passing heal: the initial one, refreshes and heal
+// reconnects. It is called from the heal chain element and the heal event loop and must not block.
+type Observer func(conn *networkservice.Connection, state networkservice.State)
+
+// WithObserver - returns a new context with the observer. Set it on the Request context to get notified about
+// healing of the connection.
+func WithObserver(ctx context.Context, observer Observer) context.Context {
+	return context.WithValue(ctx, observerKey{}, observer)
+}
+
+func notify(ctx context.Context, conn *networkservice.Connection, state networkservice.State) {
+	if observer, ok := ctx.Value(observerKey{}).(Observer); ok && observer != nil {
+		observer(conn.Clone(), state)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loglevels
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/log/sloglogger"
+)
+
+type logLevelsServer struct {
+	levels *sloglogger.Levels
+}
+
+// NewServer - returns a new NetworkServiceServer setting the connection "id" field to the context logger, so the level
+// set with levels.SetConnection applies to the logs of the next chain elements. The level of the connection is deleted
+// from levels on Close, so the levels don't outlive the connections.
+func NewServer(levels *sloglogger.Levels) networkservice.NetworkServiceServer {
+	return &logLevelsServer{
+		levels: levels,
+	}
+}
+
+func (s *logLevelsServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	ctx = log.WithLog(ctx, log.FromContext(ctx).WithField("id", request.GetConnection().GetId()))
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *logLevelsServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	defer s.levels.DeleteConnection(conn.GetId())
+
+	ctx = log.WithLog(ctx, log.FromContext(ctx).WithField("id", conn.GetId()))
+	return next.Server(ctx).Close(ctx, conn)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loglevels_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/loglevels"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/checks/checkcontext"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/log/sloglogger"
+)
+
+func TestLogLevels_DeleteOnClose(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	levels := sloglogger.NewLevels(slog.LevelInfo)
+	levels.SetConnection("conn-1", slog.LevelDebug)
+	levels.SetConnection("conn-2", slog.LevelDebug)
+
+	server := chain.NewNetworkServiceServer(
+		loglevels.NewServer(levels),
+	)
+
+	conn, err := server.Request(context.Background(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-1"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, slog.LevelDebug, levels.Level("", "conn-1"))
+
+	_, err = server.Close(context.Background(), conn)
+	require.NoError(t, err)
+	require.Equal(t, slog.LevelInfo, levels.Level("", "conn-1"))
+	require.Equal(t, slog.LevelDebug, levels.Level("", "conn-2"))
+	require.Equal(t, slog.LevelDebug, levels.Minimum())
+}
+
+func TestLogLevels_DeleteOnCloseError(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	levels := sloglogger.NewLevels(slog.LevelInfo)
+	levels.SetConnection("conn-1", slog.LevelDebug)
+
+	server := chain.NewNetworkServiceServer(
+		loglevels.NewServer(levels),
+		injecterror.NewServer(injecterror.WithRequestErrorTimes(), injecterror.WithCloseErrorTimes(0)),
+	)
+
+	_, err := server.Close(context.Background(), &networkservice.Connection{Id: "conn-1"})
+	require.Error(t, err)
+	require.Equal(t, slog.LevelInfo, levels.Level("", "conn-1"))
+	require.Equal(t, slog.LevelInfo, levels.Minimum())
+}
+
+func TestLogLevels_AppliesToChainLogs(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	levels := sloglogger.NewLevels(slog.LevelInfo)
+	levels.SetConnection("conn-1", slog.LevelDebug)
+
+	out := new(bytes.Buffer)
+	ctx := log.WithLog(context.Background(), sloglogger.New(context.Background(), sloglogger.WithOutput(out), sloglogger.WithLevels(levels)))
+
+	// The chain is not traced, so the context logger is not replaced with the global one
+	server := next.NewNetworkServiceServer(
+		loglevels.NewServer(levels),
+		checkcontext.NewServer(t, func(_ *testing.T, ctx context.Context) {
+			log.FromContext(ctx).Debugf("chain element message")
+		}),
+	)
+
+	for _, id := range []string{"conn-1", "conn-2"} {
+		_, err := server.Request(ctx, &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{Id: id},
+		})
+		require.NoError(t, err)
+	}
+
+	// Only the connection with the debug level has its debug messages logged
+	require.Equal(t, 1, bytes.Count(out.Bytes(), []byte("chain element message")))
+	require.Contains(t, out.String(), "conn-1")
+}
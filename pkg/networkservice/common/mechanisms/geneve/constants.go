@@ -0,0 +1,45 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package geneve provides helpers for the GENEVE tunnel mechanism
+package geneve
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/common"
+)
+
+const (
+	// MECHANISM string
+	MECHANISM = "GENEVE"
+
+	// Mechanism parameters
+
+	// SrcIP - source IP
+	SrcIP = common.SrcIP
+	// DstIP - destination IP
+	DstIP = common.DstIP
+	// SrcPort - Source geneve listening port
+	SrcPort = common.SrcPort
+	// DstPort - Destination geneve listening port
+	DstPort = common.DstPort
+
+	// VNI - vni
+	VNI = "vni"
+	// MTU - maximum transmission unit
+	MTU = common.MTU
+	// Options - geneve option TLVs, see TLV
+	Options = "geneve_options"
+)
@@ -0,0 +1,236 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geneve
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/tunnel"
+)
+
+// maxTLVDataLen - max length of the option data, the option length field counts 4-byte words in 5 bits
+const maxTLVDataLen = 31 * 4
+
+// Mechanism is a GENEVE mechanism helper
+type Mechanism struct {
+	*networkservice.Mechanism
+}
+
+// TLV is a GENEVE option
+type TLV struct {
+	Class uint16
+	Type  uint8
+	Data  []byte
+}
+
+// Validate checks that TLV can be encoded into the GENEVE header
+func (t *TLV) Validate() error {
+	if len(t.Data)%4 != 0 || len(t.Data) > maxTLVDataLen {
+		return errors.Errorf("geneve option data length should be a multiple of 4 up to %d: %d", maxTLVDataLen, len(t.Data))
+	}
+	return nil
+}
+
+func (t *TLV) String() string {
+	return fmt.Sprintf("%d:%d:%s", t.Class, t.Type, hex.EncodeToString(t.Data))
+}
+
+// ToMechanism - convert unified mechanism to helper type
+func ToMechanism(m *networkservice.Mechanism) *Mechanism {
+	if m.GetType() == MECHANISM {
+		if m.Parameters == nil {
+			m.Parameters = map[string]string{}
+		}
+		return &Mechanism{
+			m,
+		}
+	}
+	return nil
+}
+
+// GetParameters returns the map of all parameters to the mechanism
+func (m *Mechanism) GetParameters() map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	if m.Parameters == nil {
+		m.Parameters = map[string]string{}
+	}
+	return m.Parameters
+}
+
+// SrcIP returns the SrcIP parameter of the Mechanism
+func (m *Mechanism) SrcIP() net.IP {
+	return net.ParseIP(m.GetParameters()[SrcIP])
+}
+
+// SetSrcIP sets the SrcIP parameter of the Mechanism
+func (m *Mechanism) SetSrcIP(ip net.IP) *Mechanism {
+	if m == nil {
+		return nil
+	}
+	m.GetParameters()[SrcIP] = ip.String()
+	return m
+}
+
+// DstIP returns the DstIP parameter of the Mechanism
+func (m *Mechanism) DstIP() net.IP {
+	return net.ParseIP(m.GetParameters()[DstIP])
+}
+
+// SetDstIP sets the DstIP parameter of the Mechanism
+func (m *Mechanism) SetDstIP(ip net.IP) *Mechanism {
+	if m == nil {
+		return nil
+	}
+	m.GetParameters()[DstIP] = ip.String()
+	return m
+}
+
+// SrcPort returns the SrcPort parameter of the Mechanism
+func (m *Mechanism) SrcPort() uint16 {
+	return parseUint16(m.GetParameters()[SrcPort])
+}
+
+// SetSrcPort sets the SrcPort parameter of the Mechanism
+func (m *Mechanism) SetSrcPort(port uint16) *Mechanism {
+	if m == nil {
+		return nil
+	}
+	m.GetParameters()[SrcPort] = strconv.FormatUint(uint64(port), 10)
+	return m
+}
+
+// DstPort returns the DstPort parameter of the Mechanism
+func (m *Mechanism) DstPort() uint16 {
+	return parseUint16(m.GetParameters()[DstPort])
+}
+
+// SetDstPort sets the DstPort parameter of the Mechanism
+func (m *Mechanism) SetDstPort(port uint16) *Mechanism {
+	if m == nil {
+		return nil
+	}
+	m.GetParameters()[DstPort] = strconv.FormatUint(uint64(port), 10)
+	return m
+}
+
+// VNI returns the VNI parameter of the Mechanism
+func (m *Mechanism) VNI() uint32 {
+	vni, err := strconv.ParseUint(m.GetParameters()[VNI], 10, 24)
+	if err != nil {
+		return 0
+	}
+	return uint32(vni)
+}
+
+// SetVNI sets the VNI parameter of the Mechanism
+func (m *Mechanism) SetVNI(vni uint32) *Mechanism {
+	if m == nil {
+		return nil
+	}
+	m.GetParameters()[VNI] = strconv.FormatUint(uint64(vni), 10)
+	return m
+}
+
+// EvenVNI returns true if the VNI generated for the Mechanism should be even
+func (m *Mechanism) EvenVNI() bool {
+	return tunnel.EvenTunnelID(m.GetParameters())
+}
+
+// MTU returns the MTU parameter of the Mechanism
+func (m *Mechanism) MTU() uint32 {
+	mtu, err := strconv.ParseUint(m.GetParameters()[MTU], 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(mtu)
+}
+
+// SetMTU sets the MTU parameter of the Mechanism
+func (m *Mechanism) SetMTU(mtu uint32) *Mechanism {
+	if m == nil {
+		return nil
+	}
+	m.GetParameters()[MTU] = strconv.FormatUint(uint64(mtu), 10)
+	return m
+}
+
+// Options returns the option TLVs of the Mechanism
+func (m *Mechanism) Options() ([]*TLV, error) {
+	value := m.GetParameters()[Options]
+	if value == "" {
+		return nil, nil
+	}
+	var rv []*TLV
+	for _, option := range strings.Split(value, ",") {
+		fields := strings.Split(option, ":")
+		if len(fields) != 3 {
+			return nil, errors.Errorf("invalid geneve option: %q", option)
+		}
+		class, err := strconv.ParseUint(fields[0], 10, 16)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid geneve option class: %q", option)
+		}
+		optionType, err := strconv.ParseUint(fields[1], 10, 8)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid geneve option type: %q", option)
+		}
+		data, err := hex.DecodeString(fields[2])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid geneve option data: %q", option)
+		}
+		rv = append(rv, &TLV{Class: uint16(class), Type: uint8(optionType), Data: data})
+	}
+	return rv, nil
+}
+
+// SetOptions sets the option TLVs of the Mechanism
+func (m *Mechanism) SetOptions(options ...*TLV) (*Mechanism, error) {
+	if m == nil {
+		return nil, nil
+	}
+	values := make([]string, 0, len(options))
+	for _, option := range options {
+		if err := option.Validate(); err != nil {
+			return nil, err
+		}
+		values = append(values, option.String())
+	}
+	if len(values) == 0 {
+		delete(m.GetParameters(), Options)
+		return m, nil
+	}
+	m.GetParameters()[Options] = strings.Join(values, ",")
+	return m, nil
+}
+
+func parseUint16(s string) uint16 {
+	u, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0
+	}
+	return uint16(u)
+}
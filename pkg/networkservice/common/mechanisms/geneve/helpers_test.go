@@ -0,0 +1,54 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geneve_test
+
+import (
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/geneve"
+)
+
+func TestMechanism_Options(t *testing.T) {
+	mechanism := geneve.ToMechanism(&networkservice.Mechanism{Type: geneve.MECHANISM})
+
+	options, err := mechanism.Options()
+	require.NoError(t, err)
+	require.Empty(t, options)
+
+	expected := []*geneve.TLV{
+		{Class: 0x0102, Type: 0x80, Data: []byte{1, 2, 3, 4}},
+		{Class: 0xffff, Type: 1},
+	}
+	_, err = mechanism.SetOptions(expected...)
+	require.NoError(t, err)
+
+	options, err = mechanism.Options()
+	require.NoError(t, err)
+	require.Equal(t, expected[0], options[0])
+	require.Equal(t, expected[1].Class, options[1].Class)
+	require.Empty(t, options[1].Data)
+
+	_, err = mechanism.SetOptions(&geneve.TLV{Data: []byte{1, 2, 3}})
+	require.Error(t, err)
+
+	mechanism.GetParameters()[geneve.Options] = "1:2"
+	_, err = mechanism.Options()
+	require.Error(t, err)
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vni
+
+import "github.com/networkservicemesh/sdk/pkg/tools/tunnelid"
+
+const genevePort = 6081
+
+// Option is an option pattern for vni server/client
+type Option func(o *options)
+
+// WithTunnelPort sets GENEVE port
+func WithTunnelPort(tunnelPort uint16) Option {
+	return func(o *options) {
+		if tunnelPort != 0 {
+			o.tunnelPort = tunnelPort
+		}
+	}
+}
+
+// WithAllocator sets allocator of the VNIs, so it can be shared with the other tunnel mechanisms or persisted
+func WithAllocator(allocator *tunnelid.Allocator) Option {
+	return func(o *options) {
+		o.allocator = allocator
+	}
+}
+
+type options struct {
+	tunnelPort uint16
+	allocator  *tunnelid.Allocator
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		tunnelPort: genevePort,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.allocator == nil {
+		o.allocator = tunnelid.NewAllocator()
+	}
+	return o
+}
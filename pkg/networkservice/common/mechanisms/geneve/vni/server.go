@@ -0,0 +1,94 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vni
+
+import (
+	"context"
+	"net"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/geneve"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/tunnel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/tunnelid"
+)
+
+// Space is the GENEVE VNI space
+var Space = &tunnelid.Space{Name: geneve.MECHANISM, Min: 1, Max: 1<<24 - 1}
+
+type vniServer struct {
+	tunnelIP   net.IP
+	tunnelPort uint16
+	ids        networkservice.NetworkServiceServer
+}
+
+// NewServer - set the DstIP *and* VNI for the geneve mechanism
+func NewServer(tunnelIP net.IP, opts ...Option) networkservice.NetworkServiceServer {
+	o := newOptions(opts)
+	return &vniServer{
+		tunnelIP:   tunnelIP,
+		tunnelPort: o.tunnelPort,
+		ids:        tunnel.NewIDServer(Space, o.allocator, toMechanism),
+	}
+}
+
+func (v *vniServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	mechanism := geneve.ToMechanism(request.GetConnection().GetMechanism())
+	if mechanism == nil {
+		return next.Server(ctx).Request(ctx, request)
+	}
+	mechanism.SetDstIP(v.tunnelIP).SetDstPort(v.tunnelPort)
+
+	log.FromContext(ctx).
+		WithField("GeneveVNIServer", "request").
+		WithField("mechanism.DstIP", mechanism.DstIP()).
+		WithField("mechanism.DstPort", mechanism.DstPort()).
+		Debugf("set mechanism dst")
+
+	return v.ids.Request(ctx, request)
+}
+
+func (v *vniServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return v.ids.Close(ctx, conn)
+}
+
+type vniMechanism struct {
+	*geneve.Mechanism
+}
+
+func toMechanism(m *networkservice.Mechanism) tunnel.Mechanism {
+	if mechanism := geneve.ToMechanism(m); mechanism != nil {
+		return &vniMechanism{Mechanism: mechanism}
+	}
+	return nil
+}
+
+func (m *vniMechanism) TunnelID() uint32 {
+	return m.VNI()
+}
+
+func (m *vniMechanism) SetTunnelID(id uint32) {
+	m.SetVNI(id)
+}
+
+func (m *vniMechanism) EvenTunnelID() bool {
+	return m.EvenVNI()
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vni_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/vxlan"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/geneve"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/geneve/vni"
+	vxlanvni "github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/vxlan/vni"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/tunnelid"
+)
+
+func TestVNIServer_SharedAllocator(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	allocator := tunnelid.NewAllocator()
+	c := next.NewNetworkServiceClient(
+		metadata.NewClient(),
+		vni.NewClient(net.ParseIP("192.0.2.1")),
+		vxlanvni.NewClient(net.ParseIP("192.0.2.1")),
+		adapters.NewServerToClient(next.NewNetworkServiceServer(
+			metadata.NewServer(),
+			mechanisms.NewServer(map[string]networkservice.NetworkServiceServer{
+				geneve.MECHANISM: vni.NewServer(net.ParseIP("192.0.2.2"), vni.WithAllocator(allocator)),
+				vxlan.MECHANISM:  vxlanvni.NewServer(net.ParseIP("192.0.2.2"), vxlanvni.WithAllocator(allocator)),
+			}),
+		)),
+	)
+
+	var conns []*networkservice.Connection
+	for _, mechanismType := range []string{geneve.MECHANISM, vxlan.MECHANISM} {
+		conn, err := c.Request(context.Background(), &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{Id: mechanismType},
+			MechanismPreferences: []*networkservice.Mechanism{
+				{Cls: cls.REMOTE, Type: mechanismType},
+			},
+		})
+		require.NoError(t, err)
+		conns = append(conns, conn)
+	}
+
+	mechanism := geneve.ToMechanism(conns[0].GetMechanism())
+	require.NotNil(t, mechanism)
+	require.Equal(t, "192.0.2.1", mechanism.SrcIP().String())
+	require.Equal(t, "192.0.2.2", mechanism.DstIP().String())
+	require.Equal(t, uint16(6081), mechanism.DstPort())
+	require.NotZero(t, mechanism.VNI())
+	// 192.0.2.1 < 192.0.2.2
+	require.Zero(t, mechanism.VNI()%2)
+
+	require.Len(t, allocator.Allocations(), 2)
+
+	for _, conn := range conns {
+		_, err := c.Close(context.Background(), conn)
+		require.NoError(t, err)
+	}
+	require.Empty(t, allocator.Allocations())
+}
+
+func TestVNIServer_ClaimInUse(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	allocator := tunnelid.NewAllocator()
+	s := next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		vni.NewServer(net.ParseIP("192.0.2.2"), vni.WithAllocator(allocator)),
+	)
+	owners := func() (rv []string) {
+		for _, allocation := range allocator.Allocations() {
+			rv = append(rv, allocation.Owner)
+		}
+		return rv
+	}
+
+	request := func(id string) *networkservice.NetworkServiceRequest {
+		mechanism := &networkservice.Mechanism{Cls: cls.REMOTE, Type: geneve.MECHANISM}
+		geneve.ToMechanism(mechanism).SetSrcIP(net.ParseIP("192.0.2.1")).SetVNI(42)
+		return &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{Id: id, Mechanism: mechanism},
+		}
+	}
+
+	conn, err := s.Request(context.Background(), request("conn-1"))
+	require.NoError(t, err)
+
+	// Refresh keeps the VNI
+	_, err = s.Request(context.Background(), request("conn-1"))
+	require.NoError(t, err)
+
+	// The VNI selected by the peer is accepted, but stays allocated by its owner
+	_, err = s.Request(context.Background(), request("conn-2"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"conn-1"}, owners())
+
+	_, err = s.Close(context.Background(), conn)
+	require.NoError(t, err)
+
+	_, err = s.Request(context.Background(), request("conn-2"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"conn-2"}, owners())
+}
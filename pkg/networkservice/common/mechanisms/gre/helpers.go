@@ -0,0 +1,124 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gre
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/tunnel"
+)
+
+// Mechanism is a GRE mechanism helper
+type Mechanism struct {
+	*networkservice.Mechanism
+}
+
+// ToMechanism - convert unified mechanism to helper type
+func ToMechanism(m *networkservice.Mechanism) *Mechanism {
+	if m.GetType() == MECHANISM {
+		if m.Parameters == nil {
+			m.Parameters = map[string]string{}
+		}
+		return &Mechanism{
+			m,
+		}
+	}
+	return nil
+}
+
+// GetParameters returns the map of all parameters to the mechanism
+func (m *Mechanism) GetParameters() map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	if m.Parameters == nil {
+		m.Parameters = map[string]string{}
+	}
+	return m.Parameters
+}
+
+// SrcIP returns the SrcIP parameter of the Mechanism
+func (m *Mechanism) SrcIP() net.IP {
+	return net.ParseIP(m.GetParameters()[SrcIP])
+}
+
+// SetSrcIP sets the SrcIP parameter of the Mechanism
+func (m *Mechanism) SetSrcIP(ip net.IP) *Mechanism {
+	if m == nil {
+		return nil
+	}
+	m.GetParameters()[SrcIP] = ip.String()
+	return m
+}
+
+// DstIP returns the DstIP parameter of the Mechanism
+func (m *Mechanism) DstIP() net.IP {
+	return net.ParseIP(m.GetParameters()[DstIP])
+}
+
+// SetDstIP sets the DstIP parameter of the Mechanism
+func (m *Mechanism) SetDstIP(ip net.IP) *Mechanism {
+	if m == nil {
+		return nil
+	}
+	m.GetParameters()[DstIP] = ip.String()
+	return m
+}
+
+// Key returns the GRE key of the Mechanism
+func (m *Mechanism) Key() uint32 {
+	key, err := strconv.ParseUint(m.GetParameters()[Key], 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(key)
+}
+
+// SetKey sets the GRE key of the Mechanism
+func (m *Mechanism) SetKey(key uint32) *Mechanism {
+	if m == nil {
+		return nil
+	}
+	m.GetParameters()[Key] = strconv.FormatUint(uint64(key), 10)
+	return m
+}
+
+// EvenKey returns true if the key generated for the Mechanism should be even
+func (m *Mechanism) EvenKey() bool {
+	return tunnel.EvenTunnelID(m.GetParameters())
+}
+
+// MTU returns the MTU parameter of the Mechanism
+func (m *Mechanism) MTU() uint32 {
+	mtu, err := strconv.ParseUint(m.GetParameters()[MTU], 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(mtu)
+}
+
+// SetMTU sets the MTU parameter of the Mechanism
+func (m *Mechanism) SetMTU(mtu uint32) *Mechanism {
+	if m == nil {
+		return nil
+	}
+	m.GetParameters()[MTU] = strconv.FormatUint(uint64(mtu), 10)
+	return m
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gre_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/gre"
+)
+
+func TestToMechanism(t *testing.T) {
+	require.Nil(t, gre.ToMechanism(&networkservice.Mechanism{Type: "VXLAN"}))
+	require.NotNil(t, gre.ToMechanism(&networkservice.Mechanism{Type: gre.MECHANISM}).GetParameters())
+}
+
+func TestMechanism_Parameters(t *testing.T) {
+	mechanism := gre.ToMechanism(&networkservice.Mechanism{Type: gre.MECHANISM})
+	require.Zero(t, mechanism.Key())
+	require.Zero(t, mechanism.MTU())
+
+	mechanism.
+		SetSrcIP(net.ParseIP("192.0.2.1")).
+		SetDstIP(net.ParseIP("192.0.2.2")).
+		SetKey(1<<32 - 1).
+		SetMTU(1400)
+
+	require.Equal(t, "192.0.2.1", mechanism.SrcIP().String())
+	require.Equal(t, "192.0.2.2", mechanism.DstIP().String())
+	require.Equal(t, uint32(1<<32-1), mechanism.Key())
+	require.Equal(t, uint32(1400), mechanism.MTU())
+}
+
+func TestMechanism_EvenKey(t *testing.T) {
+	mechanism := gre.ToMechanism(&networkservice.Mechanism{Type: gre.MECHANISM})
+	mechanism.SetSrcIP(net.ParseIP("192.0.2.1")).SetDstIP(net.ParseIP("192.0.2.2"))
+	require.True(t, mechanism.EvenKey())
+
+	mechanism.SetSrcIP(net.ParseIP("192.0.2.3"))
+	require.False(t, mechanism.EvenKey())
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package key
+
+import "github.com/networkservicemesh/sdk/pkg/tools/tunnelid"
+
+// Option is an option pattern for key server
+type Option func(o *options)
+
+// WithAllocator sets allocator of the keys, so it can be shared with the other tunnel mechanisms or persisted
+func WithAllocator(allocator *tunnelid.Allocator) Option {
+	return func(o *options) {
+		o.allocator = allocator
+	}
+}
+
+type options struct {
+	allocator *tunnelid.Allocator
+}
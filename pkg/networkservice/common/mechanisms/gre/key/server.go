@@ -0,0 +1,91 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package key
+
+import (
+	"context"
+	"math"
+	"net"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/gre"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/tunnel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/tunnelid"
+)
+
+// Space is the GRE key space
+var Space = &tunnelid.Space{Name: gre.MECHANISM, Min: 1, Max: math.MaxUint32}
+
+type keyServer struct {
+	tunnelIP net.IP
+	ids      networkservice.NetworkServiceServer
+}
+
+// NewServer - set the DstIP *and* Key for the gre mechanism
+func NewServer(tunnelIP net.IP, opts ...Option) networkservice.NetworkServiceServer {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.allocator == nil {
+		o.allocator = tunnelid.NewAllocator()
+	}
+	return &keyServer{
+		tunnelIP: tunnelIP,
+		ids:      tunnel.NewIDServer(Space, o.allocator, toMechanism),
+	}
+}
+
+func (k *keyServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	mechanism := gre.ToMechanism(request.GetConnection().GetMechanism())
+	if mechanism == nil {
+		return next.Server(ctx).Request(ctx, request)
+	}
+	mechanism.SetDstIP(k.tunnelIP)
+	return k.ids.Request(ctx, request)
+}
+
+func (k *keyServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return k.ids.Close(ctx, conn)
+}
+
+type keyMechanism struct {
+	*gre.Mechanism
+}
+
+func toMechanism(m *networkservice.Mechanism) tunnel.Mechanism {
+	if mechanism := gre.ToMechanism(m); mechanism != nil {
+		return &keyMechanism{Mechanism: mechanism}
+	}
+	return nil
+}
+
+func (m *keyMechanism) TunnelID() uint32 {
+	return m.Key()
+}
+
+func (m *keyMechanism) SetTunnelID(id uint32) {
+	m.SetKey(id)
+}
+
+func (m *keyMechanism) EvenTunnelID() bool {
+	return m.EvenKey()
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package key_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/gre"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/gre/key"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/tunnelid"
+)
+
+func TestKeyServer(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	allocator := tunnelid.NewAllocator()
+	// All odd keys for the peer are used externally, except the one
+	for i := uint32(1); i < 100; i += 2 {
+		if i != 51 {
+			allocator.Reserve(net.ParseIP("192.0.2.2"), key.Space, i)
+		}
+	}
+
+	c := next.NewNetworkServiceClient(
+		metadata.NewClient(),
+		key.NewClient(net.ParseIP("192.0.2.2")),
+		adapters.NewServerToClient(next.NewNetworkServiceServer(
+			metadata.NewServer(),
+			key.NewServer(net.ParseIP("192.0.2.1"), key.WithAllocator(allocator)),
+		)),
+	)
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:        "id",
+			Mechanism: &networkservice.Mechanism{Cls: cls.REMOTE, Type: gre.MECHANISM},
+		},
+	}
+	gre.ToMechanism(request.GetConnection().GetMechanism()).SetSrcIP(net.ParseIP("192.0.2.2"))
+
+	conn, err := c.Request(context.Background(), request)
+	require.NoError(t, err)
+
+	mechanism := gre.ToMechanism(conn.GetMechanism())
+	require.Equal(t, "192.0.2.1", mechanism.DstIP().String())
+	require.NotZero(t, mechanism.Key())
+	// 192.0.2.2 > 192.0.2.1
+	require.Equal(t, uint32(1), mechanism.Key()%2)
+	require.Len(t, allocator.Allocations(), 1)
+
+	_, err = c.Close(context.Background(), conn)
+	require.NoError(t, err)
+	require.Empty(t, allocator.Allocations())
+}
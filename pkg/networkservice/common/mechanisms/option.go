@@ -0,0 +1,32 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechanisms
+
+type options struct {
+	scorers []Scorer
+}
+
+// Option is an option pattern for mechanisms server
+type Option func(o *options)
+
+// WithScorers sets scorers of the mechanism candidates. Candidates are tried in the order of their total score, client
+// order of MechanismPreferences is kept for the candidates with equal scores.
+func WithScorers(scorers ...Scorer) Option {
+	return func(o *options) {
+		o.scorers = append(o.scorers, scorers...)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechanisms
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+const rejectionMetricKeyPrefix = "mechanism_rejected_"
+
+// RejectionReason - reason of the mechanism candidate rejection
+type RejectionReason string
+
+const (
+	// RejectionUnsupported - server has no handler for the mechanism type
+	RejectionUnsupported RejectionReason = "unsupported"
+	// RejectionPolicy - candidate was rejected by the Scorer
+	RejectionPolicy RejectionReason = "policy"
+	// RejectionFailed - handler of the mechanism type has failed the request
+	RejectionFailed RejectionReason = "failed"
+)
+
+// Rejection describes why the mechanism candidate was not selected
+type Rejection struct {
+	// Index of the candidate in MechanismPreferences, there can be several candidates of the same type
+	Index   int
+	Type    string
+	Reason  RejectionReason
+	Message string
+}
+
+func (r *Rejection) String() string {
+	if r.Message == "" {
+		return r.Type + ": " + string(r.Reason)
+	}
+	return r.Type + ": " + string(r.Reason) + ": " + r.Message
+}
+
+// Rejections returns rejected mechanism candidates stored in the current path segment metrics by the mechanisms
+// server, sorted by their index in MechanismPreferences
+func Rejections(conn *networkservice.Connection) []*Rejection {
+	segment := currentSegment(conn)
+	var rv []*Rejection
+	for key, value := range segment.GetMetrics() {
+		index, ok := strings.CutPrefix(key, rejectionMetricKeyPrefix)
+		if !ok {
+			continue
+		}
+		i, err := strconv.Atoi(index)
+		if err != nil {
+			continue
+		}
+		mechanismType, value, _ := strings.Cut(value, ": ")
+		reason, message, _ := strings.Cut(value, ": ")
+		rv = append(rv, &Rejection{
+			Index:   i,
+			Type:    mechanismType,
+			Reason:  RejectionReason(reason),
+			Message: message,
+		})
+	}
+	sort.Slice(rv, func(i, j int) bool { return rv[i].Index < rv[j].Index })
+	return rv
+}
+
+// storeRejections replaces rejections stored in the current path segment metrics
+func storeRejections(conn *networkservice.Connection, rejections []*Rejection) {
+	segment := currentSegment(conn)
+	if segment == nil {
+		return
+	}
+	for key := range segment.GetMetrics() {
+		if strings.HasPrefix(key, rejectionMetricKeyPrefix) {
+			delete(segment.Metrics, key)
+		}
+	}
+	if len(rejections) == 0 {
+		return
+	}
+	if segment.Metrics == nil {
+		segment.Metrics = make(map[string]string)
+	}
+	for _, r := range rejections {
+		segment.Metrics[rejectionMetricKeyPrefix+strconv.Itoa(r.Index)] = r.String()
+	}
+}
+
+func currentSegment(conn *networkservice.Connection) *networkservice.PathSegment {
+	path := conn.GetPath()
+	if int(path.GetIndex()) >= len(path.GetPathSegments()) {
+		return nil
+	}
+	return path.GetPathSegments()[path.GetIndex()]
+}
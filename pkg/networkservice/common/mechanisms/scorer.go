@@ -0,0 +1,67 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechanisms
+
+import (
+	"context"
+	"strings"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
+)
+
+// Scorer scores the mechanism candidate of the request, the candidates with the higher scores are tried first.
+// Returned error rejects the candidate.
+type Scorer func(ctx context.Context, request *networkservice.NetworkServiceRequest, mechanism *networkservice.Mechanism) (int, error)
+
+// CostScorer returns Scorer preferring the mechanism types with the lower cost. Types missing in costs have zero cost.
+func CostScorer(costs map[string]int) Scorer {
+	return func(_ context.Context, _ *networkservice.NetworkServiceRequest, mechanism *networkservice.Mechanism) (int, error) {
+		return -costs[mechanism.GetType()], nil
+	}
+}
+
+// SamePeerMechanismScorer returns Scorer adding weight to the candidate of the same type as the mechanism selected by
+// the previous path segment for its own incoming connection. The mechanisms server of the previous hop stores it in
+// the path segment metrics before passing the request further.
+func SamePeerMechanismScorer(weight int) Scorer {
+	return func(_ context.Context, request *networkservice.NetworkServiceRequest, mechanism *networkservice.Mechanism) (int, error) {
+		path := request.GetConnection().GetPath()
+		if path.GetIndex() == 0 || int(path.GetIndex()) > len(path.GetPathSegments()) {
+			return 0, nil
+		}
+		peerInterface := path.GetPathSegments()[path.GetIndex()-1].GetMetrics()[serverMetricKey]
+		if peerType, _, ok := strings.Cut(peerInterface, "/"); ok && peerType == mechanism.GetType() {
+			return weight, nil
+		}
+		return 0, nil
+	}
+}
+
+// AllowedTypesScorer returns Scorer rejecting all mechanism types except the allowed ones
+func AllowedTypesScorer(types ...string) Scorer {
+	allowed := make(map[string]struct{}, len(types))
+	for _, t := range types {
+		allowed[t] = struct{}{}
+	}
+	return func(_ context.Context, _ *networkservice.NetworkServiceRequest, mechanism *networkservice.Mechanism) (int, error) {
+		if _, ok := allowed[mechanism.GetType()]; !ok {
+			return 0, errors.Errorf("%s is not allowed", mechanism.GetType())
+		}
+		return 0, nil
+	}
+}
@@ -22,16 +22,19 @@ package mechanisms
 
 import (
 	"context"
+	"sort"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/pkg/errors"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
 )
 
 type mechanismsServer struct {
 	mechanisms map[string]networkservice.NetworkServiceServer // key is Mechanism.Type
+	scorers    []Scorer
 }
 
 // NewServer - returns new NetworkServiceServer chain element that will attempt to meet the request.MechanismPreferences using
@@ -41,9 +44,16 @@ type mechanismsServer struct {
 //	      key:    mechanismType
 //	      value:  NetworkServiceServer that only handles the work for the specified mechanismType
 //	              Note: Supplied NetworkServiceServer elements should not call next.Server(ctx).{Request,Close} themselves
-func NewServer(mechanisms map[string]networkservice.NetworkServiceServer) networkservice.NetworkServiceServer {
+//
+// Candidates rejected during the negotiation are stored in the path segment metrics, see Rejections.
+func NewServer(mechanisms map[string]networkservice.NetworkServiceServer, opts ...Option) networkservice.NetworkServiceServer {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
 	rv := &mechanismsServer{
 		mechanisms: make(map[string]networkservice.NetworkServiceServer),
+		scorers:    o.scorers,
 	}
 	for mechanismType, server := range mechanisms {
 		// We wrap in a chain here to make sure that if the 'server' is calling next.Server(ctx) it doesn't
@@ -63,24 +73,82 @@ func (ms *mechanismsServer) Request(ctx context.Context, request *networkservice
 		}
 		return nil, errors.WithStack(errUnsupportedMech)
 	}
+
+	candidates, rejections := ms.candidates(ctx, request)
+
 	var err = errCannotSupportMech
-	for _, mechanism := range request.GetMechanismPreferences() {
-		srv, ok := ms.mechanisms[mechanism.GetType()]
-		if ok {
-			req := request.Clone()
-			req.GetConnection().Mechanism = mechanism
-			var resp *networkservice.Connection
-			resp, respErr := srv.Request(ctx, req)
-			if respErr == nil {
-				storeMetrics(resp, resp.GetMechanism(), false)
-				return resp, nil
-			}
-			err = errors.Wrap(err, respErr.Error())
+	for _, c := range candidates {
+		req := request.Clone()
+		req.GetConnection().Mechanism = c.mechanism
+		storeRejections(req.GetConnection(), rejections)
+		// The selected mechanism is visible to the next hops during the request, see SamePeerMechanismScorer
+		replaceServerMetrics(req.GetConnection(), c.mechanism)
+		resp, respErr := ms.mechanisms[c.mechanism.GetType()].Request(ctx, req)
+		if respErr == nil {
+			replaceServerMetrics(resp, resp.GetMechanism())
+			return resp, nil
 		}
+		err = errors.Wrap(err, respErr.Error())
+		rejections = append(rejections, &Rejection{
+			Index:   c.index,
+			Type:    c.mechanism.GetType(),
+			Reason:  RejectionFailed,
+			Message: respErr.Error(),
+		})
 	}
 	return nil, err
 }
 
+// replaceServerMetrics stores the server interface details overwriting the ones left from the previous negotiation
+func replaceServerMetrics(conn *networkservice.Connection, mechanism *networkservice.Mechanism) {
+	if segment := currentSegment(conn); segment != nil {
+		delete(segment.Metrics, serverMetricKey)
+	}
+	storeMetrics(conn, mechanism, false)
+}
+
+type candidate struct {
+	index     int
+	mechanism *networkservice.Mechanism
+	score     int
+}
+
+// candidates returns supported mechanism preferences ordered by their scores and rejections of the rest
+func (ms *mechanismsServer) candidates(ctx context.Context, request *networkservice.NetworkServiceRequest) (candidates []*candidate, rejections []*Rejection) {
+	logger := log.FromContext(ctx).WithField("mechanismsServer", "Request")
+
+	for i, mechanism := range request.GetMechanismPreferences() {
+		if _, ok := ms.mechanisms[mechanism.GetType()]; !ok {
+			rejections = append(rejections, &Rejection{Index: i, Type: mechanism.GetType(), Reason: RejectionUnsupported})
+			continue
+		}
+		score, err := ms.score(ctx, request, mechanism)
+		if err != nil {
+			logger.Debugf("mechanism %s is rejected by the policy: %s", mechanism.GetType(), err.Error())
+			rejections = append(rejections, &Rejection{Index: i, Type: mechanism.GetType(), Reason: RejectionPolicy, Message: err.Error()})
+			continue
+		}
+		candidates = append(candidates, &candidate{index: i, mechanism: mechanism, score: score})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	return candidates, rejections
+}
+
+func (ms *mechanismsServer) score(ctx context.Context, request *networkservice.NetworkServiceRequest, mechanism *networkservice.Mechanism) (int, error) {
+	var total int
+	for _, scorer := range ms.scorers {
+		score, err := scorer(ctx, request, mechanism)
+		if err != nil {
+			return 0, err
+		}
+		total += score
+	}
+	return total, nil
+}
+
 func (ms *mechanismsServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
 	srv, ok := ms.mechanisms[conn.GetMechanism().GetType()]
 	if ok {
@@ -23,6 +23,7 @@ package mechanisms_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -30,6 +31,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/checks/checkcontext"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/checks/checkrequest"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
 
@@ -45,6 +47,7 @@ import (
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/null"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
 )
@@ -265,3 +268,105 @@ func TestMetrics(t *testing.T) {
 		require.Equal(t, fmt.Sprintf("%s/%s", request.MechanismPreferences[0].Type, ifname), conn.Path.PathSegments[0].Metrics[metricsKey])
 	}
 }
+
+func TestScorersAndRejections(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	s := mechanisms.NewServer(map[string]networkservice.NetworkServiceServer{
+		memif.MECHANISM:  injecterror.NewServer(injecterror.WithError(errors.New("no memif socket"))),
+		kernel.MECHANISM: null.NewServer(),
+		srv6.MECHANISM:   null.NewServer(),
+		vxlan.MECHANISM:  null.NewServer(),
+	},
+		mechanisms.WithScorers(
+			mechanisms.CostScorer(map[string]int{kernel.MECHANISM: 10}),
+			mechanisms.AllowedTypesScorer(memif.MECHANISM, kernel.MECHANISM, vxlan.MECHANISM),
+		),
+	)
+
+	req := request()
+	req.MechanismPreferences = append(req.MechanismPreferences, &networkservice.Mechanism{Type: "NOT_A_TYPE"})
+	req.Connection.Path = &networkservice.Path{
+		PathSegments: []*networkservice.PathSegment{{}},
+	}
+
+	conn, err := s.Request(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, vxlan.MECHANISM, conn.GetMechanism().GetType())
+
+	require.Equal(t, []*mechanisms.Rejection{
+		{Index: 0, Type: memif.MECHANISM, Reason: mechanisms.RejectionFailed, Message: "no memif socket"},
+		{Index: 2, Type: srv6.MECHANISM, Reason: mechanisms.RejectionPolicy, Message: "SRV6 is not allowed"},
+		{Index: 4, Type: "NOT_A_TYPE", Reason: mechanisms.RejectionUnsupported},
+	}, mechanisms.Rejections(conn))
+}
+
+func TestRejectionsOfSameType(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	s := mechanisms.NewServer(map[string]networkservice.NetworkServiceServer{
+		kernel.MECHANISM: chain.NewNetworkServiceServer(
+			injecterror.NewServer(injecterror.WithRequestErrorTimes(0), injecterror.WithError(errors.New("no netns"))),
+			injecterror.NewServer(injecterror.WithRequestErrorTimes(0), injecterror.WithError(errors.New("no netns"))),
+		),
+		vxlan.MECHANISM: null.NewServer(),
+	})
+
+	req := request()
+	req.MechanismPreferences = []*networkservice.Mechanism{
+		{Type: kernel.MECHANISM, Parameters: map[string]string{"name": "nsm-1"}},
+		{Type: kernel.MECHANISM, Parameters: map[string]string{"name": "nsm-2"}},
+		{Type: vxlan.MECHANISM},
+	}
+	req.Connection.Path = &networkservice.Path{
+		PathSegments: []*networkservice.PathSegment{{}},
+	}
+
+	conn, err := s.Request(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, vxlan.MECHANISM, conn.GetMechanism().GetType())
+	require.Len(t, mechanisms.Rejections(conn), 2)
+}
+
+func TestSamePeerMechanismScorer(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	newHop := func(opts ...mechanisms.Option) networkservice.NetworkServiceServer {
+		return mechanisms.NewServer(map[string]networkservice.NetworkServiceServer{
+			memif.MECHANISM:  null.NewServer(),
+			kernel.MECHANISM: null.NewServer(),
+		}, opts...)
+	}
+
+	var peerInterface string
+	s := chain.NewNetworkServiceServer(
+		updatepath.NewServer("hop-1"),
+		newHop(mechanisms.WithScorers(mechanisms.CostScorer(map[string]int{memif.MECHANISM: 10}))),
+		// The next hop negotiates the mechanism of its own connection
+		checkrequest.NewServer(t, func(t *testing.T, request *networkservice.NetworkServiceRequest) {
+			peerInterface = request.GetConnection().GetPath().GetPathSegments()[0].GetMetrics()["server_interface"]
+			request.GetConnection().Mechanism = nil
+		}),
+		updatepath.NewServer("hop-2"),
+		newHop(mechanisms.WithScorers(mechanisms.SamePeerMechanismScorer(1))),
+	)
+
+	req := request()
+	req.MechanismPreferences = req.MechanismPreferences[:2]
+
+	conn, err := s.Request(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(peerInterface, kernel.MECHANISM+"/"))
+	require.True(t, strings.HasPrefix(conn.GetPath().GetPathSegments()[1].GetMetrics()["server_interface"], kernel.MECHANISM+"/"))
+}
+
+func TestCannotSupportMechanismError(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	s := mechanisms.NewServer(map[string]networkservice.NetworkServiceServer{
+		kernel.MECHANISM: injecterror.NewServer(injecterror.WithError(errors.New("no netns"))),
+	})
+
+	_, err := s.Request(context.Background(), request())
+	require.EqualError(t, err, "no netns: cannot support any of the requested mechanism")
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunnel
+
+import (
+	"bytes"
+	"net"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/common"
+)
+
+// EvenTunnelID returns true if the tunnel ID of the mechanism with the given parameters should be even. The same rule
+// as for VXLAN VNIs is used: the peer with the lower original IP generates even IDs.
+func EvenTunnelID(parameters map[string]string) bool {
+	src := originalIP(parameters, common.SrcOriginalIP, common.SrcIP)
+	if src == nil {
+		return true
+	}
+	dst := originalIP(parameters, common.DstOriginalIP, common.DstIP)
+	if dst == nil {
+		return false
+	}
+	return bytes.Compare(src, dst) <= 0
+}
+
+func originalIP(parameters map[string]string, originalKey, key string) net.IP {
+	value, ok := parameters[originalKey]
+	if !ok {
+		value = parameters[key]
+	}
+	return net.ParseIP(value)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunnel
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/tunnelid"
+)
+
+type key struct {
+	space string
+}
+
+func store(ctx context.Context, isClient bool, space *tunnelid.Space, id uint32) {
+	metadata.Map(ctx, isClient).Store(key{space: space.Name}, id)
+}
+
+func del(ctx context.Context, isClient bool, space *tunnelid.Space) {
+	metadata.Map(ctx, isClient).Delete(key{space: space.Name})
+}
+
+func load(ctx context.Context, isClient bool, space *tunnelid.Space) (value uint32, ok bool) {
+	rawValue, ok := metadata.Map(ctx, isClient).Load(key{space: space.Name})
+	if !ok {
+		return
+	}
+	value, ok = rawValue.(uint32)
+	return value, ok
+}
+
+func loadOrStore(ctx context.Context, isClient bool, space *tunnelid.Space, id uint32) (value uint32, ok bool) {
+	rawValue, ok := metadata.Map(ctx, isClient).LoadOrStore(key{space: space.Name}, id)
+	if !ok {
+		return
+	}
+	value, ok = rawValue.(uint32)
+	return value, ok
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tunnel provides the chain element allocating tunnel IDs (VNIs, keys) of the tunnel mechanisms, so the tunnel
+// mechanism packages share the same allocation logic
+package tunnel
+
+import (
+	"context"
+	"net"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/tunnelid"
+)
+
+// Mechanism is a tunnel mechanism with the tunnel ID
+type Mechanism interface {
+	SrcIP() net.IP
+	DstIP() net.IP
+	TunnelID() uint32
+	SetTunnelID(id uint32)
+	// EvenTunnelID returns true if the tunnel ID should be even, so the peers generating IDs for each other don't
+	// collide
+	EvenTunnelID() bool
+}
+
+// MechanismFunc converts networkservice.Mechanism to the tunnel Mechanism, returns nil if it is of the other type
+type MechanismFunc func(m *networkservice.Mechanism) Mechanism
+
+type idServer struct {
+	space       *tunnelid.Space
+	allocator   *tunnelid.Allocator
+	toMechanism MechanismFunc
+}
+
+// NewIDServer - returns a new server chain element allocating tunnel IDs of the space for the mechanisms converted by
+// toMechanism. The ID already set by the client is claimed, otherwise a random free ID is allocated.
+func NewIDServer(space *tunnelid.Space, allocator *tunnelid.Allocator, toMechanism MechanismFunc) networkservice.NetworkServiceServer {
+	return &idServer{
+		space:       space,
+		allocator:   allocator,
+		toMechanism: toMechanism,
+	}
+}
+
+func (s *idServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	logger := log.FromContext(ctx).WithField("tunnelIDServer", "request").WithField("space", s.space.Name)
+
+	mechanism := s.toMechanism(request.GetConnection().GetMechanism())
+	if mechanism == nil {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	owner := request.GetConnection().GetId()
+	isClient := metadata.IsClient(s)
+
+	var loaded bool
+	if id := mechanism.TunnelID(); id != 0 && mechanism.SrcIP() != nil {
+		// If we already have an ID, make sure we remember it, and go on
+		claimed, err := s.allocator.Claim(mechanism.SrcIP(), s.space, id, owner)
+		if err != nil {
+			return nil, err
+		}
+		if !claimed {
+			logger.WithField("tunnelID", id).Warnf("tunnel ID is reserved or already in use by another connection")
+		}
+		_, loaded = loadOrStore(ctx, isClient, s.space, id)
+		logger.WithField("tunnelID", id).Debugf("claimed tunnel ID")
+	} else if id, ok := load(ctx, isClient, s.space); ok {
+		loaded = true
+		mechanism.SetTunnelID(id)
+		logger.WithField("tunnelID", id).Debugf("tunnel ID loaded from metadata")
+	} else {
+		if mechanism.SrcIP() == nil || mechanism.DstIP() == nil {
+			return nil, errors.Errorf("both srcIP(%s) and dstIP(%s) must be non-nil", mechanism.SrcIP(), mechanism.DstIP())
+		}
+		even := mechanism.EvenTunnelID()
+		id, err := s.allocator.Allocate(mechanism.SrcIP(), s.space, owner, func(id uint32) bool {
+			return (id%2 == 0) == even
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to allocate %s tunnel ID", s.space.Name)
+		}
+		mechanism.SetTunnelID(id)
+		store(ctx, isClient, s.space, id)
+		logger.WithField("tunnelID", id).Debugf("tunnel ID allocated and stored in metadata")
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil && !loaded {
+		del(ctx, isClient, s.space)
+		_ = s.allocator.Release(mechanism.SrcIP(), s.space, mechanism.TunnelID(), owner)
+
+		logger.WithField("tunnelID", mechanism.TunnelID()).Errorf("error returned from request, releasing tunnel ID. err=%v", err.Error())
+	}
+	return conn, err
+}
+
+func (s *idServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if mechanism := s.toMechanism(conn.GetMechanism()); mechanism != nil {
+		logger := log.FromContext(ctx).WithField("tunnelIDServer", "close").WithField("space", s.space.Name)
+
+		del(ctx, metadata.IsClient(s), s.space)
+		if mechanism.TunnelID() != 0 && mechanism.SrcIP() != nil {
+			if err := s.allocator.Release(mechanism.SrcIP(), s.space, mechanism.TunnelID(), conn.GetId()); err != nil {
+				logger.Warnf("failed to release tunnel ID: %s", err.Error())
+			}
+		}
+
+		// IDs of the connection which are still allocated are leaked by the previous requests
+		leaked, err := s.allocator.ReleaseOwner(s.space, conn.GetId())
+		for _, k := range leaked {
+			logger.WithField("peer", k.PeerIP).WithField("tunnelID", k.ID).Warnf("released leaked tunnel ID")
+		}
+		if err != nil {
+			logger.Warnf("failed to release leaked tunnel IDs: %s", err.Error())
+		}
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
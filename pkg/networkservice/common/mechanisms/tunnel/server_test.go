@@ -0,0 +1,161 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunnel_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/gre"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/tunnel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/tunnelid"
+)
+
+var space = &tunnelid.Space{Name: gre.MECHANISM, Min: 1, Max: 100}
+
+type greMechanism struct {
+	*gre.Mechanism
+}
+
+func (m *greMechanism) TunnelID() uint32      { return m.Key() }
+func (m *greMechanism) SetTunnelID(id uint32) { m.SetKey(id) }
+func (m *greMechanism) EvenTunnelID() bool    { return m.EvenKey() }
+
+func toMechanism(m *networkservice.Mechanism) tunnel.Mechanism {
+	if mechanism := gre.ToMechanism(m); mechanism != nil {
+		return &greMechanism{Mechanism: mechanism}
+	}
+	return nil
+}
+
+func request(id string, srcIP string) *networkservice.NetworkServiceRequest {
+	mechanism := &networkservice.Mechanism{Type: gre.MECHANISM}
+	gre.ToMechanism(mechanism).SetSrcIP(net.ParseIP(srcIP)).SetDstIP(net.ParseIP("192.0.2.2"))
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: id, Mechanism: mechanism},
+	}
+}
+
+func TestIDServer_AllocateAndRefresh(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	allocator := tunnelid.NewAllocator()
+	s := next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		tunnel.NewIDServer(space, allocator, toMechanism),
+	)
+
+	// The peer with the lower IP gets even IDs
+	conn1, err := s.Request(context.Background(), request("conn-1", "192.0.2.1"))
+	require.NoError(t, err)
+	id1 := gre.ToMechanism(conn1.GetMechanism()).Key()
+	require.Zero(t, id1%2)
+
+	conn2, err := s.Request(context.Background(), request("conn-2", "192.0.2.3"))
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), gre.ToMechanism(conn2.GetMechanism()).Key()%2)
+
+	// Refresh keeps the ID stored in metadata
+	refresh := request("conn-1", "192.0.2.1")
+	conn1, err = s.Request(context.Background(), refresh)
+	require.NoError(t, err)
+	require.Equal(t, id1, gre.ToMechanism(conn1.GetMechanism()).Key())
+	require.Len(t, allocator.Allocations(), 2)
+
+	_, err = s.Close(context.Background(), conn1)
+	require.NoError(t, err)
+	_, err = s.Close(context.Background(), conn2)
+	require.NoError(t, err)
+	require.Empty(t, allocator.Allocations())
+}
+
+func TestIDServer_ReleasesOnError(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	allocator := tunnelid.NewAllocator()
+	s := next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		tunnel.NewIDServer(space, allocator, toMechanism),
+		injecterror.NewServer(injecterror.WithRequestErrorTimes(0), injecterror.WithError(errors.New("failed"))),
+	)
+
+	_, err := s.Request(context.Background(), request("conn-1", "192.0.2.1"))
+	require.Error(t, err)
+	require.Empty(t, allocator.Allocations())
+}
+
+func TestIDServer_ReleasesLeakedOnClose(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	allocator := tunnelid.NewAllocator()
+	s := next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		tunnel.NewIDServer(space, allocator, toMechanism),
+	)
+
+	conn, err := s.Request(context.Background(), request("conn-1", "192.0.2.1"))
+	require.NoError(t, err)
+
+	// The ID allocated for the other peer of the same connection is leaked by the connection
+	_, err = allocator.Claim(net.ParseIP("192.0.2.5"), space, 7, "conn-1")
+	require.NoError(t, err)
+	require.Len(t, allocator.Allocations(), 2)
+
+	_, err = s.Close(context.Background(), conn)
+	require.NoError(t, err)
+	require.Empty(t, allocator.Allocations())
+}
+
+func TestIDServer_OtherMechanism(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	allocator := tunnelid.NewAllocator()
+	s := next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		tunnel.NewIDServer(space, allocator, toMechanism),
+	)
+
+	conn, err := s.Request(context.Background(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-1", Mechanism: &networkservice.Mechanism{Type: "VXLAN"}},
+	})
+	require.NoError(t, err)
+	require.Empty(t, allocator.Allocations())
+
+	_, err = s.Close(context.Background(), conn)
+	require.NoError(t, err)
+}
+
+func TestEvenTunnelID(t *testing.T) {
+	require.True(t, tunnel.EvenTunnelID(map[string]string{}))
+	require.False(t, tunnel.EvenTunnelID(map[string]string{"src_ip": "192.0.2.1"}))
+	require.True(t, tunnel.EvenTunnelID(map[string]string{"src_ip": "192.0.2.1", "dst_ip": "192.0.2.2"}))
+	// Original IPs are preferred, so both sides of NAT agree on the rule
+	require.False(t, tunnel.EvenTunnelID(map[string]string{
+		"src_ip":      "192.0.2.1",
+		"dst_ip":      "192.0.2.2",
+		"orig_src_ip": "192.0.2.3",
+	}))
+}
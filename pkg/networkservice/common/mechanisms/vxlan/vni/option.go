@@ -18,6 +18,8 @@
 
 package vni
 
+import "github.com/networkservicemesh/sdk/pkg/tools/tunnelid"
+
 // Option is an option pattern for vni server/client
 type Option func(o *vniOpions)
 
@@ -30,6 +32,14 @@ func WithTunnelPort(tunnelPort uint16) Option {
 	}
 }
 
+// WithAllocator sets allocator of the VNIs, so it can be shared with the other tunnel mechanisms or persisted
+func WithAllocator(allocator *tunnelid.Allocator) Option {
+	return func(o *vniOpions) {
+		o.allocator = allocator
+	}
+}
+
 type vniOpions struct {
 	tunnelPort uint16
+	allocator  *tunnelid.Allocator
 }
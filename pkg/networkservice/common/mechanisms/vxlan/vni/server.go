@@ -24,31 +24,24 @@ import (
 	"context"
 	"net"
 
-	"github.com/edwarnicke/genericsync"
-
-	"github.com/networkservicemesh/sdk/pkg/tools/log"
-
 	"github.com/golang/protobuf/ptypes/empty"
-	"github.com/pkg/errors"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/vxlan"
 
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/tunnel"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
-	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/tunnelid"
 )
 
-type vniKey struct {
-	srcIPString string
-	vni         uint32
-}
+// Space is the VXLAN VNI space
+var Space = &tunnelid.Space{Name: vxlan.MECHANISM, Min: 1, Max: 1<<24 - 1}
 
 type vniServer struct {
 	tunnelIP   net.IP
 	tunnelPort uint16
-
-	// This map stores all generated VNIs
-	genericsync.Map[vniKey, *vniKey]
+	ids        networkservice.NetworkServiceServer
 }
 
 // NewServer - set the DstIP *and* VNI for the vxlan mechanism
@@ -59,97 +52,58 @@ func NewServer(tunnelIP net.IP, options ...Option) networkservice.NetworkService
 	for _, opt := range options {
 		opt(opts)
 	}
+	if opts.allocator == nil {
+		opts.allocator = tunnelid.NewAllocator()
+	}
 
 	return &vniServer{
 		tunnelIP:   tunnelIP,
 		tunnelPort: opts.tunnelPort,
+		ids:        tunnel.NewIDServer(Space, opts.allocator, toMechanism),
 	}
 }
 
 func (v *vniServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
-	logger := log.FromContext(ctx).WithField("VNIserver", "request")
-
 	mechanism := vxlan.ToMechanism(request.GetConnection().GetMechanism())
 	if mechanism == nil {
-		logger.Debugf("mechanism is not vxlan")
+		log.FromContext(ctx).WithField("VNIserver", "request").Debugf("mechanism is not vxlan")
 		return next.Server(ctx).Request(ctx, request)
 	}
 	mechanism.SetDstIP(v.tunnelIP)
 	mechanism.SetDstPort(v.tunnelPort)
 
-	logger.WithField("mechanism.DstIP", mechanism.DstIP()).WithField("mechanism.DstPort", mechanism.DstPort()).Debugf("set mechanism dst")
-
-	k := vniKey{
-		srcIPString: mechanism.SrcIP().String(),
-		vni:         mechanism.VNI(),
-	}
-
-	// If we already have a VNI, make sure we remember it, and go on
-	if k.vni != 0 && mechanism.SrcIP() != nil {
-		_, _ = v.Map.LoadOrStore(k, &k)
-		_, loaded := loadOrStore(ctx, metadata.IsClient(v), k.vni)
+	log.FromContext(ctx).
+		WithField("VNIserver", "request").
+		WithField("mechanism.DstIP", mechanism.DstIP()).
+		WithField("mechanism.DstPort", mechanism.DstPort()).
+		Debugf("set mechanism dst")
 
-		logger.WithField("vni", k.vni).Debugf("loadOrStore vni in metadata")
+	return v.ids.Request(ctx, request)
+}
 
-		conn, err := next.Server(ctx).Request(ctx, request)
-		if err != nil && !loaded {
-			delete(ctx, metadata.IsClient(v))
-			v.Map.Delete(k)
+func (v *vniServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return v.ids.Close(ctx, conn)
+}
 
-			logger.WithField("vni", k.vni).Errorf("error returned from request, deleting vni. err=%v", err.Error())
-		}
-		return conn, err
-	}
+type vniMechanism struct {
+	*vxlan.Mechanism
+}
 
-	vni, loaded := load(ctx, metadata.IsClient(v))
-	if loaded {
-		mechanism.SetVNI(vni)
-		logger.WithField("vni", vni).Debugf("vni loaded from metadata")
-	} else {
-		for {
-			// Generate a random VNI (appropriately odd or even)
-			var err error
-			k.vni, err = mechanism.GenerateRandomVNI()
-			if err != nil {
-				return nil, errors.Wrap(err, "failed to generate a random VNI")
-			}
-			// If its not one already in use, set it and we are good to go
-			if _, ok := v.Map.LoadOrStore(k, &k); !ok {
-				mechanism.SetVNI(k.vni)
-				store(ctx, metadata.IsClient(v), k.vni)
-				logger.WithField("vni", k.vni).Debugf("vni generated and stored in metadata")
-				break
-			}
-		}
+func toMechanism(m *networkservice.Mechanism) tunnel.Mechanism {
+	if mechanism := vxlan.ToMechanism(m); mechanism != nil {
+		return &vniMechanism{Mechanism: mechanism}
 	}
+	return nil
+}
 
-	conn, err := next.Server(ctx).Request(ctx, request)
-	if err != nil && !loaded {
-		delete(ctx, metadata.IsClient(v))
-		v.Map.Delete(k)
+func (m *vniMechanism) TunnelID() uint32 {
+	return m.VNI()
+}
 
-		logger.WithField("vniKey.vni", k.vni).Errorf("error returned from request, deleting vni. err=%v", err.Error())
-	}
-	return conn, err
+func (m *vniMechanism) SetTunnelID(id uint32) {
+	m.SetVNI(id)
 }
 
-func (v *vniServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
-	if mechanism := vxlan.ToMechanism(conn.GetMechanism()); mechanism != nil {
-		k := vniKey{
-			srcIPString: mechanism.SrcIP().String(),
-			vni:         mechanism.VNI(),
-		}
-
-		if k.vni != 0 && mechanism.SrcIP() != nil {
-			delete(ctx, metadata.IsClient(v))
-			v.Map.LoadAndDelete(k)
-
-			log.FromContext(ctx).
-				WithField("VNIserver", "close").
-				WithField("vniKey.srcIPString", k.srcIPString).
-				WithField("vniKey.vni", k.vni).
-				Debugf("vniKey deleted")
-		}
-	}
-	return next.Server(ctx).Close(ctx, conn)
+func (m *vniMechanism) EvenTunnelID() bool {
+	return m.EvenVNI()
 }
@@ -0,0 +1,111 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"context"
+	"net"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	wireguardmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/wireguard"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/postpone"
+)
+
+type wireguardClient struct {
+	tunnelIP net.IP
+	*keeper
+}
+
+// NewClient - set the SrcIP, SrcPort and SrcPublicKey for the wireguard mechanism
+func NewClient(tunnelIP net.IP, opts ...Option) networkservice.NetworkServiceClient {
+	return &wireguardClient{
+		tunnelIP: tunnelIP,
+		keeper:   newKeeper(defaultClientPortMin, defaultClientPortMax, opts),
+	}
+}
+
+func (c *wireguardClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	var mechanisms []*wireguardmech.Mechanism
+	if mech := wireguardmech.ToMechanism(request.GetConnection().GetMechanism()); mech != nil {
+		mechanisms = append(mechanisms, mech)
+	}
+	for _, m := range request.GetMechanismPreferences() {
+		if mech := wireguardmech.ToMechanism(m); mech != nil {
+			mechanisms = append(mechanisms, mech)
+		}
+	}
+	if len(mechanisms) == 0 {
+		return next.Client(ctx).Request(ctx, request, opts...)
+	}
+
+	isClient := metadata.IsClient(c)
+	prev, _ := load(ctx, isClient)
+	cur, err := c.refresh(ctx, prev)
+	if err != nil {
+		return nil, err
+	}
+	store(ctx, isClient, cur)
+
+	for _, mech := range mechanisms {
+		mech.SetSrcIP(c.tunnelIP).SetSrcPort(cur.port).SetSrcPublicKey(cur.keys.PublicKey)
+	}
+	log.FromContext(ctx).
+		WithField("wireguardClient", "request").
+		WithField("mechSrcIp", c.tunnelIP).
+		WithField("mechSrcPort", cur.port).
+		Debugf("set mechanism src")
+
+	postponeCtxFunc := postpone.ContextWithValues(ctx)
+
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		c.rollback(ctx, isClient, prev, cur)
+		return nil, err
+	}
+
+	mech := wireguardmech.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		// Other mechanism is selected
+		c.release(ctx, isClient)
+		return conn, nil
+	}
+	if err = ValidatePublicKey(mech.DstPublicKey()); err != nil {
+		err = errors.Wrap(err, "invalid wireguard destination public key")
+
+		closeCtx, cancelClose := postponeCtxFunc()
+		defer cancelClose()
+
+		if _, closeErr := c.Close(closeCtx, conn, opts...); closeErr != nil {
+			err = errors.Wrapf(err, "connection closed with error: %s", closeErr.Error())
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (c *wireguardClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	c.release(ctx, metadata.IsClient(c))
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"context"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+type keeper struct {
+	ports               *PortAllocator
+	keyRotationInterval time.Duration
+	keyGracePeriod      time.Duration
+}
+
+func newKeeper(portMin, portMax uint16, opts []Option) *keeper {
+	o := &options{
+		keyRotationInterval: defaultKeyRotationInterval,
+		keyGracePeriod:      defaultKeyGracePeriod,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.ports == nil {
+		o.ports = NewPortAllocator(portMin, portMax)
+	}
+	return &keeper{
+		ports:               o.ports,
+		keyRotationInterval: o.keyRotationInterval,
+		keyGracePeriod:      o.keyGracePeriod,
+	}
+}
+
+// refresh returns the state for the request: allocates port and generates keys for the new connection, rotates keys for
+// the refreshed one if the rotation interval has passed keeping the previous keys for the grace period
+func (k *keeper) refresh(ctx context.Context, prev *state) (*state, error) {
+	now := clock.FromContext(ctx).Now()
+	if prev != nil && now.Sub(prev.rotatedAt) < k.keyRotationInterval {
+		return prev, nil
+	}
+
+	keys, err := GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	if prev != nil {
+		return &state{
+			port:                prev.port,
+			keys:                keys,
+			rotatedAt:           now,
+			previousKeys:        prev.keys,
+			previousKeysExpires: now.Add(k.keyGracePeriod),
+		}, nil
+	}
+
+	port, err := k.ports.Allocate()
+	if err != nil {
+		return nil, err
+	}
+	return &state{port: port, keys: keys, rotatedAt: now}, nil
+}
+
+// rollback restores metadata after the failed request
+func (k *keeper) rollback(ctx context.Context, isClient bool, prev, cur *state) {
+	if prev != nil {
+		store(ctx, isClient, prev)
+		return
+	}
+	del(ctx, isClient)
+	k.ports.Release(cur.port)
+}
+
+func (k *keeper) release(ctx context.Context, isClient bool) {
+	if s, ok := loadAndDelete(ctx, isClient); ok {
+		k.ports.Release(s.port)
+	}
+}
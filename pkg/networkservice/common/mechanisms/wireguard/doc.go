@@ -0,0 +1,20 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wireguard provides networkservice.NetworkService{Client,Server} chain elements for the wireguard mechanism:
+// they generate ephemeral key pairs, exchange public keys via the mechanism parameters, allocate listen ports and rotate
+// the keys on refresh. Private keys never leave the metadata, see KeyPairFromContext.
+package wireguard
@@ -0,0 +1,60 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+)
+
+const keyLen = 32
+
+// KeyPair - wireguard key pair encoded the same way as wg(8) does
+type KeyPair struct {
+	PrivateKey string
+	PublicKey  string
+}
+
+// GenerateKeyPair generates new Curve25519 key pair
+func GenerateKeyPair() (*KeyPair, error) {
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate wireguard private key")
+	}
+	return &KeyPair{
+		PrivateKey: base64.StdEncoding.EncodeToString(privateKey.Bytes()),
+		PublicKey:  base64.StdEncoding.EncodeToString(privateKey.PublicKey().Bytes()),
+	}, nil
+}
+
+// ValidatePublicKey checks that key is a base64 encoded Curve25519 public key
+func ValidatePublicKey(key string) error {
+	b, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return errors.Wrapf(err, "public key %q is not base64 encoded", key)
+	}
+	if len(b) != keyLen {
+		return errors.Errorf("public key %q has invalid length: %d", key, len(b))
+	}
+	if _, err := ecdh.X25519().NewPublicKey(b); err != nil {
+		return errors.Wrapf(err, "invalid public key %q", key)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"context"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+type key struct{}
+
+type state struct {
+	port                uint16
+	keys                *KeyPair
+	rotatedAt           time.Time
+	previousKeys        *KeyPair
+	previousKeysExpires time.Time
+}
+
+// KeyPairFromContext returns the current key pair of the connection, so the datapath chain elements can configure
+// the wireguard interface
+func KeyPairFromContext(ctx context.Context, isClient bool) (*KeyPair, bool) {
+	if s, ok := load(ctx, isClient); ok {
+		return s.keys, true
+	}
+	return nil, false
+}
+
+// PreviousKeyPairFromContext returns the key pair replaced by the last rotation while its grace period lasts, so the
+// datapath chain elements can keep accepting the peer still using it
+func PreviousKeyPairFromContext(ctx context.Context, isClient bool) (*KeyPair, bool) {
+	s, ok := load(ctx, isClient)
+	if !ok || s.previousKeys == nil || !clock.FromContext(ctx).Now().Before(s.previousKeysExpires) {
+		return nil, false
+	}
+	return s.previousKeys, true
+}
+
+func store(ctx context.Context, isClient bool, s *state) {
+	metadata.Map(ctx, isClient).Store(key{}, s)
+}
+
+func del(ctx context.Context, isClient bool) {
+	metadata.Map(ctx, isClient).Delete(key{})
+}
+
+func load(ctx context.Context, isClient bool) (value *state, ok bool) {
+	rawValue, ok := metadata.Map(ctx, isClient).Load(key{})
+	if !ok {
+		return nil, false
+	}
+	value, ok = rawValue.(*state)
+	return value, ok
+}
+
+func loadAndDelete(ctx context.Context, isClient bool) (value *state, ok bool) {
+	rawValue, ok := metadata.Map(ctx, isClient).LoadAndDelete(key{})
+	if !ok {
+		return nil, false
+	}
+	value, ok = rawValue.(*state)
+	return value, ok
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import "time"
+
+const (
+	// Keys are rotated far more often than the connections live, but rarely enough not to reconfigure the datapath on
+	// every refresh
+	defaultKeyRotationInterval = time.Hour
+	// Previous key is kept until both sides of the refreshed connection have applied the new keys
+	defaultKeyGracePeriod = time.Minute
+
+	// Default port ranges of the client and server are disjoint, so the forwarder running both doesn't need to share
+	// PortAllocator between them
+	defaultServerPortMin = 51820
+	defaultServerPortMax = 56819
+	defaultClientPortMin = 56820
+	defaultClientPortMax = 61819
+)
+
+type options struct {
+	ports               *PortAllocator
+	keyRotationInterval time.Duration
+	keyGracePeriod      time.Duration
+}
+
+// Option is an option pattern for wireguard client/server
+type Option func(o *options)
+
+// WithPortAllocator sets allocator of the listen ports
+func WithPortAllocator(ports *PortAllocator) Option {
+	return func(o *options) {
+		o.ports = ports
+	}
+}
+
+// WithKeyRotationInterval sets min interval between the key rotations. Keys are rotated on the first refresh after the
+// interval, 0 means rotation on every refresh. Default is 1 hour.
+func WithKeyRotationInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.keyRotationInterval = interval
+	}
+}
+
+// WithKeyGracePeriod sets how long the previous key pair is kept after the rotation, see PreviousKeyPairFromContext.
+// Default is 1 minute.
+func WithKeyGracePeriod(gracePeriod time.Duration) Option {
+	return func(o *options) {
+		o.keyGracePeriod = gracePeriod
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/edwarnicke/genericsync"
+	"github.com/pkg/errors"
+)
+
+// PortAllocator allocates wireguard listen ports from the range without collisions. Client and server on the same
+// node should either share the allocator or use the disjoint ranges.
+type PortAllocator struct {
+	min, max uint16
+
+	// This map stores all allocated ports
+	genericsync.Map[uint16, struct{}]
+}
+
+// NewPortAllocator creates PortAllocator for the [min, max] range
+func NewPortAllocator(min, max uint16) *PortAllocator {
+	if max < min {
+		min, max = max, min
+	}
+	return &PortAllocator{
+		min: min,
+		max: max,
+	}
+}
+
+// Allocate allocates a random free port
+func (a *PortAllocator) Allocate() (uint16, error) {
+	size := int64(a.max-a.min) + 1
+	start, err := rand.Int(rand.Reader, big.NewInt(size))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to generate a random port")
+	}
+	for i := int64(0); i < size; i++ {
+		port := a.min + uint16((start.Int64()+i)%size)
+		if _, loaded := a.LoadOrStore(port, struct{}{}); !loaded {
+			return port, nil
+		}
+	}
+	return 0, errors.Errorf("no free ports in range %d-%d", a.min, a.max)
+}
+
+// Release releases the port
+func (a *PortAllocator) Release(port uint16) {
+	a.Delete(port)
+}
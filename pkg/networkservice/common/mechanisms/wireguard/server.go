@@ -0,0 +1,80 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"context"
+	"net"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	wireguardmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/wireguard"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type wireguardServer struct {
+	tunnelIP net.IP
+	*keeper
+}
+
+// NewServer - set the DstIP, DstPort and DstPublicKey for the wireguard mechanism
+func NewServer(tunnelIP net.IP, opts ...Option) networkservice.NetworkServiceServer {
+	return &wireguardServer{
+		tunnelIP: tunnelIP,
+		keeper:   newKeeper(defaultServerPortMin, defaultServerPortMax, opts),
+	}
+}
+
+func (s *wireguardServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	logger := log.FromContext(ctx).WithField("wireguardServer", "request")
+
+	mechanism := wireguardmech.ToMechanism(request.GetConnection().GetMechanism())
+	if mechanism == nil {
+		return next.Server(ctx).Request(ctx, request)
+	}
+	if err := ValidatePublicKey(mechanism.SrcPublicKey()); err != nil {
+		return nil, errors.Wrap(err, "invalid wireguard source public key")
+	}
+
+	isClient := metadata.IsClient(s)
+	prev, _ := load(ctx, isClient)
+	cur, err := s.refresh(ctx, prev)
+	if err != nil {
+		return nil, err
+	}
+	store(ctx, isClient, cur)
+
+	mechanism.SetDstIP(s.tunnelIP).SetDstPort(cur.port).SetDstPublicKey(cur.keys.PublicKey)
+	logger.WithField("mechanism.DstIP", mechanism.DstIP()).WithField("mechanism.DstPort", mechanism.DstPort()).Debugf("set mechanism dst")
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		s.rollback(ctx, isClient, prev, cur)
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (s *wireguardServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.release(ctx, metadata.IsClient(s))
+	return next.Server(ctx).Close(ctx, conn)
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
+	wireguardmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/wireguard"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/wireguard"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/checks/checkcontext"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+)
+
+func newTestChain(clientOpts, serverOpts []wireguard.Option) networkservice.NetworkServiceClient {
+	return chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		wireguard.NewClient(net.ParseIP("192.0.2.1"), clientOpts...),
+		adapters.NewServerToClient(
+			chain.NewNetworkServiceServer(
+				metadata.NewServer(),
+				mechanisms.NewServer(map[string]networkservice.NetworkServiceServer{
+					wireguardmech.MECHANISM: wireguard.NewServer(net.ParseIP("192.0.2.2"), serverOpts...),
+				}),
+			),
+		),
+	)
+}
+
+func newRequest() *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id"},
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Cls: cls.REMOTE, Type: wireguardmech.MECHANISM},
+		},
+	}
+}
+
+func TestWireguard_KeyExchangeAndRotation(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opts := []wireguard.Option{wireguard.WithKeyRotationInterval(0)}
+	c := newTestChain(opts, opts)
+
+	request := newRequest()
+	conn, err := c.Request(ctx, request)
+	require.NoError(t, err)
+
+	mech := wireguardmech.ToMechanism(conn.GetMechanism())
+	require.NotNil(t, mech)
+	require.Equal(t, "192.0.2.1", mech.SrcIP().String())
+	require.Equal(t, "192.0.2.2", mech.DstIP().String())
+	require.NoError(t, wireguard.ValidatePublicKey(mech.SrcPublicKey()))
+	require.NoError(t, wireguard.ValidatePublicKey(mech.DstPublicKey()))
+	require.NotEqual(t, mech.SrcPublicKey(), mech.DstPublicKey())
+	require.NotZero(t, mech.SrcPort())
+	require.NotZero(t, mech.DstPort())
+	require.NotEqual(t, mech.SrcPort(), mech.DstPort())
+
+	first := mech.Clone()
+
+	request.Connection = conn.Clone()
+	conn, err = c.Request(ctx, request)
+	require.NoError(t, err)
+
+	mech = wireguardmech.ToMechanism(conn.GetMechanism())
+	require.NotEqual(t, wireguardmech.ToMechanism(first).SrcPublicKey(), mech.SrcPublicKey())
+	require.NotEqual(t, wireguardmech.ToMechanism(first).DstPublicKey(), mech.DstPublicKey())
+	require.Equal(t, wireguardmech.ToMechanism(first).SrcPort(), mech.SrcPort())
+	require.Equal(t, wireguardmech.ToMechanism(first).DstPort(), mech.DstPort())
+
+	_, err = c.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+func TestWireguard_KeyRotationInterval(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.New(ctx)
+	ctx = clock.WithClock(ctx, clockMock)
+
+	opts := []wireguard.Option{wireguard.WithKeyRotationInterval(time.Hour)}
+	c := newTestChain(opts, opts)
+
+	request := newRequest()
+	conn, err := c.Request(ctx, request)
+	require.NoError(t, err)
+	srcKey := wireguardmech.ToMechanism(conn.GetMechanism()).SrcPublicKey()
+
+	request.Connection = conn.Clone()
+	conn, err = c.Request(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, srcKey, wireguardmech.ToMechanism(conn.GetMechanism()).SrcPublicKey())
+
+	clockMock.Add(time.Hour)
+
+	request.Connection = conn.Clone()
+	conn, err = c.Request(ctx, request)
+	require.NoError(t, err)
+	require.NotEqual(t, srcKey, wireguardmech.ToMechanism(conn.GetMechanism()).SrcPublicKey())
+
+	_, err = c.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+func TestWireguard_KeyGracePeriod(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.New(ctx)
+	ctx = clock.WithClock(ctx, clockMock)
+
+	var keys, previousKeys *wireguard.KeyPair
+	c := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		wireguard.NewClient(net.ParseIP("192.0.2.1")),
+		adapters.NewServerToClient(
+			chain.NewNetworkServiceServer(
+				metadata.NewServer(),
+				wireguard.NewServer(net.ParseIP("192.0.2.2"),
+					wireguard.WithKeyRotationInterval(time.Hour),
+					wireguard.WithKeyGracePeriod(time.Minute),
+				),
+				checkcontext.NewServer(t, func(t *testing.T, ctx context.Context) {
+					keys, _ = wireguard.KeyPairFromContext(ctx, false)
+					previousKeys, _ = wireguard.PreviousKeyPairFromContext(ctx, false)
+				}),
+			),
+		),
+	)
+
+	request := newRequest()
+	request.Connection.Mechanism = request.GetMechanismPreferences()[0]
+	conn, err := c.Request(ctx, request)
+	require.NoError(t, err)
+	require.Nil(t, previousKeys)
+	rotated := keys
+
+	clockMock.Add(time.Hour)
+
+	request.Connection = conn.Clone()
+	conn, err = c.Request(ctx, request)
+	require.NoError(t, err)
+	require.NotEqual(t, rotated, keys)
+	require.Equal(t, rotated, previousKeys)
+
+	clockMock.Add(time.Minute)
+
+	request.Connection = conn.Clone()
+	conn, err = c.Request(ctx, request)
+	require.NoError(t, err)
+	require.Nil(t, previousKeys)
+
+	_, err = c.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+func TestWireguard_PortsAreReleased(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ports := wireguard.NewPortAllocator(51820, 51821)
+	c := newTestChain(
+		[]wireguard.Option{wireguard.WithPortAllocator(ports)},
+		[]wireguard.Option{wireguard.WithPortAllocator(ports)},
+	)
+
+	conn, err := c.Request(ctx, newRequest())
+	require.NoError(t, err)
+
+	// Both ports are used by the first connection
+	request := newRequest()
+	request.Connection.Id = "id-2"
+	_, err = c.Request(ctx, request)
+	require.Error(t, err)
+
+	_, err = c.Close(ctx, conn)
+	require.NoError(t, err)
+
+	conn, err = c.Request(ctx, request)
+	require.NoError(t, err)
+
+	_, err = c.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+func TestWireguardServer_InvalidPublicKey(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	s := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		wireguard.NewServer(net.ParseIP("192.0.2.2")),
+	)
+
+	request := newRequest()
+	request.Connection.Mechanism = &networkservice.Mechanism{Cls: cls.REMOTE, Type: wireguardmech.MECHANISM}
+	wireguardmech.ToMechanism(request.Connection.Mechanism).SetSrcPublicKey("not a key")
+
+	_, err := s.Request(context.Background(), request)
+	require.Error(t, err)
+}
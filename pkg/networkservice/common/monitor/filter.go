@@ -24,27 +24,139 @@ import (
 	"github.com/edwarnicke/serialize"
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 )
 
+type pendingConnection struct {
+	eventType networkservice.ConnectionEventType
+	conn      *networkservice.Connection
+}
+
 type monitorFilter struct {
 	selector *networkservice.MonitorScopeSelector
+	scope    *Scope
 	executor serialize.Executor
+	// remove is called when the filter fails to send an event
+	remove func()
+
+	// sent, pending, flushTimer and stopped are accessed in the executor only. sent keeps the IDs of the connections
+	// sent in the scope, pending and flushTimer are used to coalesce the events.
+	sent       map[string]struct{}
+	pending    map[string]*pendingConnection
+	flushTimer clock.Timer
+	stopped    bool
 
 	networkservice.MonitorConnection_MonitorConnectionsServer
 }
 
-func newMonitorFilter(selector *networkservice.MonitorScopeSelector, srv networkservice.MonitorConnection_MonitorConnectionsServer) *monitorFilter {
+func newMonitorFilter(selector *networkservice.MonitorScopeSelector, scope *Scope, srv networkservice.MonitorConnection_MonitorConnectionsServer, remove func()) *monitorFilter {
 	return &monitorFilter{
 		selector: selector,
+		scope:    scope,
+		remove:   remove,
+		sent:     make(map[string]struct{}),
 		MonitorConnection_MonitorConnectionsServer: srv,
 	}
 }
 
+// stop drops the coalesced events, it is called once the stream is done
+func (m *monitorFilter) stop() {
+	m.stopped = true
+	m.pending = nil
+	if m.flushTimer != nil {
+		m.flushTimer.Stop()
+		m.flushTimer = nil
+	}
+}
+
 // Send - Filter connections based on event passed and selector for this filter
 func (m *monitorFilter) Send(event *networkservice.ConnectionEvent) error {
+	connections := m.filter(event)
+	if event.Type == networkservice.ConnectionEventType_INITIAL_STATE_TRANSFER || m.scope == nil || m.scope.Coalesce == 0 {
+		return m.send(event.Type, connections)
+	}
+	m.coalesce(event.Type, connections)
+	return nil
+}
+
+func (m *monitorFilter) filter(event *networkservice.ConnectionEvent) map[string]*networkservice.Connection {
+	connections := networkservice.FilterMapOnManagerScopeSelector(event.GetConnections(), m.selector)
+	if m.scope == nil {
+		return connections
+	}
+	// Deleted connections are sent regardless of their state, so the watcher doesn't keep them
+	deleted := event.Type == networkservice.ConnectionEventType_DELETE
+	for id, conn := range connections {
+		_, sent := m.sent[id]
+		matched := m.scope.match(conn, deleted)
+		if matched && !deleted {
+			m.sent[id] = struct{}{}
+		} else {
+			delete(m.sent, id)
+		}
+		// Connection leaving the scope, e.g. going from UP to DOWN, is sent once more, so the watcher doesn't keep its
+		// previous state
+		if !matched && !sent {
+			delete(connections, id)
+			continue
+		}
+		connections[id] = m.scope.apply(conn)
+	}
+	return connections
+}
+
+// coalesce collects the connections until the flush, so only the last event of each connection is sent
+func (m *monitorFilter) coalesce(eventType networkservice.ConnectionEventType, connections map[string]*networkservice.Connection) {
+	if len(connections) == 0 || m.stopped {
+		return
+	}
+	if m.pending == nil {
+		m.pending = make(map[string]*pendingConnection)
+	}
+	for id, conn := range connections {
+		m.pending[id] = &pendingConnection{eventType: eventType, conn: conn}
+	}
+	if m.flushTimer != nil {
+		return
+	}
+	m.flushTimer = clock.FromContext(m.Context()).AfterFunc(m.scope.Coalesce, func() {
+		m.executor.AsyncExec(func() {
+			if m.stopped {
+				return
+			}
+			m.flushTimer = nil
+			if err := m.flush(); err != nil {
+				m.stop()
+				m.remove()
+			}
+		})
+	})
+}
+
+func (m *monitorFilter) flush() error {
+	pending := m.pending
+	m.pending = nil
+
+	updated := make(map[string]*networkservice.Connection)
+	deleted := make(map[string]*networkservice.Connection)
+	for id, p := range pending {
+		if p.eventType == networkservice.ConnectionEventType_DELETE {
+			deleted[id] = p.conn
+			continue
+		}
+		updated[id] = p.conn
+	}
+	if err := m.send(networkservice.ConnectionEventType_UPDATE, updated); err != nil {
+		return err
+	}
+	return m.send(networkservice.ConnectionEventType_DELETE, deleted)
+}
+
+func (m *monitorFilter) send(eventType networkservice.ConnectionEventType, connections map[string]*networkservice.Connection) error {
 	rv := &networkservice.ConnectionEvent{
-		Type:        event.Type,
-		Connections: networkservice.FilterMapOnManagerScopeSelector(event.GetConnections(), m.selector),
+		Type:        eventType,
+		Connections: connections,
 	}
 	if rv.Type == networkservice.ConnectionEventType_INITIAL_STATE_TRANSFER || len(rv.GetConnections()) > 0 {
 		if err := m.MonitorConnection_MonitorConnectionsServer.Send(rv); err != nil {
@@ -22,6 +22,8 @@ import (
 	"github.com/edwarnicke/serialize"
 	"github.com/google/uuid"
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type monitorConnectionServer struct {
@@ -40,9 +42,19 @@ func newMonitorConnectionServer(chainCtx context.Context) networkservice.Monitor
 }
 
 func (m *monitorConnectionServer) MonitorConnections(selector *networkservice.MonitorScopeSelector, srv networkservice.MonitorConnection_MonitorConnectionsServer) error {
+	scope, err := ScopeFromContext(srv.Context())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	id := uuid.New().String()
+	filter := newMonitorFilter(selector, scope, srv, func() {
+		m.executor.AsyncExec(func() {
+			delete(m.filters, id)
+		})
+	})
 	m.executor.AsyncExec(func() {
-		filter := newMonitorFilter(selector, srv)
-		m.filters[uuid.New().String()] = filter
+		m.filters[id] = filter
 
 		connections := networkservice.FilterMapOnManagerScopeSelector(m.connections, selector)
 
@@ -60,6 +72,9 @@ func (m *monitorConnectionServer) MonitorConnections(selector *networkservice.Mo
 	case <-m.chainCtx.Done():
 	}
 
+	filter.remove()
+	filter.executor.AsyncExec(filter.stop)
+
 	return nil
 }
 
@@ -81,23 +96,19 @@ func (m *monitorConnectionServer) Send(event *networkservice.ConnectionEvent) (_
 			// sending event with INIITIAL_STATE_TRANSFER not permitted
 			return
 		}
-		for id, filter := range m.filters {
-			id, filter := id, filter
+		for _, filter := range m.filters {
+			filter := filter
 			e := event.Clone()
 			filter.executor.AsyncExec(func() {
 				var err error
 				select {
 				case <-filter.Context().Done():
-					m.executor.AsyncExec(func() {
-						delete(m.filters, id)
-					})
+					filter.remove()
 				default:
 					err = filter.Send(e)
 				}
 				if err != nil {
-					m.executor.AsyncExec(func() {
-						delete(m.filters, id)
-					})
+					filter.remove()
 				}
 			})
 		}
@@ -0,0 +1,202 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	networkServiceKey = "nsm-monitor-network-service"
+	labelKey          = "nsm-monitor-label"
+	stateKey          = "nsm-monitor-state"
+	maskKey           = "nsm-monitor-mask"
+	coalesceKey       = "nsm-monitor-coalesce"
+)
+
+// Mask is a set of the Connection fields stripped from the monitor events
+type Mask uint32
+
+const (
+	// MaskTokens strips tokens and their expiration from the path segments
+	MaskTokens Mask = 1 << iota
+	// MaskPath strips everything but names and IDs from the path segments
+	MaskPath
+	// MaskContext strips the connection context
+	MaskContext
+	// MaskMechanism strips the connection mechanism
+	MaskMechanism
+)
+
+var maskNames = map[string]Mask{
+	"tokens":    MaskTokens,
+	"path":      MaskPath,
+	"context":   MaskContext,
+	"mechanism": MaskMechanism,
+}
+
+// Scope is a server side filter of the MonitorConnections stream. MonitorScopeSelector selects connections by path
+// segments only, so Scope is passed with gRPC metadata of the MonitorConnections call.
+type Scope struct {
+	// NetworkServices - if not empty, only connections to these network services are sent
+	NetworkServices []string
+	// Labels - only connections with all these labels are sent
+	Labels map[string]string
+	// States - if not empty, only connections in these states are sent
+	States []networkservice.State
+	// Mask - fields stripped from the sent connections
+	Mask Mask
+	// Coalesce - if not zero, the events are collected for the interval, so rapid updates of one connection are sent
+	// as one event
+	Coalesce time.Duration
+}
+
+// WithScope returns context with scope appended to the outgoing gRPC metadata, it should be used for the
+// MonitorConnections call
+func WithScope(ctx context.Context, scope *Scope) context.Context {
+	var kv []string
+	for _, ns := range scope.NetworkServices {
+		kv = append(kv, networkServiceKey, ns)
+	}
+	for k, v := range scope.Labels {
+		kv = append(kv, labelKey, k+"="+v)
+	}
+	for _, state := range scope.States {
+		kv = append(kv, stateKey, state.String())
+	}
+	for name, mask := range maskNames {
+		if scope.Mask&mask != 0 {
+			kv = append(kv, maskKey, name)
+		}
+	}
+	if scope.Coalesce > 0 {
+		kv = append(kv, coalesceKey, scope.Coalesce.String())
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// ScopeFromContext returns scope from the incoming gRPC metadata of the MonitorConnections call, or nil if the scope is
+// not set
+func ScopeFromContext(ctx context.Context) (*Scope, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	scope := &Scope{
+		NetworkServices: md.Get(networkServiceKey),
+	}
+	for _, label := range md.Get(labelKey) {
+		k, v, found := strings.Cut(label, "=")
+		if !found {
+			return nil, errors.Errorf("invalid monitor label: %s", label)
+		}
+		if scope.Labels == nil {
+			scope.Labels = make(map[string]string)
+		}
+		scope.Labels[k] = v
+	}
+	for _, name := range md.Get(stateKey) {
+		state, ok := networkservice.State_value[name]
+		if !ok {
+			return nil, errors.Errorf("invalid monitor state: %s", name)
+		}
+		scope.States = append(scope.States, networkservice.State(state))
+	}
+	for _, name := range md.Get(maskKey) {
+		mask, ok := maskNames[name]
+		if !ok {
+			return nil, errors.Errorf("invalid monitor mask: %s", name)
+		}
+		scope.Mask |= mask
+	}
+	if values := md.Get(coalesceKey); len(values) > 0 {
+		coalesce, err := time.ParseDuration(values[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid monitor coalesce interval: %s", values[0])
+		}
+		scope.Coalesce = coalesce
+	}
+
+	if len(scope.NetworkServices) == 0 && scope.Labels == nil && scope.States == nil && scope.Mask == 0 && scope.Coalesce == 0 {
+		return nil, nil
+	}
+	return scope, nil
+}
+
+// match returns true if conn is in the scope, states are not checked for the deleted connections
+func (s *Scope) match(conn *networkservice.Connection, deleted bool) bool {
+	if s == nil {
+		return true
+	}
+	if len(s.NetworkServices) > 0 && !contains(s.NetworkServices, conn.GetNetworkService()) {
+		return false
+	}
+	for k, v := range s.Labels {
+		if value, ok := conn.GetLabels()[k]; !ok || value != v {
+			return false
+		}
+	}
+	if !deleted && len(s.States) > 0 && !contains(s.States, conn.GetState()) {
+		return false
+	}
+	return true
+}
+
+// apply returns conn stripped with the scope mask, conn is cloned if any field is stripped
+func (s *Scope) apply(conn *networkservice.Connection) *networkservice.Connection {
+	if s == nil || s.Mask == 0 {
+		return conn
+	}
+
+	conn = conn.Clone()
+	if s.Mask&MaskContext != 0 {
+		conn.Context = nil
+	}
+	if s.Mask&MaskMechanism != 0 {
+		conn.Mechanism = nil
+	}
+	for i, segment := range conn.GetPath().GetPathSegments() {
+		if s.Mask&MaskPath != 0 {
+			conn.GetPath().GetPathSegments()[i] = &networkservice.PathSegment{
+				Name: segment.GetName(),
+				Id:   segment.GetId(),
+			}
+			continue
+		}
+		if s.Mask&MaskTokens != 0 {
+			segment.Token = ""
+			segment.Expires = nil
+		}
+	}
+	return conn
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
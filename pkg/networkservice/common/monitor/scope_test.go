@@ -0,0 +1,276 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	grpcmetadata "google.golang.org/grpc/metadata"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/monitor"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/eventchannel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+)
+
+// monitorWithScope starts MonitorConnections stream with the scope passed as it is passed by gRPC
+func monitorWithScope(ctx context.Context, t *testing.T, monitorServer networkservice.MonitorConnectionServer, scope *monitor.Scope) <-chan *networkservice.ConnectionEvent {
+	md, _ := grpcmetadata.FromOutgoingContext(monitor.WithScope(ctx, scope))
+	ctx = grpcmetadata.NewIncomingContext(ctx, md)
+
+	eventCh := make(chan *networkservice.ConnectionEvent, 10)
+	go func() {
+		_ = monitorServer.MonitorConnections(new(networkservice.MonitorScopeSelector), eventchannel.NewMonitorConnectionMonitorConnectionsServer(ctx, eventCh))
+	}()
+
+	event := <-eventCh
+	require.Equal(t, networkservice.ConnectionEventType_INITIAL_STATE_TRANSFER, event.GetType())
+	return eventCh
+}
+
+func request(id, networkService string, labels map[string]string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             id,
+			NetworkService: networkService,
+			Labels:         labels,
+			Context:        &networkservice.ConnectionContext{MTU: 1500},
+			Path: &networkservice.Path{
+				PathSegments: []*networkservice.PathSegment{{Name: "nsmgr", Id: id, Token: "token", Metrics: map[string]string{"rx": "1"}}},
+			},
+		},
+	}
+}
+
+func TestScopeFromContext(t *testing.T) {
+	scope := &monitor.Scope{
+		NetworkServices: []string{"ns-1", "ns-2"},
+		Labels:          map[string]string{"app": "vpn"},
+		States:          []networkservice.State{networkservice.State_UP},
+		Mask:            monitor.MaskTokens | monitor.MaskContext,
+		Coalesce:        time.Second,
+	}
+	md, _ := grpcmetadata.FromOutgoingContext(monitor.WithScope(context.Background(), scope))
+
+	actual, err := monitor.ScopeFromContext(grpcmetadata.NewIncomingContext(context.Background(), md))
+	require.NoError(t, err)
+	require.Equal(t, scope, actual)
+
+	actual, err = monitor.ScopeFromContext(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, actual)
+
+	_, err = monitor.ScopeFromContext(grpcmetadata.NewIncomingContext(context.Background(), grpcmetadata.Pairs("nsm-monitor-mask", "unknown")))
+	require.Error(t, err)
+}
+
+func TestMonitorServer_Scope(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var monitorServer networkservice.MonitorConnectionServer
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		monitor.NewServer(ctx, &monitorServer),
+	)
+
+	eventCh := monitorWithScope(ctx, t, monitorServer, &monitor.Scope{
+		NetworkServices: []string{"ns-1"},
+		Labels:          map[string]string{"app": "vpn"},
+		Mask:            monitor.MaskPath | monitor.MaskContext,
+	})
+
+	_, err := server.Request(ctx, request("conn-1", "ns-1", map[string]string{"app": "vpn", "node": "a"}))
+	require.NoError(t, err)
+	_, err = server.Request(ctx, request("conn-2", "ns-2", map[string]string{"app": "vpn"}))
+	require.NoError(t, err)
+	conn, err := server.Request(ctx, request("conn-3", "ns-1", map[string]string{"app": "web"}))
+	require.NoError(t, err)
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+	conn, err = server.Request(ctx, request("conn-4", "ns-1", map[string]string{"app": "vpn"}))
+	require.NoError(t, err)
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+
+	event := <-eventCh
+	require.Equal(t, networkservice.ConnectionEventType_UPDATE, event.GetType())
+	require.Len(t, event.GetConnections(), 1)
+	conn = event.GetConnections()["conn-1"]
+	require.Nil(t, conn.GetContext())
+	require.Equal(t, []*networkservice.PathSegment{{Name: "nsmgr", Id: "conn-1"}}, conn.GetPath().GetPathSegments())
+
+	event = <-eventCh
+	require.Equal(t, networkservice.ConnectionEventType_UPDATE, event.GetType())
+	require.NotNil(t, event.GetConnections()["conn-4"])
+
+	event = <-eventCh
+	require.Equal(t, networkservice.ConnectionEventType_DELETE, event.GetType())
+	require.NotNil(t, event.GetConnections()["conn-4"])
+	require.Empty(t, eventCh)
+}
+
+func TestMonitorServer_Coalesce(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	clockMock := clockmock.New(ctx)
+	ctx = clock.WithClock(ctx, clockMock)
+
+	var monitorServer networkservice.MonitorConnectionServer
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		monitor.NewServer(ctx, &monitorServer),
+	)
+
+	eventCh := monitorWithScope(ctx, t, monitorServer, &monitor.Scope{
+		Mask:     monitor.MaskTokens,
+		Coalesce: time.Second,
+	})
+
+	for i := 0; i < 10; i++ {
+		req := request("conn-1", "ns-1", nil)
+		req.GetConnection().GetContext().MTU = uint32(1000 + i)
+		_, err := server.Request(ctx, req)
+		require.NoError(t, err)
+	}
+	conn, err := server.Request(ctx, request("conn-2", "ns-1", nil))
+	require.NoError(t, err)
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+
+	require.Never(t, func() bool { return len(eventCh) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+	clockMock.Add(time.Second)
+
+	event := <-eventCh
+	require.Equal(t, networkservice.ConnectionEventType_UPDATE, event.GetType())
+	require.Len(t, event.GetConnections(), 1)
+	require.Equal(t, uint32(1009), event.GetConnections()["conn-1"].GetContext().GetMTU())
+	require.Empty(t, event.GetConnections()["conn-1"].GetPath().GetPathSegments()[0].GetToken())
+
+	event = <-eventCh
+	require.Equal(t, networkservice.ConnectionEventType_DELETE, event.GetType())
+	require.Len(t, event.GetConnections(), 1)
+	require.NotNil(t, event.GetConnections()["conn-2"])
+}
+
+func TestMonitorServer_ScopeStates(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var monitorServer networkservice.MonitorConnectionServer
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		monitor.NewServer(ctx, &monitorServer),
+	)
+
+	eventCh := monitorWithScope(ctx, t, monitorServer, &monitor.Scope{
+		States: []networkservice.State{networkservice.State_UP},
+	})
+
+	for _, state := range []networkservice.State{
+		networkservice.State_UP,
+		networkservice.State_DOWN,
+		networkservice.State_DOWN,
+		networkservice.State_UP,
+	} {
+		req := request("conn-1", "ns-1", nil)
+		req.GetConnection().State = state
+		_, err := server.Request(ctx, req)
+		require.NoError(t, err)
+	}
+
+	// UP -> DOWN transition is sent once, the next DOWN update is out of the scope
+	for _, state := range []networkservice.State{
+		networkservice.State_UP,
+		networkservice.State_DOWN,
+		networkservice.State_UP,
+	} {
+		event := <-eventCh
+		require.Equal(t, networkservice.ConnectionEventType_UPDATE, event.GetType())
+		require.Equal(t, state, event.GetConnections()["conn-1"].GetState())
+	}
+	require.Never(t, func() bool { return len(eventCh) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+}
+
+type countingServer struct {
+	sends int32
+
+	networkservice.MonitorConnection_MonitorConnectionsServer
+}
+
+func (s *countingServer) Send(event *networkservice.ConnectionEvent) error {
+	atomic.AddInt32(&s.sends, 1)
+	return s.MonitorConnection_MonitorConnectionsServer.Send(event)
+}
+
+func TestMonitorServer_CoalesceStreamDone(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	clockMock := clockmock.New(ctx)
+	ctx = clock.WithClock(ctx, clockMock)
+
+	var monitorServer networkservice.MonitorConnectionServer
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		monitor.NewServer(ctx, &monitorServer),
+	)
+
+	md, _ := grpcmetadata.FromOutgoingContext(monitor.WithScope(ctx, &monitor.Scope{Coalesce: time.Second}))
+	streamCtx, cancelStream := context.WithCancel(grpcmetadata.NewIncomingContext(ctx, md))
+	defer cancelStream()
+
+	eventCh := make(chan *networkservice.ConnectionEvent, 10)
+	srv := &countingServer{
+		MonitorConnection_MonitorConnectionsServer: eventchannel.NewMonitorConnectionMonitorConnectionsServer(streamCtx, eventCh),
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = monitorServer.MonitorConnections(new(networkservice.MonitorScopeSelector), srv)
+	}()
+	require.Equal(t, networkservice.ConnectionEventType_INITIAL_STATE_TRANSFER, (<-eventCh).GetType())
+
+	_, err := server.Request(ctx, request("conn-1", "ns-1", nil))
+	require.NoError(t, err)
+	require.Never(t, func() bool { return len(eventCh) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+
+	cancelStream()
+	<-done
+
+	// The coalesced events are dropped with the stream, nothing is sent on flush
+	clockMock.Add(time.Second)
+	require.Never(t, func() bool { return atomic.LoadInt32(&srv.sends) > 1 }, 100*time.Millisecond, 10*time.Millisecond)
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multipath
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/edwarnicke/genericsync"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/heal"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/extend"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type multipathClient struct {
+	chainCtx      context.Context
+	mode          Mode
+	nseClient     registry.NetworkServiceEndpointRegistryClient
+	retryInterval time.Duration
+	onFailover    func(conn *networkservice.Connection)
+	states        genericsync.Map[string, *state]
+}
+
+// NewClient - returns a new multipath client chain element.
+//   - chainCtx - context for the lifecycle of the client, standby retries stop when it is canceled
+func NewClient(chainCtx context.Context, opts ...Option) networkservice.NetworkServiceClient {
+	c := &multipathClient{
+		chainCtx:      chainCtx,
+		mode:          PrimaryBackup,
+		retryInterval: defaultRetryInterval,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (m *multipathClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	if request.GetConnection() == nil {
+		request.Connection = &networkservice.Connection{}
+	}
+	if request.GetConnection().GetId() == "" {
+		request.GetConnection().Id = uuid.New().String()
+	}
+
+	id, _ := parsePathID(request.GetConnection().GetId())
+	st, _ := m.states.LoadOrStore(id, &state{id: id})
+
+	st.requestMu.Lock()
+	defer st.requestMu.Unlock()
+
+	ctx = heal.WithObserver(ctx, m.observe)
+
+	var errs [pathCount]error
+	if st.conn(standbyPath) == nil {
+		// A new standby path is pinned to an NSE different from the primary one, so it waits for the primary path
+		for i := 0; i < pathCount; i++ {
+			errs[i] = m.requestPath(ctx, st, request, i, opts...)
+		}
+	} else {
+		var wg sync.WaitGroup
+		for i := 0; i < pathCount; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = m.requestPath(ctx, st, request, i, opts...)
+			}(i)
+		}
+		wg.Wait()
+	}
+	st.stopRetry()
+
+	if errs[primaryPath] != nil && errs[standbyPath] != nil {
+		if !st.established() {
+			m.states.Delete(id)
+		}
+		return nil, errors.Wrapf(errs[primaryPath], "all paths have failed, standby path: %s", errs[standbyPath].Error())
+	}
+	for i, err := range errs {
+		if err != nil && st.conn(i) == nil {
+			log.FromContext(ctx).WithField("multipathClient", "Request").
+				Warnf("path %s is not established, retry in %v: %v", pathID(id, i), m.retryInterval, err)
+			m.retry(ctx, st, request, opts...)
+		}
+	}
+
+	return st.result(m.mode), nil
+}
+
+func (m *multipathClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	id, _ := parsePathID(conn.GetId())
+	st, ok := m.states.LoadAndDelete(id)
+	if !ok {
+		return next.Client(ctx).Close(ctx, conn, opts...)
+	}
+
+	st.requestMu.Lock()
+	defer st.requestMu.Unlock()
+
+	if st.cancelRetry != nil {
+		st.cancelRetry()
+		st.cancelRetry = nil
+	}
+
+	var err error
+	for i := 0; i < pathCount; i++ {
+		pathConn := st.conn(i)
+		if pathConn == nil {
+			continue
+		}
+		if _, closeErr := next.Client(ctx).Close(ctx, pathConn, opts...); closeErr != nil {
+			if err == nil {
+				err = errors.Wrapf(closeErr, "failed to close path %s", pathConn.GetId())
+			} else {
+				err = errors.Wrapf(err, "failed to close path %s: %s", pathConn.GetId(), closeErr.Error())
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &empty.Empty{}, nil
+}
+
+func (m *multipathClient) requestPath(ctx context.Context, st *state, request *networkservice.NetworkServiceRequest, i int, opts ...grpc.CallOption) error {
+	req := st.request(request, i)
+	if i == standbyPath && st.conn(standbyPath) == nil {
+		m.avoidPrimaryNSE(ctx, st, req)
+	}
+
+	conn, err := next.Client(ctx).Request(ctx, req, opts...)
+	if st.update(i, conn, err) {
+		m.failover(st)
+	}
+	return err
+}
+
+// avoidPrimaryNSE pins the new standby path to an NSE different from the NSE of the primary path
+func (m *multipathClient) avoidPrimaryNSE(ctx context.Context, st *state, req *networkservice.NetworkServiceRequest) {
+	primary := st.conn(primaryPath)
+	if m.nseClient == nil || primary == nil {
+		return
+	}
+
+	logger := log.FromContext(ctx).WithField("multipathClient", "avoidPrimaryNSE")
+
+	stream, err := m.nseClient.Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{
+			NetworkServiceNames: []string{req.GetConnection().GetNetworkService()},
+		},
+	})
+	if err != nil {
+		logger.Warnf("failed to find endpoints for %s: %v", req.GetConnection().GetNetworkService(), err)
+		return
+	}
+
+	now := clock.FromContext(ctx).Now()
+	for _, nse := range registry.ReadNetworkServiceEndpointList(stream) {
+		if nse.GetName() == primary.GetNetworkServiceEndpointName() {
+			continue
+		}
+		if nse.GetExpirationTime() != nil && nse.GetExpirationTime().AsTime().Before(now) {
+			continue
+		}
+		req.GetConnection().NetworkServiceEndpointName = nse.GetName()
+		return
+	}
+	logger.Warnf("no alternative endpoint for %s, standby path may share the primary endpoint", primary.GetNetworkServiceEndpointName())
+}
+
+// retry keeps requesting not established paths in background until both are established or the connection is
+// closed
+func (m *multipathClient) retry(ctx context.Context, st *state, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) {
+	if st.cancelRetry != nil {
+		return
+	}
+
+	retryCtx, cancel := context.WithCancel(m.chainCtx)
+	st.cancelRetry = cancel
+	retryCtx = extend.WithValuesFromContext(retryCtx, ctx)
+	request = request.Clone()
+	clockTime := clock.FromContext(ctx)
+
+	go func() {
+		for {
+			select {
+			case <-retryCtx.Done():
+				return
+			case <-clockTime.After(m.retryInterval):
+			}
+			m.retryOnce(retryCtx, st, request, opts...)
+		}
+	}()
+}
+
+func (m *multipathClient) retryOnce(ctx context.Context, st *state, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) {
+	st.requestMu.Lock()
+	defer st.requestMu.Unlock()
+
+	for i := 0; i < pathCount && ctx.Err() == nil; i++ {
+		if st.conn(i) != nil {
+			continue
+		}
+
+		requestCtx, cancel := clock.FromContext(ctx).WithTimeout(ctx, m.retryInterval)
+		if err := m.requestPath(requestCtx, st, request, i, opts...); err != nil {
+			log.FromContext(ctx).WithField("multipathClient", "retry").Debugf("path %s is not established: %v", pathID(st.id, i), err)
+		}
+		cancel()
+	}
+	st.stopRetry()
+}
+
+func (m *multipathClient) observe(conn *networkservice.Connection, connState networkservice.State) {
+	id, i := parsePathID(conn.GetId())
+	st, ok := m.states.Load(id)
+	if !ok {
+		return
+	}
+	var switched bool
+	if connState == networkservice.State_UP {
+		switched = st.refresh(i, conn)
+	} else {
+		switched = st.setDown(i)
+	}
+	if switched {
+		m.failover(st)
+	}
+}
+
+func (m *multipathClient) failover(st *state) {
+	conn := st.result(m.mode)
+	log.FromContext(m.chainCtx).WithField("multipathClient", "failover").
+		Infof("connection %s failed over to path %s", st.id, conn.GetCurrentPathSegment().GetId())
+	if m.onFailover != nil {
+		m.onFailover(conn)
+	}
+}
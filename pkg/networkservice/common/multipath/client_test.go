@@ -0,0 +1,460 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multipath_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edwarnicke/genericsync"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/begin"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/clientconn"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/heal"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/multipath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+)
+
+const (
+	connID = "conn"
+	nsName = "ns"
+)
+
+// pathsClient emulates the rest of the client chain: it returns connections to the pinned endpoint (nse-1 by
+// default) and fails requests for the IDs in fail.
+type pathsClient struct {
+	mu     sync.Mutex
+	fail   map[string]bool
+	routes map[string]string
+	conns  map[string]*networkservice.Connection
+}
+
+func newPathsClient() *pathsClient {
+	return &pathsClient{
+		fail:   make(map[string]bool),
+		routes: make(map[string]string),
+		conns:  make(map[string]*networkservice.Connection),
+	}
+}
+
+func (c *pathsClient) setFail(id string, fail bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fail[id] = fail
+}
+
+func (c *pathsClient) active() map[string]*networkservice.Connection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	conns := make(map[string]*networkservice.Connection, len(c.conns))
+	for id, conn := range c.conns {
+		conns[id] = conn.Clone()
+	}
+	return conns
+}
+
+func (c *pathsClient) Request(_ context.Context, request *networkservice.NetworkServiceRequest, _ ...grpc.CallOption) (*networkservice.Connection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	conn := request.GetConnection().Clone()
+	if c.fail[conn.GetId()] {
+		return nil, errors.Errorf("path %s has failed", conn.GetId())
+	}
+	if conn.GetNetworkServiceEndpointName() == "" {
+		conn.NetworkServiceEndpointName = "nse-1"
+	}
+	conn.Context = &networkservice.ConnectionContext{
+		IpContext: &networkservice.IPContext{
+			DstRoutes: []*networkservice.Route{
+				{Prefix: "10.0.0.0/24"},
+				{Prefix: c.routes[conn.GetId()]},
+			},
+		},
+	}
+	c.conns[conn.GetId()] = conn
+	return conn.Clone(), nil
+}
+
+func (c *pathsClient) Close(_ context.Context, conn *networkservice.Connection, _ ...grpc.CallOption) (*empty.Empty, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.conns, conn.GetId())
+	return &empty.Empty{}, nil
+}
+
+func request() *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             connID,
+			NetworkService: nsName,
+		},
+	}
+}
+
+func TestMultipathClient_PrimaryBackup(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	paths := newPathsClient()
+	client := chain.NewNetworkServiceClient(multipath.NewClient(ctx), paths)
+
+	conn, err := client.Request(ctx, request())
+	require.NoError(t, err)
+	require.Equal(t, connID, conn.GetId())
+	require.Len(t, paths.active(), 2)
+	require.Contains(t, paths.active(), connID+multipath.StandbySuffix)
+
+	// Refresh
+	request := request()
+	request.Connection = conn
+	conn, err = client.Request(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, connID, conn.GetId())
+	require.Len(t, paths.active(), 2)
+
+	_, err = client.Close(ctx, conn)
+	require.NoError(t, err)
+	require.Empty(t, paths.active())
+}
+
+func TestMultipathClient_StandbyAvoidsPrimaryNSE(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	nseClient := adapters.NetworkServiceEndpointServerToClient(memory.NewNetworkServiceEndpointRegistryServer())
+	for _, name := range []string{"nse-1", "nse-2"} {
+		_, err := nseClient.Register(ctx, &registry.NetworkServiceEndpoint{
+			Name:                name,
+			NetworkServiceNames: []string{nsName},
+		})
+		require.NoError(t, err)
+	}
+
+	paths := newPathsClient()
+	client := chain.NewNetworkServiceClient(multipath.NewClient(ctx, multipath.WithNSEClient(nseClient)), paths)
+
+	conn, err := client.Request(ctx, request())
+	require.NoError(t, err)
+	require.Equal(t, "nse-1", paths.active()[connID].GetNetworkServiceEndpointName())
+	require.Equal(t, "nse-2", paths.active()[connID+multipath.StandbySuffix].GetNetworkServiceEndpointName())
+
+	_, err = client.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+func TestMultipathClient_Failover(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	failoverCh := make(chan *networkservice.Connection, 1)
+	paths := newPathsClient()
+	paths.routes[connID] = "10.0.1.0/24"
+	paths.routes[connID+multipath.StandbySuffix] = "10.0.2.0/24"
+
+	client := chain.NewNetworkServiceClient(
+		multipath.NewClient(ctx, multipath.WithFailoverCallback(func(conn *networkservice.Connection) {
+			failoverCh <- conn
+		})),
+		paths,
+	)
+
+	conn, err := client.Request(ctx, request())
+	require.NoError(t, err)
+	require.Equal(t, "10.0.1.0/24", conn.GetContext().GetIpContext().GetDstRoutes()[1].GetPrefix())
+
+	// Primary path fails on refresh, standby path becomes active
+	paths.setFail(connID, true)
+	request := request()
+	request.Connection = conn
+	conn, err = client.Request(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, connID, conn.GetId())
+	require.Equal(t, "10.0.2.0/24", conn.GetContext().GetIpContext().GetDstRoutes()[1].GetPrefix())
+
+	select {
+	case failoverConn := <-failoverCh:
+		require.Equal(t, conn.String(), failoverConn.String())
+	default:
+		require.FailNow(t, "no failover")
+	}
+
+	_, err = client.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+func TestMultipathClient_ActiveActive(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	paths := newPathsClient()
+	paths.routes[connID] = "10.0.1.0/24"
+	paths.routes[connID+multipath.StandbySuffix] = "10.0.2.0/24"
+
+	client := chain.NewNetworkServiceClient(multipath.NewClient(ctx, multipath.WithMode(multipath.ActiveActive)), paths)
+
+	conn, err := client.Request(ctx, request())
+	require.NoError(t, err)
+
+	var prefixes []string
+	for _, route := range conn.GetContext().GetIpContext().GetDstRoutes() {
+		prefixes = append(prefixes, route.GetPrefix())
+	}
+	require.Equal(t, []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24"}, prefixes)
+
+	_, err = client.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+func TestMultipathClient_RetryStandby(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	clockMock := clockmock.New(ctx)
+	ctx = clock.WithClock(ctx, clockMock)
+
+	paths := newPathsClient()
+	paths.setFail(connID+multipath.StandbySuffix, true)
+
+	client := chain.NewNetworkServiceClient(
+		multipath.NewClient(ctx, multipath.WithRetryInterval(time.Second)),
+		paths,
+	)
+
+	conn, err := client.Request(ctx, request())
+	require.NoError(t, err)
+	require.Len(t, paths.active(), 1)
+
+	paths.setFail(connID+multipath.StandbySuffix, false)
+	require.Eventually(t, func() bool {
+		clockMock.Add(time.Second)
+		return len(paths.active()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = client.Close(ctx, conn)
+	require.NoError(t, err)
+	require.Empty(t, paths.active())
+}
+
+func TestMultipathClient_AllPathsFailed(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	paths := newPathsClient()
+	paths.setFail(connID, true)
+	paths.setFail(connID+multipath.StandbySuffix, true)
+
+	_, err := chain.NewNetworkServiceClient(multipath.NewClient(ctx), paths).Request(ctx, request())
+	require.Error(t, err)
+}
+
+// generationClient returns connections with the number of requests for the connection ID in the extra context
+type generationClient struct {
+	mu          sync.Mutex
+	generations map[string]int
+}
+
+func (c *generationClient) Request(_ context.Context, request *networkservice.NetworkServiceRequest, _ ...grpc.CallOption) (*networkservice.Connection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	conn := request.GetConnection().Clone()
+	c.generations[conn.GetId()]++
+	conn.Context = &networkservice.ConnectionContext{
+		ExtraContext: map[string]string{"generation": strconv.Itoa(c.generations[conn.GetId()])},
+	}
+	return conn, nil
+}
+
+func (c *generationClient) Close(_ context.Context, _ *networkservice.Connection, _ ...grpc.CallOption) (*empty.Empty, error) {
+	return &empty.Empty{}, nil
+}
+
+// eventFactoryClient stores the begin event factories to refresh paths below the multipath client
+type eventFactoryClient struct {
+	factories genericsync.Map[string, begin.EventFactory]
+}
+
+func (c *eventFactoryClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	c.factories.Store(request.GetConnection().GetId(), begin.FromContext(ctx))
+	return next.Client(ctx).Request(ctx, request, opts...)
+}
+
+func (c *eventFactoryClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
+
+// monitorCC opens monitor streams never sending events, so heal detects the failures by the liveness check only
+type monitorCC struct {
+	grpc.ClientConnInterface
+}
+
+func (cc *monitorCC) NewStream(ctx context.Context, _ *grpc.StreamDesc, _ string, _ ...grpc.CallOption) (grpc.ClientStream, error) {
+	return &monitorStream{ctx: ctx}, nil
+}
+
+type monitorStream struct {
+	grpc.ClientStream
+	ctx context.Context
+}
+
+func (s *monitorStream) Context() context.Context { return s.ctx }
+
+func (s *monitorStream) SendMsg(interface{}) error { return nil }
+
+func (s *monitorStream) CloseSend() error { return nil }
+
+func (s *monitorStream) RecvMsg(interface{}) error {
+	<-s.ctx.Done()
+	return s.ctx.Err()
+}
+
+func TestMultipathClient_RefreshBelowUpdatesPaths(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var primaryDown atomic.Bool
+	livenessCheck := func(_ context.Context, conn *networkservice.Connection) bool {
+		return conn.GetId() != connID || !primaryDown.Load()
+	}
+
+	failoverCh := make(chan *networkservice.Connection, 1)
+	factories := new(eventFactoryClient)
+	paths := &generationClient{
+		generations: make(map[string]int),
+	}
+	client := chain.NewNetworkServiceClient(
+		multipath.NewClient(ctx, multipath.WithFailoverCallback(func(conn *networkservice.Connection) {
+			failoverCh <- conn
+		})),
+		begin.NewClient(),
+		metadata.NewClient(),
+		clientconn.NewClient(new(monitorCC)),
+		heal.NewClient(ctx,
+			heal.WithLivenessCheck(livenessCheck),
+			heal.WithLivenessCheckInterval(10*time.Millisecond)),
+		factories,
+		paths,
+	)
+
+	conn, err := client.Request(ctx, request())
+	require.NoError(t, err)
+
+	// Refresh the standby path below the multipath client
+	factory, ok := factories.factories.Load(connID + multipath.StandbySuffix)
+	require.True(t, ok)
+	require.NoError(t, <-factory.Request())
+
+	// Heal reports the primary path down, the refreshed standby path becomes active
+	primaryDown.Store(true)
+	select {
+	case failoverConn := <-failoverCh:
+		require.Equal(t, connID, failoverConn.GetId())
+		require.Equal(t, "2", failoverConn.GetContext().GetExtraContext()["generation"])
+	case <-ctx.Done():
+		require.FailNow(t, "no failover")
+	}
+	primaryDown.Store(false)
+
+	_, err = client.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+// barrierClient blocks refreshes until the refreshes of both paths have arrived
+type barrierClient struct {
+	mu        sync.Mutex
+	refreshes int
+	ready     chan struct{}
+}
+
+func (c *barrierClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, _ ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn := request.GetConnection().Clone()
+	if conn.GetNetworkServiceEndpointName() == "" {
+		conn.NetworkServiceEndpointName = "nse-1"
+		return conn, nil
+	}
+
+	c.mu.Lock()
+	c.refreshes++
+	if c.refreshes == 2 {
+		close(c.ready)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-c.ready:
+		return conn, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *barrierClient) Close(_ context.Context, _ *networkservice.Connection, _ ...grpc.CallOption) (*empty.Empty, error) {
+	return &empty.Empty{}, nil
+}
+
+func TestMultipathClient_RefreshesPathsConcurrently(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	client := chain.NewNetworkServiceClient(multipath.NewClient(ctx), &barrierClient{ready: make(chan struct{})})
+
+	conn, err := client.Request(ctx, request())
+	require.NoError(t, err)
+
+	request := request()
+	request.Connection = conn
+	conn, err = client.Request(ctx, request)
+	require.NoError(t, err)
+
+	_, err = client.Close(ctx, conn)
+	require.NoError(t, err)
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package multipath provides a client chain element keeping two connections for every requested connection: the
+// primary one and a standby one ready to take over.
+//
+// Both paths are requested through the rest of the chain with their own connection IDs, so each of them is
+// refreshed and healed independently. When heal reports the active path down, the other path becomes active at
+// once without waiting for the reconnect. In ActiveActive mode both paths are used and the returned connection
+// carries the union of their routes. Refreshes of established paths are requested concurrently, connections
+// refreshed or healed below the element are reported back by heal (see heal.WithObserver), so the heal chain element
+// is required below multipath.
+//
+// The chain element should be placed at the head of the client chain, before begin (see client.WithMultipathClient).
+package multipath
@@ -0,0 +1,68 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multipath
+
+import (
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+)
+
+// Mode - how the paths are used
+type Mode int
+
+const (
+	// PrimaryBackup - only the active path is returned, the other one stays as a hot standby
+	PrimaryBackup Mode = iota
+	// ActiveActive - both paths are in use, the returned connection has the union of their routes
+	ActiveActive
+)
+
+const defaultRetryInterval = 5 * time.Second
+
+// Option - option for multipath.NewClient() chain element
+type Option func(c *multipathClient)
+
+// WithMode - sets how the paths are used. Default is PrimaryBackup.
+func WithMode(mode Mode) Option {
+	return func(c *multipathClient) {
+		c.mode = mode
+	}
+}
+
+// WithNSEClient - sets the registry client used to pin the standby path to an NSE different from the primary one.
+// Without it the NSE of the standby path is selected by the NSMgr.
+func WithNSEClient(nseClient registry.NetworkServiceEndpointRegistryClient) Option {
+	return func(c *multipathClient) {
+		c.nseClient = nseClient
+	}
+}
+
+// WithRetryInterval - sets interval between attempts to establish the standby path if it has failed
+func WithRetryInterval(retryInterval time.Duration) Option {
+	return func(c *multipathClient) {
+		c.retryInterval = retryInterval
+	}
+}
+
+// WithFailoverCallback - sets a callback called with the connection of the newly active path on failover
+func WithFailoverCallback(onFailover func(conn *networkservice.Connection)) Option {
+	return func(c *multipathClient) {
+		c.onFailover = onFailover
+	}
+}
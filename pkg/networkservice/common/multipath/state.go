@@ -0,0 +1,205 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multipath
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// StandbySuffix - suffix of the standby path connection ID
+const StandbySuffix = "-standby"
+
+const (
+	primaryPath = iota
+	standbyPath
+	pathCount
+)
+
+func pathID(id string, path int) string {
+	if path == standbyPath {
+		return id + StandbySuffix
+	}
+	return id
+}
+
+func parsePathID(pathID string) (id string, path int) {
+	if id, ok := strings.CutSuffix(pathID, StandbySuffix); ok {
+		return id, standbyPath
+	}
+	return pathID, primaryPath
+}
+
+type path struct {
+	conn *networkservice.Connection
+	up   bool
+}
+
+type state struct {
+	id string
+
+	// requestMu serializes Request, Close and standby retries
+	requestMu   sync.Mutex
+	cancelRetry context.CancelFunc
+
+	// mu guards paths and active, it is never held while calling the next chain element
+	mu     sync.Mutex
+	paths  [pathCount]path
+	active int
+}
+
+// request returns a request for the path: refreshes are built from the path's own connection, new paths are
+// built from the incoming request.
+func (s *state) request(request *networkservice.NetworkServiceRequest, i int) *networkservice.NetworkServiceRequest {
+	req := request.Clone()
+
+	if conn := s.conn(i); conn != nil {
+		conn.NetworkService = req.GetConnection().GetNetworkService()
+		conn.Labels = req.GetConnection().GetLabels()
+		conn.Payload = req.GetConnection().GetPayload()
+		req.Connection = conn
+		return req
+	}
+
+	req.GetConnection().Id = pathID(s.id, i)
+	req.GetConnection().Path = nil
+	if i == standbyPath {
+		req.GetConnection().NetworkServiceEndpointName = ""
+		req.GetConnection().Mechanism = nil
+		req.GetConnection().Context = nil
+	}
+	return req
+}
+
+func (s *state) conn(i int) *networkservice.Connection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.paths[i].conn.Clone()
+}
+
+func (s *state) established() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.paths {
+		if s.paths[i].conn != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// update stores the result of the path request and returns true if the active path has changed
+func (s *state) update(i int, conn *networkservice.Connection, err error) (switched bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case err == nil:
+		s.paths[i] = path{conn: conn.Clone(), up: true}
+	case s.paths[i].conn != nil:
+		s.paths[i].up = false
+	}
+	return s.elect()
+}
+
+// refresh stores the connection of the established path refreshed or healed below the multipath client and returns
+// true if the active path has changed
+func (s *state) refresh(i int, conn *networkservice.Connection) (switched bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.paths[i].conn == nil {
+		return false
+	}
+	s.paths[i] = path{conn: conn.Clone(), up: true}
+	return s.elect()
+}
+
+// stopRetry stops the standby retries once both paths are established, it must be called under requestMu
+func (s *state) stopRetry() {
+	if s.cancelRetry != nil && s.conn(primaryPath) != nil && s.conn(standbyPath) != nil {
+		s.cancelRetry()
+		s.cancelRetry = nil
+	}
+}
+
+// setDown marks the established path down and returns true if the active path has changed
+func (s *state) setDown(i int) (switched bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.paths[i].conn == nil {
+		return false
+	}
+	s.paths[i].up = false
+	return s.elect()
+}
+
+// elect makes the other path active if the active one is down. It returns true on failover from an established path.
+func (s *state) elect() bool {
+	other := pathCount - 1 - s.active
+	if s.paths[s.active].up || !s.paths[other].up {
+		return false
+	}
+	failover := s.paths[s.active].conn != nil
+	s.active = other
+	return failover
+}
+
+// result returns the connection of the active path, with the routes of the other path in ActiveActive mode
+func (s *state) result(mode Mode) *networkservice.Connection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conn := s.paths[s.active].conn.Clone()
+	if conn == nil {
+		return nil
+	}
+	conn.Id = s.id
+
+	if other := s.paths[pathCount-1-s.active]; mode == ActiveActive && other.up {
+		ipContext := other.conn.GetContext().GetIpContext()
+		if conn.GetContext() == nil {
+			conn.Context = &networkservice.ConnectionContext{}
+		}
+		if conn.GetContext().GetIpContext() == nil {
+			conn.GetContext().IpContext = &networkservice.IPContext{}
+		}
+		conn.GetContext().GetIpContext().SrcRoutes = unionRoutes(conn.GetContext().GetIpContext().GetSrcRoutes(), ipContext.GetSrcRoutes())
+		conn.GetContext().GetIpContext().DstRoutes = unionRoutes(conn.GetContext().GetIpContext().GetDstRoutes(), ipContext.GetDstRoutes())
+	}
+	return conn
+}
+
+func unionRoutes(routes, others []*networkservice.Route) []*networkservice.Route {
+	prefixes := make(map[string]struct{}, len(routes))
+	for _, route := range routes {
+		prefixes[route.GetPrefix()] = struct{}{}
+	}
+	for _, route := range others {
+		if _, ok := prefixes[route.GetPrefix()]; !ok {
+			prefixes[route.GetPrefix()] = struct{}{}
+			routes = append(routes, route.Clone())
+		}
+	}
+	return routes
+}
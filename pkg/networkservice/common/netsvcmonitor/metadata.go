@@ -1,4 +1,4 @@
-// Copyright (c) 2023 Cisco Systems, Inc.
+// Copyright (c) 2023-2024 Cisco Systems, Inc.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -19,6 +19,8 @@ package netsvcmonitor
 import (
 	"context"
 
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
 )
 
@@ -34,3 +36,41 @@ func loadCancelFunction(ctx context.Context) (func(), bool) {
 	}
 	return nil, false
 }
+
+// migrated keeps the endpoint the connection was migrated from and the connection it was migrated to. The client
+// doesn't know about the migration until its next refresh, so the refresh still requests the old endpoint and context.
+type migrated struct {
+	from string
+	to   *networkservice.Connection
+}
+
+// apply replaces the old endpoint with the one the connection was migrated to: the endpoint name, the context and the
+// path segments after the current one
+func (m *migrated) apply(conn *networkservice.Connection) {
+	to := m.to.Clone()
+	conn.NetworkServiceEndpointName = to.GetNetworkServiceEndpointName()
+	conn.Context = to.GetContext()
+
+	path, index := conn.GetPath(), int(conn.GetPath().GetIndex())
+	if len(path.GetPathSegments()) > index && len(to.GetPath().GetPathSegments()) > index {
+		path.PathSegments = append(path.GetPathSegments()[:index+1], to.GetPath().GetPathSegments()[index+1:]...)
+	}
+}
+
+type migratedKey struct{}
+
+func storeMigrated(ctx context.Context, m *migrated) {
+	metadata.Map(ctx, false).Store(migratedKey{}, m)
+}
+
+func loadMigrated(ctx context.Context) (*migrated, bool) {
+	v, ok := metadata.Map(ctx, false).Load(migratedKey{})
+	if ok {
+		return v.(*migrated), true
+	}
+	return nil, false
+}
+
+func deleteMigrated(ctx context.Context) {
+	metadata.Map(ctx, false).Delete(migratedKey{})
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netsvcmonitor
+
+import (
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+// migration schedules the reselect of a connection which endpoint doesn't match the network service: it waits for the
+// grace period first and then for the slot reserved in the pacer
+type migration struct {
+	clock       clock.Clock
+	gracePeriod time.Duration
+	pacer       *pacer
+	timer       clock.Timer
+	paced       bool
+}
+
+// update starts the migration if the endpoint doesn't match and cancels it if the endpoint matches again
+func (mg *migration) update(matches bool) {
+	switch {
+	case matches:
+		mg.stop()
+	case mg.timer == nil:
+		mg.timer = mg.clock.Timer(mg.gracePeriod)
+		mg.paced = false
+	}
+}
+
+// C returns the channel receiving when the migration should be checked, or nil if there is no migration scheduled
+func (mg *migration) C() <-chan time.Time {
+	if mg.timer == nil {
+		return nil
+	}
+	return mg.timer.C()
+}
+
+// ready is called on receive from C, it returns true if the migration can be done right now
+func (mg *migration) ready() bool {
+	if mg.paced {
+		return true
+	}
+	mg.paced = true
+	if delay := mg.pacer.reserve(mg.clock.Now()); delay > 0 {
+		mg.timer.Reset(delay)
+		return false
+	}
+	return true
+}
+
+func (mg *migration) stop() {
+	if mg.timer != nil {
+		mg.timer.Stop()
+		mg.timer = nil
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netsvcmonitor
+
+import "time"
+
+// Option changes default settings for the netsvcmonitor server
+type Option func(*monitorServer)
+
+// WithMigrationRate limits the number of connections migrated to another endpoint per second. All connections of the
+// server share the limit, so an update of a network service doesn't reselect all its connections at once.
+// By default migrations are not limited.
+func WithMigrationRate(perSecond int) Option {
+	return func(m *monitorServer) {
+		m.pacer = newPacer(perSecond)
+	}
+}
+
+// WithGracePeriod sets how long the connection is kept on an endpoint that doesn't match the network service anymore
+// before the migration. If the endpoint matches again during the grace period, the migration is canceled.
+// By default the migration starts immediately.
+func WithGracePeriod(gracePeriod time.Duration) Option {
+	return func(m *monitorServer) {
+		m.gracePeriod = gracePeriod
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2023 Cisco Systems, Inc.
+// Copyright (c) 2023-2024 Cisco Systems, Inc.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -15,6 +15,9 @@
 // limitations under the License.
 
 // Package netsvcmonitor provides a NetworkServiceServer chain element to provide a possible change nse for the connection immediately if network service was updated.
+// The connection is refreshed with the endpoint reselected, so the client keeps its connection while it migrates to
+// the matching endpoint. The path to the old endpoint is closed only once the new one is established, see dial. The
+// connection is closed only if the reselect fails.
 package netsvcmonitor
 
 import (
@@ -22,29 +25,41 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/registry"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/begin"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/tombstone"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 	"github.com/networkservicemesh/sdk/pkg/tools/log"
 	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
 )
 
 type monitorServer struct {
-	chainCtx  context.Context
-	nsClient  registry.NetworkServiceRegistryClient
-	nseClient registry.NetworkServiceEndpointRegistryClient
+	chainCtx    context.Context
+	nsClient    registry.NetworkServiceRegistryClient
+	nseClient   registry.NetworkServiceEndpointRegistryClient
+	pacer       *pacer
+	gracePeriod time.Duration
 }
 
 // NewServer creates a new instance of netsvcmonitor server that allowes to the server chain monitor changes in the network service
-func NewServer(chainCtx context.Context, nsClient registry.NetworkServiceRegistryClient, nseClient registry.NetworkServiceEndpointRegistryClient) networkservice.NetworkServiceServer {
-	return &monitorServer{
+func NewServer(chainCtx context.Context, nsClient registry.NetworkServiceRegistryClient, nseClient registry.NetworkServiceEndpointRegistryClient, opts ...Option) networkservice.NetworkServiceServer {
+	var m = &monitorServer{
 		chainCtx:  chainCtx,
 		nsClient:  nsClient,
 		nseClient: nseClient,
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 func (m *monitorServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
@@ -52,76 +67,167 @@ func (m *monitorServer) Request(ctx context.Context, request *networkservice.Net
 		cancel()
 	}
 
+	var nseName = request.GetConnection().GetNetworkServiceEndpointName()
+	if mg, ok := loadMigrated(ctx); ok && mg.to != nil {
+		switch nseName {
+		case mg.from:
+			mg.apply(request.GetConnection())
+		case mg.to.GetNetworkServiceEndpointName():
+			// The client already knows about the migration
+			deleteMigrated(ctx)
+		}
+	}
+
 	resp, err := next.Server(ctx).Request(ctx, request)
 	if err != nil {
 		return resp, err
 	}
+	if mg, ok := loadMigrated(ctx); ok && mg.to == nil && nseName == "" {
+		mg.to = resp.Clone()
+	}
 
 	var conn = resp.Clone()
+	if conn.GetNetworkServiceEndpointName() == "" {
+		return resp, err
+	}
 
 	var monitorCtx, cancel = context.WithCancel(m.chainCtx)
 
 	storeCancelFunction(ctx, cancel)
 
+	go m.monitor(monitorCtx, ctx, conn)
+
+	return resp, err
+}
+
+// monitor migrates the connection once its endpoint doesn't match the network service anymore. The network service and
+// the endpoint are watched by name, so the watches are shared if nsClient and nseClient share them, see watchhub.
+func (m *monitorServer) monitor(monitorCtx, ctx context.Context, conn *networkservice.Connection) {
 	var logger = log.FromContext(ctx).WithField("monitorServer", "Find")
 
-	var monitorNetworkServiceGoroutine = func() {
-		for ; monitorCtx.Err() == nil; time.Sleep(time.Millisecond * 100) {
-			// nolint:govet
-			var stream, err = m.nsClient.Find(monitorCtx, &registry.NetworkServiceQuery{
-				Watch: true,
-				NetworkService: &registry.NetworkService{
-					Name: conn.GetNetworkService(),
-				},
-			})
-			if err != nil {
-				logger.Errorf("an error happened during finding network service: %v", err.Error())
-				continue
-			}
+	var state = &watchState{conn: conn}
+	var mg = &migration{
+		clock:       clock.FromContext(m.chainCtx),
+		gracePeriod: m.gracePeriod,
+		pacer:       m.pacer,
+	}
+	defer mg.stop()
+
+	for ; monitorCtx.Err() == nil; time.Sleep(time.Millisecond * 100) {
+		watchCtx, cancelWatch := context.WithCancel(monitorCtx)
+		var streams = new(watchStreams)
+		networkServiceCh, endpointCh, err := m.watch(watchCtx, conn, state, streams)
+		if err != nil {
+			cancelWatch()
+			logger.Errorf("an error happened during watching network service: %v", err.Error())
+			continue
+		}
 
-			var networkServiceCh = registry.ReadNetworkServiceChannel(stream)
-			var netsvcStreamIsAlive = true
-
-			for netsvcStreamIsAlive && monitorCtx.Err() == nil {
-				select {
-				case <-monitorCtx.Done():
-					return
-				case netsvc, ok := <-networkServiceCh:
-					if !ok {
-						netsvcStreamIsAlive = false
-						break
-					}
-
-					nseStream, err := m.nseClient.Find(monitorCtx, &registry.NetworkServiceEndpointQuery{
-						NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{
-							Name: conn.GetNetworkServiceEndpointName(),
-						},
-					})
-					if err != nil {
-						logger.Errorf("an error happened during finding nse: %v", err.Error())
-						break
-					}
-
-					var nses = registry.ReadNetworkServiceEndpointList(nseStream)
-
-					if len(nses) == 0 {
-						continue
-					}
-
-					if len(matchutils.MatchEndpoint(resp.GetLabels(), netsvc.GetNetworkService(), nses...)) == 0 {
-						begin.FromContext(ctx).Close()
-						logger.Warnf("nse %v doesn't match with networkservice: %v", conn.GetNetworkServiceEndpointName(), conn.GetNetworkService())
-
-						return
-					}
+		for streamsAreAlive := true; streamsAreAlive; {
+			select {
+			case <-monitorCtx.Done():
+				cancelWatch()
+				return
+			case resp, ok := <-networkServiceCh:
+				if streamsAreAlive = ok; ok {
+					state.onNetworkService(resp)
+				}
+			case resp, ok := <-endpointCh:
+				if streamsAreAlive = ok; ok {
+					state.onEndpoint(resp)
 				}
+			case <-mg.C():
+				if !mg.ready() {
+					continue
+				}
+				cancelWatch()
+				m.migrate(ctx, conn, logger)
+
+				return
 			}
+
+			if streamsAreAlive {
+				mg.update(state.matches())
+			}
+		}
+		cancelWatch()
+		// Both streams are drained to get their errors and trailers
+		for range networkServiceCh {
+		}
+		for range endpointCh {
 		}
+		state.onClosed(streams)
+		mg.update(state.matches())
 	}
+}
 
-	go monitorNetworkServiceGoroutine()
+// migrate reselects the endpoint of the connection, the connection is closed if there is no matching endpoint
+func (m *monitorServer) migrate(ctx context.Context, conn *networkservice.Connection, logger log.Logger) {
+	logger.Warnf("nse %v doesn't match with networkservice: %v, reselecting", conn.GetNetworkServiceEndpointName(), conn.GetNetworkService())
 
-	return resp, err
+	storeMigrated(ctx, &migrated{from: conn.GetNetworkServiceEndpointName()})
+	if err := <-begin.FromContext(ctx).Request(begin.WithReselectEndpoint()); err != nil {
+		deleteMigrated(ctx)
+		logger.Errorf("failed to reselect nse for the connection %v, closing: %v", conn.GetId(), err.Error())
+		begin.FromContext(ctx).Close()
+	}
+}
+
+func (m *monitorServer) watch(
+	ctx context.Context,
+	conn *networkservice.Connection,
+	state *watchState,
+	streams *watchStreams,
+) (<-chan *registry.NetworkServiceResponse, <-chan *registry.NetworkServiceEndpointResponse, error) {
+	nsStream, err := m.nsClient.Find(findoptions.WithOptions(ctx, &findoptions.Options{
+		WatchRevisions: true,
+		FromRevision:   state.revision,
+	}), &registry.NetworkServiceQuery{
+		Watch: true,
+		NetworkService: &registry.NetworkService{
+			Name: conn.GetNetworkService(),
+		},
+	}, grpc.Trailer(&streams.nsTrailer))
+	if err != nil {
+		return nil, nil, err
+	}
+	nseStream, err := m.nseClient.Find(findoptions.WithOptions(ctx, &findoptions.Options{
+		WatchRevisions: true,
+		FromRevision:   state.endpointRevision,
+		DeleteReasons:  true,
+	}), &registry.NetworkServiceEndpointQuery{
+		Watch: true,
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{
+			Name: conn.GetNetworkServiceEndpointName(),
+		},
+	}, grpc.Trailer(&streams.nseTrailer))
+	if err != nil {
+		return nil, nil, err
+	}
+	return readChannel[*registry.NetworkServiceResponse](nsStream, &streams.nsErr),
+		readChannel[*registry.NetworkServiceEndpointResponse](nseStream, &streams.nseErr), nil
+}
+
+// readChannel is registry.ReadNetworkServiceChannel keeping the error the stream is closed with, err is set before the
+// channel is closed
+func readChannel[T any](stream interface {
+	Recv() (T, error)
+	Context() context.Context
+}, err *error) <-chan T {
+	result := make(chan T)
+	go func() {
+		defer close(result)
+		var msg T
+		for msg, *err = stream.Recv(); *err == nil; msg, *err = stream.Recv() {
+			select {
+			case result <- msg:
+				continue
+			case <-stream.Context().Done():
+				return
+			}
+		}
+	}()
+	return result
 }
 
 func (m *monitorServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
@@ -131,3 +237,73 @@ func (m *monitorServer) Close(ctx context.Context, conn *networkservice.Connecti
 
 	return next.Server(ctx).Close(ctx, conn)
 }
+
+// watchState keeps the last received network service and endpoint of the connection
+type watchState struct {
+	conn *networkservice.Connection
+	// revisions reported by the closed network service and endpoint streams, the next streams are resumed from them
+	revision         uint64
+	endpointRevision uint64
+	netsvc           *registry.NetworkService
+	nse              *registry.NetworkServiceEndpoint
+	// forced is set if the endpoint was removed against the will of its owner, see tombstone.Reason
+	forced bool
+}
+
+func (s *watchState) onNetworkService(resp *registry.NetworkServiceResponse) {
+	if resp.GetNetworkService().GetName() == s.conn.GetNetworkService() && !resp.GetDeleted() {
+		s.netsvc = resp.GetNetworkService()
+	}
+}
+
+func (s *watchState) onEndpoint(resp *registry.NetworkServiceEndpointResponse) {
+	if resp.GetNetworkServiceEndpoint().GetName() != s.conn.GetNetworkServiceEndpointName() {
+		return
+	}
+	// forced is kept until the endpoint is registered again, the reason of the deletion is received on the stream close
+	if resp.GetDeleted() {
+		s.nse = nil
+		return
+	}
+	s.nse = resp.GetNetworkServiceEndpoint()
+	s.forced = false
+}
+
+// onClosed handles the closed streams. They are resumed from the revisions they have reported on close, see
+// findoptions.Revision, the lagging ones resync the full state. The endpoint stream is closed by the registry once it
+// has sent the reason of the endpoint deletion, see tombstone.TrailerReason.
+func (s *watchState) onClosed(streams *watchStreams) {
+	s.revision = resumeRevision(s.revision, streams.nsTrailer, streams.nsErr)
+	s.endpointRevision = resumeRevision(s.endpointRevision, streams.nseTrailer, streams.nseErr)
+	if s.nse == nil && tombstone.TrailerReason(streams.nseTrailer, s.conn.GetNetworkServiceEndpointName()).Forced() {
+		s.forced = true
+	}
+}
+
+// matches returns false if the endpoint was forcibly removed or if both the network service and the endpoint are known
+// and they don't match. Gracefully deleted and expired endpoints are left to heal.
+func (s *watchState) matches() bool {
+	if s.forced {
+		return false
+	}
+	if s.netsvc == nil || s.nse == nil {
+		return true
+	}
+	return len(matchutils.MatchEndpoint(s.conn.GetLabels(), s.netsvc, s.nse)) > 0
+}
+
+// watchStreams keeps the errors and the trailers of the closed network service and endpoint streams
+type watchStreams struct {
+	nsErr, nseErr         error
+	nsTrailer, nseTrailer metadata.MD
+}
+
+func resumeRevision(revision uint64, trailer metadata.MD, err error) uint64 {
+	if memory.IsResyncRequired(err) {
+		return 0
+	}
+	if resumed := findoptions.Revision(trailer); resumed > 0 {
+		return resumed
+	}
+	return revision
+}
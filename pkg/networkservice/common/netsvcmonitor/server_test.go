@@ -1,4 +1,4 @@
-// Copyright (c) 2023 Cisco Systems, Inc.
+// Copyright (c) 2023-2024 Cisco Systems, Inc.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -19,20 +19,28 @@ package netsvcmonitor_test
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/registry"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/begin"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/netsvcmonitor"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/count"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/tombstone"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
 )
 
 func Test_Netsvcmonitor_And_GroupOfSimilarNetworkServices(t *testing.T) {
@@ -91,3 +99,251 @@ func Test_Netsvcmonitor_And_GroupOfSimilarNetworkServices(t *testing.T) {
 		return counter.Closes() > 0
 	}, time.Millisecond*300, time.Millisecond*50)
 }
+
+type reselectServer struct {
+	nseName   atomic.Value
+	requested atomic.Value
+	reselects int32
+}
+
+func (s *reselectServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if request.GetConnection().GetNetworkServiceEndpointName() == "" {
+		atomic.AddInt32(&s.reselects, 1)
+		request.GetConnection().NetworkServiceEndpointName = s.nseName.Load().(string)
+	}
+	s.requested.Store(request.GetConnection().GetNetworkServiceEndpointName())
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *reselectServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *reselectServer) Reselects() int {
+	return int(atomic.LoadInt32(&s.reselects))
+}
+
+type migrationSetup struct {
+	nsServer  registry.NetworkServiceRegistryServer
+	nseServer registry.NetworkServiceEndpointRegistryServer
+	reselect  *reselectServer
+	counter   *count.Server
+	server    networkservice.NetworkServiceServer
+}
+
+func newMigrationSetup(ctx context.Context, t *testing.T, opts ...netsvcmonitor.Option) *migrationSetup {
+	var s = &migrationSetup{
+		nsServer:  memory.NewNetworkServiceRegistryServer(),
+		nseServer: memory.NewNetworkServiceEndpointRegistryServer(),
+		reselect:  new(reselectServer),
+		counter:   new(count.Server),
+	}
+
+	s.reselect.nseName.Store("endpoint-2")
+	s.registerNetworkService(t, "red")
+
+	for i, color := range []string{"red", "blue"} {
+		_, err := s.nseServer.Register(context.Background(), &registry.NetworkServiceEndpoint{
+			Name:                fmt.Sprintf("endpoint-%v", i+1),
+			NetworkServiceNames: []string{"service-1"},
+			NetworkServiceLabels: map[string]*registry.NetworkServiceLabels{
+				"service-1": {Labels: map[string]string{"color": color}},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	s.server = chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		begin.NewServer(),
+		netsvcmonitor.NewServer(
+			ctx,
+			adapters.NetworkServiceServerToClient(s.nsServer),
+			adapters.NetworkServiceEndpointServerToClient(s.nseServer),
+			opts...,
+		),
+		s.reselect,
+		s.counter,
+	)
+	return s
+}
+
+func (s *migrationSetup) registerNetworkService(t *testing.T, color string) {
+	_, err := s.nsServer.Register(context.Background(), &registry.NetworkService{
+		Name: "service-1",
+		Matches: []*registry.Match{
+			{
+				Routes: []*registry.Destination{
+					{DestinationSelector: map[string]string{"color": color}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func (s *migrationSetup) request(ctx context.Context, t *testing.T, id string) *networkservice.Connection {
+	conn, err := s.server.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:                         id,
+			NetworkService:             "service-1",
+			NetworkServiceEndpointName: "endpoint-1",
+		},
+	})
+	require.NoError(t, err)
+	return conn
+}
+
+func Test_Netsvcmonitor_MigratesConnection(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	var testCtx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var s = newMigrationSetup(testCtx, t)
+	var conn = s.request(testCtx, t, "1")
+
+	s.registerNetworkService(t, "blue")
+
+	require.Eventually(t, func() bool {
+		return s.reselect.Reselects() == 1
+	}, time.Second/2, time.Millisecond*10)
+	require.Never(t, func() bool {
+		return s.reselect.Reselects() > 1 || s.counter.Closes() > 0
+	}, time.Millisecond*200, time.Millisecond*20)
+
+	// The client doesn't know about the migration yet, its refresh keeps the new endpoint
+	_, err := s.server.Request(testCtx, &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Equal(t, "endpoint-2", s.reselect.requested.Load())
+
+	_, err = s.server.Close(testCtx, conn)
+	require.NoError(t, err)
+}
+
+func Test_Netsvcmonitor_MigratesFromForciblyRemovedEndpoint(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	var testCtx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var s = newMigrationSetup(testCtx, t)
+	s.request(testCtx, t, "1")
+
+	// Both endpoints match the network service, the first one is removed as unreachable
+	_, err := s.nseServer.Register(testCtx, &registry.NetworkServiceEndpoint{
+		Name:                "endpoint-2",
+		NetworkServiceNames: []string{"service-1"},
+		NetworkServiceLabels: map[string]*registry.NetworkServiceLabels{
+			"service-1": {Labels: map[string]string{"color": "red"}},
+		},
+	})
+	require.NoError(t, err)
+	_, err = s.nseServer.Unregister(tombstone.WithReason(testCtx, tombstone.Unreachable), &registry.NetworkServiceEndpoint{Name: "endpoint-1"})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return s.reselect.Reselects() == 1
+	}, time.Second/2, time.Millisecond*10)
+	require.Never(t, func() bool {
+		return s.reselect.Reselects() > 1 || s.counter.Closes() > 0
+	}, time.Millisecond*200, time.Millisecond*20)
+}
+
+func Test_Netsvcmonitor_KeepsGracefullyRemovedEndpoint(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	var testCtx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var s = newMigrationSetup(testCtx, t)
+	s.request(testCtx, t, "1")
+
+	_, err := s.nseServer.Unregister(testCtx, &registry.NetworkServiceEndpoint{Name: "endpoint-1"})
+	require.NoError(t, err)
+
+	require.Never(t, func() bool {
+		return s.reselect.Reselects() > 0 || s.counter.Closes() > 0
+	}, time.Millisecond*200, time.Millisecond*20)
+}
+
+func Test_Netsvcmonitor_ClosesIfReselectFails(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	var testCtx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var s = newMigrationSetup(testCtx, t)
+	s.server = chain.NewNetworkServiceServer(s.server, injecterror.NewServer(
+		injecterror.WithRequestErrorTimes(1),
+		injecterror.WithCloseErrorTimes(),
+	))
+	s.request(testCtx, t, "1")
+
+	s.registerNetworkService(t, "blue")
+
+	require.Eventually(t, func() bool {
+		return s.counter.Closes() == 1
+	}, time.Second/2, time.Millisecond*10)
+}
+
+func Test_Netsvcmonitor_GracePeriod(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	var testCtx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var clockMock = clockmock.New(testCtx)
+	var s = newMigrationSetup(clock.WithClock(testCtx, clockMock), t, netsvcmonitor.WithGracePeriod(time.Minute))
+	s.request(testCtx, t, "1")
+
+	// Endpoint matches again during the grace period
+	s.registerNetworkService(t, "blue")
+	time.Sleep(time.Millisecond * 100)
+	s.registerNetworkService(t, "red")
+	time.Sleep(time.Millisecond * 100)
+	clockMock.Add(time.Minute)
+	require.Never(t, func() bool {
+		return s.reselect.Reselects() > 0
+	}, time.Millisecond*200, time.Millisecond*20)
+
+	s.registerNetworkService(t, "blue")
+	require.Eventually(t, func() bool {
+		clockMock.Add(time.Minute)
+		return s.reselect.Reselects() == 1
+	}, time.Second/2, time.Millisecond*10)
+	require.Equal(t, 0, s.counter.Closes())
+}
+
+func Test_Netsvcmonitor_MigrationRate(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	var testCtx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var clockMock = clockmock.New(testCtx)
+	var s = newMigrationSetup(clock.WithClock(testCtx, clockMock), t, netsvcmonitor.WithMigrationRate(2))
+	for i := 0; i < 4; i++ {
+		s.request(testCtx, t, fmt.Sprint(i))
+	}
+
+	s.registerNetworkService(t, "blue")
+
+	require.Eventually(t, func() bool {
+		return s.reselect.Reselects() == 1
+	}, time.Second/2, time.Millisecond*10)
+	require.Never(t, func() bool {
+		return s.reselect.Reselects() > 1
+	}, time.Millisecond*100, time.Millisecond*20)
+
+	clockMock.Add(time.Second / 2)
+	require.Eventually(t, func() bool {
+		return s.reselect.Reselects() == 2
+	}, time.Second/2, time.Millisecond*10)
+
+	clockMock.Add(time.Second)
+	require.Eventually(t, func() bool {
+		return s.reselect.Reselects() == 4
+	}, time.Second/2, time.Millisecond*10)
+	require.Equal(t, 0, s.counter.Closes())
+}
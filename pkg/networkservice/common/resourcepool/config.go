@@ -0,0 +1,80 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcepool
+
+import (
+	"context"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/fs"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const (
+	// DefaultConfigPath is path to the default resource pool config file
+	DefaultConfigPath = "/var/lib/networkservicemesh/config/resourcepool.yaml"
+	// DefaultSysfsPath is path to the default sysfs mount
+	DefaultSysfsPath = "/sys"
+)
+
+// Config is a resource pool config
+type Config struct {
+	// PhysicalFunctions - key is PCI address of the physical function
+	PhysicalFunctions map[string]*PhysicalFunction `json:"physicalFunctions"`
+}
+
+// PhysicalFunction is a config of the SR-IOV physical function, all its virtual functions are added to the pool
+type PhysicalFunction struct {
+	// Capabilities - labels of the virtual functions requested by the device token ID
+	Capabilities []string `json:"capabilities"`
+}
+
+// ParseConfig parses the YAML config
+func ParseConfig(bytes []byte) (*Config, error) {
+	config := new(Config)
+	if err := yaml.Unmarshal(bytes, config); err != nil {
+		return nil, errors.Wrap(err, "failed to parse resource pool config")
+	}
+	for pfPCIAddress, pf := range config.PhysicalFunctions {
+		if pf == nil || len(pf.Capabilities) == 0 {
+			return nil, errors.Errorf("physical function %s has no capabilities", pfPCIAddress)
+		}
+	}
+	return config, nil
+}
+
+func watchConfig(ctx context.Context, path string) <-chan *Config {
+	var ch = make(chan *Config)
+	go func() {
+		logger := log.FromContext(ctx).WithField("resourcePoolServer", "watchConfig")
+		for bytes := range fs.WatchFile(ctx, path) {
+			config, err := ParseConfig(bytes)
+			if err != nil {
+				logger.Error(err.Error())
+				continue
+			}
+			select {
+			case ch <- config:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
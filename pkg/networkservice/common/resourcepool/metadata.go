@@ -1,4 +1,4 @@
-// Copyright (c) 2021 Doc.ai and/or its affiliates.
+// Copyright (c) 2024 Cisco and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -14,7 +14,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package vni
+package resourcepool
 
 import (
 	"context"
@@ -24,28 +24,24 @@ import (
 
 type key struct{}
 
-func store(ctx context.Context, isClient bool, vni uint32) {
-	metadata.Map(ctx, isClient).Store(key{}, vni)
+func store(ctx context.Context, isClient bool, d *device) {
+	metadata.Map(ctx, isClient).Store(key{}, d)
 }
 
-func delete(ctx context.Context, isClient bool) {
-	metadata.Map(ctx, isClient).Delete(key{})
-}
-
-func load(ctx context.Context, isClient bool) (value uint32, ok bool) {
+func load(ctx context.Context, isClient bool) (value *device, ok bool) {
 	rawValue, ok := metadata.Map(ctx, isClient).Load(key{})
 	if !ok {
-		return
+		return nil, false
 	}
-	value, ok = rawValue.(uint32)
+	value, ok = rawValue.(*device)
 	return value, ok
 }
 
-func loadOrStore(ctx context.Context, isClient bool, vni uint32) (value uint32, ok bool) {
-	rawValue, ok := metadata.Map(ctx, isClient).LoadOrStore(key{}, vni)
+func loadAndDelete(ctx context.Context, isClient bool) (value *device, ok bool) {
+	rawValue, ok := metadata.Map(ctx, isClient).LoadAndDelete(key{})
 	if !ok {
-		return
+		return nil, false
 	}
-	value, ok = rawValue.(uint32)
+	value, ok = rawValue.(*device)
 	return value, ok
 }
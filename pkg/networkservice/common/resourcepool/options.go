@@ -0,0 +1,47 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcepool
+
+type options struct {
+	configPath string
+	updateCh   <-chan *Config
+	sysfsPath  string
+}
+
+// Option is an option pattern for resource pool server
+type Option func(o *options)
+
+// WithConfigPath sets path of the config file to watch
+func WithConfigPath(path string) Option {
+	return func(o *options) {
+		o.configPath = path
+	}
+}
+
+// WithConfigUpdateChannel sets channel of the config updates instead of watching the config file
+func WithConfigUpdateChannel(ch <-chan *Config) Option {
+	return func(o *options) {
+		o.updateCh = ch
+	}
+}
+
+// WithSysfsPath sets path of the sysfs mount
+func WithSysfsPath(path string) Option {
+	return func(o *options) {
+		o.sysfsPath = path
+	}
+}
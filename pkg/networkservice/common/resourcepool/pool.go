@@ -0,0 +1,92 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcepool
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// pool tracks allocation of the devices
+type pool struct {
+	lock    sync.Mutex
+	devices map[string]*device
+}
+
+// update replaces the pool devices. Allocated devices are kept until they are freed even if they are removed from
+// the config.
+func (p *pool) update(devices map[string]*device) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for pciAddress, old := range p.devices {
+		if old.owner == "" {
+			continue
+		}
+		if d, ok := devices[pciAddress]; ok {
+			d.owner = old.owner
+		} else {
+			devices[pciAddress] = old
+		}
+	}
+	p.devices = devices
+}
+
+// allocate allocates a free device with the capability to the owner. Devices are sorted by PCI address, so the
+// allocation is predictable.
+func (p *pool) allocate(capability, owner string) (*device, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var candidates []*device
+	for _, d := range p.devices {
+		if d.owner == "" && hasCapability(d, capability) {
+			candidates = append(candidates, d)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, errors.Errorf("no free devices with capability %q", capability)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].pciAddress < candidates[j].pciAddress
+	})
+	d := candidates[0]
+	d.owner = owner
+	result := *d
+	return &result, nil
+}
+
+// free frees the device allocated by the owner
+func (p *pool) free(pciAddress, owner string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if d, ok := p.devices[pciAddress]; ok && d.owner == owner {
+		d.owner = ""
+	}
+}
+
+func hasCapability(d *device, capability string) bool {
+	for _, c := range d.capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resourcepool provides chain element allocating SR-IOV virtual functions to the kernel and vfio mechanisms.
+// Virtual functions of the physical functions listed in the config are discovered in sysfs and requested by the
+// capability label passed in the mechanism device token ID.
+package resourcepool
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/vfio"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type resourcePoolServer struct {
+	pool pool
+}
+
+// NewServer - returns a new server chain element allocating virtual functions from the pool. The virtual function is
+// freed on Close, so the expired connections closed by the timeout chain element free their devices too.
+// By default watches config file by DefaultConfigPath.
+func NewServer(chainCtx context.Context, opts ...Option) networkservice.NetworkServiceServer {
+	o := &options{
+		configPath: DefaultConfigPath,
+		sysfsPath:  DefaultSysfsPath,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.updateCh == nil {
+		o.updateCh = watchConfig(chainCtx, o.configPath)
+	}
+
+	s := new(resourcePoolServer)
+	go func() {
+		logger := log.FromContext(chainCtx).WithField("resourcePoolServer", "update")
+		for {
+			select {
+			case <-chainCtx.Done():
+				return
+			case config, ok := <-o.updateCh:
+				if !ok {
+					return
+				}
+				devices, err := discover(o.sysfsPath, config)
+				if err != nil {
+					logger.Error(err.Error())
+					continue
+				}
+				s.pool.update(devices)
+				logger.Infof("updated resource pool: %d virtual functions", len(devices))
+			}
+		}
+	}()
+	return s
+}
+
+func (s *resourcePoolServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	mechanism := request.GetConnection().GetMechanism()
+	capability := deviceTokenID(mechanism)
+	if capability == "" {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	logger := log.FromContext(ctx).WithField("resourcePoolServer", "request")
+	isClient := metadata.IsClient(s)
+
+	d, loaded := load(ctx, isClient)
+	if !loaded {
+		var err error
+		if d, err = s.pool.allocate(capability, request.GetConnection().GetId()); err != nil {
+			return nil, err
+		}
+		store(ctx, isClient, d)
+		logger.WithField("pciAddress", d.pciAddress).Debugf("virtual function allocated")
+	}
+	setDevice(mechanism, d)
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil && !loaded {
+		if d, ok := loadAndDelete(ctx, isClient); ok {
+			s.pool.free(d.pciAddress, d.owner)
+		}
+	}
+	return conn, err
+}
+
+func (s *resourcePoolServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if d, ok := loadAndDelete(ctx, metadata.IsClient(s)); ok {
+		s.pool.free(d.pciAddress, d.owner)
+		log.FromContext(ctx).WithField("resourcePoolServer", "close").WithField("pciAddress", d.pciAddress).Debugf("virtual function freed")
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func deviceTokenID(m *networkservice.Mechanism) string {
+	if mechanism := kernel.ToMechanism(m); mechanism != nil {
+		return mechanism.GetDeviceTokenID()
+	}
+	if mechanism := vfio.ToMechanism(m); mechanism != nil {
+		return mechanism.GetDeviceTokenID()
+	}
+	return ""
+}
+
+func setDevice(m *networkservice.Mechanism, d *device) {
+	if mechanism := kernel.ToMechanism(m); mechanism != nil {
+		mechanism.SetPCIAddress(d.pciAddress)
+	}
+	if mechanism := vfio.ToMechanism(m); mechanism != nil {
+		mechanism.SetPCIAddress(d.pciAddress)
+		if d.iommuGroup != noIommuGroup {
+			mechanism.SetIommuGroup(uint(d.iommuGroup))
+		}
+	}
+}
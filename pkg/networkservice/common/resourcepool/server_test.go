@@ -0,0 +1,216 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcepool_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/vfio"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/resourcepool"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+const (
+	pf1 = "0000:01:00.0"
+	pf2 = "0000:02:00.0"
+
+	configYAML = `
+physicalFunctions:
+  0000:01:00.0:
+    capabilities: [intel, 10G]
+  0000:02:00.0:
+    capabilities: [mellanox]
+`
+)
+
+// newSysfs creates a fake sysfs tree with two virtual functions per physical function
+func newSysfs(t *testing.T) string {
+	sysfs := t.TempDir()
+	devices := filepath.Join(sysfs, "bus", "pci", "devices")
+
+	vfs := map[string][]string{
+		pf1: {"0000:01:02.0", "0000:01:02.1"},
+		pf2: {"0000:02:02.0", "0000:02:02.1"},
+	}
+	group := 10
+	for pf, pfVFs := range vfs {
+		require.NoError(t, os.MkdirAll(filepath.Join(devices, pf), os.ModePerm))
+		for i, vf := range pfVFs {
+			require.NoError(t, os.MkdirAll(filepath.Join(devices, vf), os.ModePerm))
+			require.NoError(t, os.Symlink(filepath.Join("..", vf), filepath.Join(devices, pf, "virtfn"+strconv.Itoa(i))))
+			require.NoError(t, os.Symlink(filepath.Join("..", "..", "..", "..", "kernel", "iommu_groups", strconv.Itoa(group)), filepath.Join(devices, vf, "iommu_group")))
+			group++
+		}
+	}
+	return sysfs
+}
+
+func newRequest(id string, mechanism *networkservice.Mechanism) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:        id,
+			Mechanism: mechanism,
+		},
+	}
+}
+
+func kernelMechanism(tokenID string) *networkservice.Mechanism {
+	m := kernel.New("")
+	kernel.ToMechanism(m).SetDeviceTokenID(tokenID)
+	return m
+}
+
+func newServer(ctx context.Context, t *testing.T, opts ...resourcepool.Option) networkservice.NetworkServiceServer {
+	config, err := resourcepool.ParseConfig([]byte(configYAML))
+	require.NoError(t, err)
+
+	updateCh := make(chan *resourcepool.Config, 1)
+	updateCh <- config
+
+	return next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		resourcepool.NewServer(ctx, append([]resourcepool.Option{
+			resourcepool.WithSysfsPath(newSysfs(t)),
+			resourcepool.WithConfigUpdateChannel(updateCh),
+		}, opts...)...),
+	)
+}
+
+func requestEventually(ctx context.Context, t *testing.T, s networkservice.NetworkServiceServer, request *networkservice.NetworkServiceRequest) *networkservice.Connection {
+	var conn *networkservice.Connection
+	require.Eventually(t, func() bool {
+		var err error
+		conn, err = s.Request(ctx, request.Clone())
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	return conn
+}
+
+func TestResourcePool_AllocateAndFree(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := newServer(ctx, t)
+
+	conn1 := requestEventually(ctx, t, s, newRequest("conn-1", kernelMechanism("10G")))
+	require.Equal(t, "0000:01:02.0", kernel.ToMechanism(conn1.GetMechanism()).GetPCIAddress())
+
+	// Refresh keeps the device
+	conn1, err := s.Request(ctx, newRequest("conn-1", conn1.GetMechanism()))
+	require.NoError(t, err)
+	require.Equal(t, "0000:01:02.0", kernel.ToMechanism(conn1.GetMechanism()).GetPCIAddress())
+
+	conn2, err := s.Request(ctx, newRequest("conn-2", kernelMechanism("intel")))
+	require.NoError(t, err)
+	require.Equal(t, "0000:01:02.1", kernel.ToMechanism(conn2.GetMechanism()).GetPCIAddress())
+
+	_, err = s.Request(ctx, newRequest("conn-3", kernelMechanism("intel")))
+	require.Error(t, err)
+
+	_, err = s.Close(ctx, conn1)
+	require.NoError(t, err)
+
+	conn3, err := s.Request(ctx, newRequest("conn-3", kernelMechanism("intel")))
+	require.NoError(t, err)
+	require.Equal(t, "0000:01:02.0", kernel.ToMechanism(conn3.GetMechanism()).GetPCIAddress())
+
+	for _, conn := range []*networkservice.Connection{conn2, conn3} {
+		_, err = s.Close(ctx, conn)
+		require.NoError(t, err)
+	}
+}
+
+func TestResourcePool_VFIO(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := newServer(ctx, t)
+
+	m := vfio.New("/dev/cgroup")
+	vfio.ToMechanism(m).SetDeviceTokenID("mellanox")
+
+	conn := requestEventually(ctx, t, s, newRequest("conn-1", m))
+	mechanism := vfio.ToMechanism(conn.GetMechanism())
+	require.Equal(t, "0000:02:02.0", mechanism.GetPCIAddress())
+	require.NotZero(t, mechanism.GetIommuGroup())
+
+	_, err := s.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+func TestResourcePool_NoToken(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := newServer(ctx, t)
+
+	conn, err := s.Request(ctx, newRequest("conn-1", kernel.New("")))
+	require.NoError(t, err)
+	require.Empty(t, kernel.ToMechanism(conn.GetMechanism()).GetPCIAddress())
+}
+
+func TestResourcePool_ConfigFile(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	configPath := filepath.Join(t.TempDir(), "resourcepool.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configYAML), os.ModePerm))
+
+	s := next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		resourcepool.NewServer(ctx,
+			resourcepool.WithSysfsPath(newSysfs(t)),
+			resourcepool.WithConfigPath(configPath),
+		),
+	)
+
+	conn := requestEventually(ctx, t, s, newRequest("conn-1", kernelMechanism("intel")))
+	require.Equal(t, "0000:01:02.0", kernel.ToMechanism(conn.GetMechanism()).GetPCIAddress())
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+physicalFunctions:
+  0000:02:00.0:
+    capabilities: [fast]
+`), os.ModePerm))
+
+	conn2 := requestEventually(ctx, t, s, newRequest("conn-2", kernelMechanism("fast")))
+	require.Equal(t, "0000:02:02.0", kernel.ToMechanism(conn2.GetMechanism()).GetPCIAddress())
+
+	for _, c := range []*networkservice.Connection{conn, conn2} {
+		_, err := s.Close(ctx, c)
+		require.NoError(t, err)
+	}
+}
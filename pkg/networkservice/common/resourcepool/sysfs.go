@@ -0,0 +1,89 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcepool
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	pciDevicesPath = "bus/pci/devices"
+	virtfnPrefix   = "virtfn"
+	iommuGroupLink = "iommu_group"
+	noIommuGroup   = -1
+	virtfnPattern  = virtfnPrefix + "*"
+)
+
+// device is a virtual function of the pool
+type device struct {
+	pciAddress   string
+	pfPCIAddress string
+	capabilities []string
+	iommuGroup   int
+	owner        string
+}
+
+// discover discovers virtual functions of the configured physical functions in sysfs
+func discover(sysfsPath string, config *Config) (map[string]*device, error) {
+	devices := make(map[string]*device)
+	for pfPCIAddress, pf := range config.PhysicalFunctions {
+		pfPath := filepath.Join(sysfsPath, pciDevicesPath, pfPCIAddress)
+		links, err := filepath.Glob(filepath.Join(pfPath, virtfnPattern))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list virtual functions of %s", pfPCIAddress)
+		}
+		if len(links) == 0 {
+			if _, err := os.Stat(pfPath); err != nil {
+				return nil, errors.Wrapf(err, "physical function %s is not found", pfPCIAddress)
+			}
+		}
+		for _, link := range links {
+			if _, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(link), virtfnPrefix)); err != nil {
+				continue
+			}
+			target, err := os.Readlink(link)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read virtual function link %s", link)
+			}
+			vfPCIAddress := filepath.Base(target)
+			devices[vfPCIAddress] = &device{
+				pciAddress:   vfPCIAddress,
+				pfPCIAddress: pfPCIAddress,
+				capabilities: pf.Capabilities,
+				iommuGroup:   readIommuGroup(filepath.Join(sysfsPath, pciDevicesPath, vfPCIAddress)),
+			}
+		}
+	}
+	return devices, nil
+}
+
+func readIommuGroup(devicePath string) int {
+	target, err := os.Readlink(filepath.Join(devicePath, iommuGroupLink))
+	if err != nil {
+		return noIommuGroup
+	}
+	group, err := strconv.Atoi(filepath.Base(target))
+	if err != nil {
+		return noIommuGroup
+	}
+	return group
+}
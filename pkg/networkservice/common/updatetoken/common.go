@@ -29,7 +29,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/tools/token"
 )
 
-func updateToken(ctx context.Context, conn *networkservice.Connection, tokenGenerator token.GeneratorFunc) error {
+func updateToken(ctx context.Context, conn *networkservice.Connection, tokenGenerator token.ConnectionGeneratorFunc) error {
 	path := conn.GetPath()
 
 	// Make sure index isn't out of bound
@@ -45,7 +45,10 @@ func updateToken(ctx context.Context, conn *networkservice.Connection, tokenGene
 	}
 
 	// Generate the tok
-	tok, expireTime, err := tokenGenerator(authInfo)
+	tok, expireTime, err := tokenGenerator(authInfo, &token.ConnectionInfo{
+		NetworkService: conn.GetNetworkService(),
+		PathIndex:      path.GetIndex(),
+	})
 	if err != nil {
 		return errors.Wrap(err, "failed to generate a token")
 	}
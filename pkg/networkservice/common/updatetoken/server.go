@@ -19,9 +19,11 @@ package updatetoken
 
 import (
 	"context"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
@@ -30,12 +32,20 @@ import (
 )
 
 type updateTokenServer struct {
-	tokenGenerator token.GeneratorFunc
+	tokenGenerator token.ConnectionGeneratorFunc
 }
 
 // NewServer - creates a NetworkServiceServer chain element to update the Connection token information
 //   - name - the name of the NetworkServiceServer of which the chain element is part
 func NewServer(tokenGenerator token.GeneratorFunc) networkservice.NetworkServiceServer {
+	return NewConnectionServer(func(peerAuthInfo credentials.AuthInfo, _ *token.ConnectionInfo) (string, time.Time, error) {
+		return tokenGenerator(peerAuthInfo)
+	})
+}
+
+// NewConnectionServer - same as NewServer, but the token generator also gets the connection the token is generated
+// for
+func NewConnectionServer(tokenGenerator token.ConnectionGeneratorFunc) networkservice.NetworkServiceServer {
 	return &updateTokenServer{
 		tokenGenerator: tokenGenerator,
 	}
@@ -31,10 +31,12 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatetoken"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"go.uber.org/goleak"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
@@ -222,6 +224,36 @@ func (f *updateTokenServerSuite) TestChain() {
 	require.NoError(t, err)
 }
 
+func (f *updateTokenServerSuite) TestNewConnectionServer() {
+	t := f.T()
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	peerAuthInfo := credentials.TLSInfo{}
+	var gotAuthInfo credentials.AuthInfo
+	var gotConnInfo *token.ConnectionInfo
+	server := next.NewNetworkServiceServer(
+		updatepath.NewServer("nsc-1"),
+		updatepath.NewServer("nsmgr-1"),
+		updatetoken.NewConnectionServer(func(authInfo credentials.AuthInfo, connInfo *token.ConnectionInfo) (string, time.Time, error) {
+			gotAuthInfo, gotConnInfo = authInfo, connInfo
+			return TokenGenerator(authInfo)
+		}),
+	)
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             "conn-1",
+			NetworkService: "ns-1",
+		},
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: peerAuthInfo})
+	conn, err := server.Request(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, f.Token, conn.GetPath().GetPathSegments()[1].GetToken())
+	require.Equal(t, peerAuthInfo, gotAuthInfo)
+	require.Equal(t, &token.ConnectionInfo{NetworkService: "ns-1", PathIndex: 1}, gotConnInfo)
+}
+
 // In order for 'go test' to run this suite, we need to create
 // a normal test function and pass our suite to suite.Run
 func TestUpdateTokenServerTestSuite(t *testing.T) {
@@ -0,0 +1,62 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qoscontext
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type qosClient struct {
+	qos *QoS
+}
+
+// NewClient - returns a new qoscontext client chain element. It requests bandwidth, priority class and DSCP set by
+// the options in the connection context.
+func NewClient(opts ...ClientOption) networkservice.NetworkServiceClient {
+	c := new(qosClient)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *qosClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	if c.qos != nil {
+		if request.GetConnection() == nil {
+			request.Connection = &networkservice.Connection{}
+		}
+		SetRequested(request.GetConnection(), c.qos)
+	}
+	return next.Client(ctx).Request(ctx, request, opts...)
+}
+
+func (c *qosClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
+
+func (c *qosClient) requested() *QoS {
+	if c.qos == nil {
+		c.qos = new(QoS)
+	}
+	return c.qos
+}
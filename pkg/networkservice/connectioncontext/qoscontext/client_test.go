@@ -0,0 +1,74 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qoscontext_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/connectioncontext/qoscontext"
+)
+
+func TestQoSClient_SetsRequested(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	client := qoscontext.NewClient(
+		qoscontext.WithBandwidth(100),
+		qoscontext.WithPriorityClass("gold"),
+		qoscontext.WithDSCP(46),
+	)
+
+	conn, err := client.Request(context.Background(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id"},
+	})
+	require.NoError(t, err)
+
+	requested, err := qoscontext.Requested(conn)
+	require.NoError(t, err)
+	require.Equal(t, &qoscontext.QoS{
+		Bandwidth:     100,
+		PriorityClass: "gold",
+		DSCP:          qoscontext.DSCP(46),
+	}, requested)
+}
+
+func TestQoSClient_NoOptions(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	conn, err := qoscontext.NewClient().Request(context.Background(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "id",
+			Context: &networkservice.ConnectionContext{
+				ExtraContext: map[string]string{qoscontext.RequestedBandwidthKey: "10"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	requested, err := qoscontext.Requested(conn)
+	require.NoError(t, err)
+	require.Equal(t, &qoscontext.QoS{Bandwidth: 10}, requested)
+}
+
+func TestQoSClient_InvalidDSCP(t *testing.T) {
+	require.Panics(t, func() { qoscontext.WithDSCP(qoscontext.MaxDSCP + 1) })
+	require.NotPanics(t, func() { qoscontext.WithDSCP(qoscontext.MaxDSCP) })
+}
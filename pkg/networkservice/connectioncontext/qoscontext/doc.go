@@ -0,0 +1,23 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package qoscontext provides networkservice chain elements negotiating bandwidth and QoS hints through the
+// connection context.
+//
+// The client side stores the requested bandwidth, priority class and DSCP in Connection.Context.ExtraContext. The
+// NSE side runs an admission policy over the request, caps the bandwidth against a per-NSE capacity budget and
+// stores the granted values back to ExtraContext so that forwarders can program shaping.
+package qoscontext
@@ -0,0 +1,50 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qoscontext
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type bandwidthKey struct{}
+
+// storeBandwidth sets the bandwidth granted to the connection and held in the capacity budget
+func storeBandwidth(ctx context.Context, isClient bool, bandwidth uint64) {
+	metadata.Map(ctx, isClient).Store(bandwidthKey{}, bandwidth)
+}
+
+// loadBandwidth returns the bandwidth granted to the connection
+func loadBandwidth(ctx context.Context, isClient bool) (value uint64, ok bool) {
+	rawValue, ok := metadata.Map(ctx, isClient).Load(bandwidthKey{})
+	if !ok {
+		return 0, false
+	}
+	value, ok = rawValue.(uint64)
+	return value, ok
+}
+
+// loadAndDeleteBandwidth deletes the bandwidth granted to the connection and returns it
+func loadAndDeleteBandwidth(ctx context.Context, isClient bool) (value uint64, ok bool) {
+	rawValue, ok := metadata.Map(ctx, isClient).LoadAndDelete(bandwidthKey{})
+	if !ok {
+		return 0, false
+	}
+	value, ok = rawValue.(uint64)
+	return value, ok
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qoscontext
+
+// ClientOption - method for qosClient
+type ClientOption func(client *qosClient)
+
+// WithBandwidth - sets requested bandwidth in bits per second
+func WithBandwidth(bandwidth uint64) ClientOption {
+	return func(c *qosClient) {
+		c.requested().Bandwidth = bandwidth
+	}
+}
+
+// WithPriorityClass - sets requested priority class
+func WithPriorityClass(priorityClass string) ClientOption {
+	return func(c *qosClient) {
+		c.requested().PriorityClass = priorityClass
+	}
+}
+
+// WithDSCP - sets requested DSCP value, must not exceed MaxDSCP
+func WithDSCP(dscp uint8) ClientOption {
+	if dscp > MaxDSCP {
+		panic("dscp cannot exceed MaxDSCP")
+	}
+	return func(c *qosClient) {
+		c.requested().DSCP = DSCP(dscp)
+	}
+}
+
+// ServerOption - method for qosServer
+type ServerOption func(server *qosServer)
+
+// WithCapacity - sets total bandwidth budget of the NSE in bits per second shared by all connections.
+// 0 means unlimited (default).
+func WithCapacity(capacity uint64) ServerOption {
+	return func(s *qosServer) {
+		s.capacity = capacity
+	}
+}
+
+// WithDefaultBandwidth - sets bandwidth granted to connections not requesting any. Without it such connections
+// are not shaped and don't consume the capacity budget.
+func WithDefaultBandwidth(bandwidth uint64) ServerOption {
+	return func(s *qosServer) {
+		s.defaultBandwidth = bandwidth
+	}
+}
+
+// WithAdmissionPolicy - sets admission policy applied before the capacity budget
+func WithAdmissionPolicy(policy AdmissionPolicy) ServerOption {
+	return func(s *qosServer) {
+		s.policy = policy
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qoscontext
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
+)
+
+// AdmissionPolicy - decides what to grant for the requested QoS. requested is nil if the client hasn't requested
+// anything. Returning an error rejects the connection.
+type AdmissionPolicy func(ctx context.Context, conn *networkservice.Connection, requested *QoS) (*QoS, error)
+
+// ClassLimits - limits applied to a priority class
+type ClassLimits struct {
+	// MaxBandwidth caps the bandwidth of every connection in the class, 0 means no cap
+	MaxBandwidth uint64
+	// DSCP overrides the requested DSCP if set
+	DSCP *uint8
+}
+
+// AdmitAll - admission policy granting exactly what is requested
+func AdmitAll(_ context.Context, _ *networkservice.Connection, requested *QoS) (*QoS, error) {
+	return requested, nil
+}
+
+// ClassPolicy - admission policy allowing only the listed priority classes. Connections without a priority class
+// are looked up by the empty class name, so add "" to classes to admit them.
+func ClassPolicy(classes map[string]ClassLimits) AdmissionPolicy {
+	return func(_ context.Context, _ *networkservice.Connection, requested *QoS) (*QoS, error) {
+		granted := requested.clone()
+		if granted == nil {
+			granted = new(QoS)
+		}
+
+		limits, ok := classes[granted.PriorityClass]
+		if !ok {
+			return nil, errors.Errorf("priority class is not allowed: %q", granted.PriorityClass)
+		}
+		if limits.MaxBandwidth > 0 && (granted.Bandwidth == 0 || granted.Bandwidth > limits.MaxBandwidth) {
+			granted.Bandwidth = limits.MaxBandwidth
+		}
+		if limits.DSCP != nil {
+			granted.DSCP = DSCP(*limits.DSCP)
+		}
+		return granted, nil
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qoscontext
+
+import (
+	"strconv"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
+)
+
+const (
+	// RequestedBandwidthKey - ExtraContext key for the requested bandwidth in bits per second
+	RequestedBandwidthKey = "qos-requested-bandwidth"
+	// RequestedPriorityClassKey - ExtraContext key for the requested priority class
+	RequestedPriorityClassKey = "qos-requested-priority-class"
+	// RequestedDSCPKey - ExtraContext key for the requested DSCP value
+	RequestedDSCPKey = "qos-requested-dscp"
+
+	// GrantedBandwidthKey - ExtraContext key for the granted bandwidth in bits per second
+	GrantedBandwidthKey = "qos-granted-bandwidth"
+	// GrantedPriorityClassKey - ExtraContext key for the granted priority class
+	GrantedPriorityClassKey = "qos-granted-priority-class"
+	// GrantedDSCPKey - ExtraContext key for the granted DSCP value
+	GrantedDSCPKey = "qos-granted-dscp"
+
+	// MaxDSCP - maximum DSCP value (6 bits)
+	MaxDSCP = 63
+)
+
+// QoS - bandwidth and QoS hints of the connection
+type QoS struct {
+	// Bandwidth in bits per second, 0 means unspecified
+	Bandwidth uint64
+	// PriorityClass is an opaque traffic class name, empty means unspecified
+	PriorityClass string
+	// DSCP value to mark the traffic with, nil means unspecified
+	DSCP *uint8
+}
+
+type keys struct {
+	bandwidth, priorityClass, dscp string
+}
+
+var (
+	requestedKeys = keys{bandwidth: RequestedBandwidthKey, priorityClass: RequestedPriorityClassKey, dscp: RequestedDSCPKey}
+	grantedKeys   = keys{bandwidth: GrantedBandwidthKey, priorityClass: GrantedPriorityClassKey, dscp: GrantedDSCPKey}
+)
+
+// Requested - returns QoS requested for the conn, nil if nothing is requested
+func Requested(conn *networkservice.Connection) (*QoS, error) {
+	return load(conn, requestedKeys)
+}
+
+// Granted - returns QoS granted for the conn, nil if nothing is granted
+func Granted(conn *networkservice.Connection) (*QoS, error) {
+	return load(conn, grantedKeys)
+}
+
+// SetRequested - stores requested qos to the conn. Nil qos clears the requested values.
+func SetRequested(conn *networkservice.Connection, qos *QoS) {
+	store(conn, requestedKeys, qos)
+}
+
+// SetGranted - stores granted qos to the conn. Nil qos clears the granted values.
+func SetGranted(conn *networkservice.Connection, qos *QoS) {
+	store(conn, grantedKeys, qos)
+}
+
+func load(conn *networkservice.Connection, k keys) (*QoS, error) {
+	extraContext := conn.GetContext().GetExtraContext()
+	if extraContext == nil {
+		return nil, nil
+	}
+
+	var qos QoS
+	var found bool
+	if value, ok := extraContext[k.bandwidth]; ok {
+		bandwidth, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s: %s", k.bandwidth, value)
+		}
+		qos.Bandwidth, found = bandwidth, true
+	}
+	if value, ok := extraContext[k.priorityClass]; ok {
+		qos.PriorityClass, found = value, true
+	}
+	if value, ok := extraContext[k.dscp]; ok {
+		dscp, err := strconv.ParseUint(value, 10, 8)
+		if err != nil || dscp > MaxDSCP {
+			return nil, errors.Errorf("invalid %s: %s", k.dscp, value)
+		}
+		qos.DSCP, found = DSCP(uint8(dscp)), true
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return &qos, nil
+}
+
+func store(conn *networkservice.Connection, k keys, qos *QoS) {
+	if conn.GetContext() == nil {
+		conn.Context = &networkservice.ConnectionContext{}
+	}
+	if conn.GetContext().GetExtraContext() == nil {
+		conn.GetContext().ExtraContext = make(map[string]string)
+	}
+	extraContext := conn.GetContext().GetExtraContext()
+
+	delete(extraContext, k.bandwidth)
+	delete(extraContext, k.priorityClass)
+	delete(extraContext, k.dscp)
+
+	if qos == nil {
+		return
+	}
+	if qos.Bandwidth > 0 {
+		extraContext[k.bandwidth] = strconv.FormatUint(qos.Bandwidth, 10)
+	}
+	if qos.PriorityClass != "" {
+		extraContext[k.priorityClass] = qos.PriorityClass
+	}
+	if qos.DSCP != nil {
+		extraContext[k.dscp] = strconv.FormatUint(uint64(*qos.DSCP), 10)
+	}
+}
+
+// DSCP - returns a pointer to the dscp value, handy for QoS literals
+func DSCP(dscp uint8) *uint8 {
+	return &dscp
+}
+
+func (q *QoS) clone() *QoS {
+	if q == nil {
+		return nil
+	}
+	c := *q
+	if q.DSCP != nil {
+		c.DSCP = DSCP(*q.DSCP)
+	}
+	return &c
+}
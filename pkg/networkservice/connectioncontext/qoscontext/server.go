@@ -0,0 +1,138 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qoscontext
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type qosServer struct {
+	capacity         uint64
+	defaultBandwidth uint64
+	policy           AdmissionPolicy
+
+	mu   sync.Mutex
+	used uint64
+}
+
+// NewServer - returns a new qoscontext server chain element. It applies the admission policy to the requested QoS,
+// caps the bandwidth against the capacity budget and stores the granted QoS in the connection context. Bandwidth held by
+// the connection is kept in its metadata, so the metadata server should go before it.
+func NewServer(opts ...ServerOption) networkservice.NetworkServiceServer {
+	s := &qosServer{
+		policy: AdmitAll,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *qosServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	conn := request.GetConnection()
+
+	requested, err := Requested(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	granted, err := s.policy(ctx, conn, requested.clone())
+	if err != nil {
+		return nil, errors.Wrap(err, "qos request is not admitted")
+	}
+	granted = granted.clone()
+	if granted == nil {
+		granted = new(QoS)
+	}
+	if granted.Bandwidth == 0 {
+		granted.Bandwidth = s.defaultBandwidth
+	}
+
+	prevBandwidth, refresh, err := s.reserve(ctx, granted)
+	if err != nil {
+		return nil, err
+	}
+	SetGranted(conn, granted)
+
+	log.FromContext(ctx).WithField("qosServer", "Request").
+		Debugf("granted bandwidth %d, priority class %q", granted.Bandwidth, granted.PriorityClass)
+
+	resp, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if refresh {
+			s.reset(ctx, prevBandwidth)
+		} else {
+			s.release(ctx)
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *qosServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.release(ctx)
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+// reserve takes granted bandwidth from the budget for the connection, capping it by the remaining capacity. Bandwidth
+// already held by the connection is counted as available, so refreshes don't lose their grant.
+func (s *qosServer) reserve(ctx context.Context, granted *QoS) (prevBandwidth uint64, refresh bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prevBandwidth, refresh = loadBandwidth(ctx, metadata.IsClient(s))
+	if s.capacity > 0 && granted.Bandwidth > 0 {
+		available := s.capacity - (s.used - prevBandwidth)
+		if available == 0 {
+			return 0, false, errors.Errorf("qos capacity exhausted: %d of %d bps in use", s.used, s.capacity)
+		}
+		if granted.Bandwidth > available {
+			granted.Bandwidth = available
+		}
+	}
+
+	s.used = s.used - prevBandwidth + granted.Bandwidth
+	storeBandwidth(ctx, metadata.IsClient(s), granted.Bandwidth)
+	return prevBandwidth, refresh, nil
+}
+
+func (s *qosServer) reset(ctx context.Context, bandwidth uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, _ := loadBandwidth(ctx, metadata.IsClient(s))
+	s.used = s.used - current + bandwidth
+	storeBandwidth(ctx, metadata.IsClient(s), bandwidth)
+}
+
+func (s *qosServer) release(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if bandwidth, ok := loadAndDeleteBandwidth(ctx, metadata.IsClient(s)); ok {
+		s.used -= bandwidth
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qoscontext_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/connectioncontext/qoscontext"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+func request(id string, qos *qoscontext.QoS) *networkservice.NetworkServiceRequest {
+	conn := &networkservice.Connection{Id: id}
+	qoscontext.SetRequested(conn, qos)
+	return &networkservice.NetworkServiceRequest{Connection: conn}
+}
+
+func granted(t *testing.T, conn *networkservice.Connection) *qoscontext.QoS {
+	qos, err := qoscontext.Granted(conn)
+	require.NoError(t, err)
+	return qos
+}
+
+func TestQoSServer_CapacityBudget(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	ctx := context.Background()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		qoscontext.NewServer(qoscontext.WithCapacity(100)),
+	)
+
+	conn1, err := server.Request(ctx, request("1", &qoscontext.QoS{Bandwidth: 70}))
+	require.NoError(t, err)
+	require.Equal(t, uint64(70), granted(t, conn1).Bandwidth)
+
+	// Capped by the remaining capacity
+	conn2, err := server.Request(ctx, request("2", &qoscontext.QoS{Bandwidth: 70}))
+	require.NoError(t, err)
+	require.Equal(t, uint64(30), granted(t, conn2).Bandwidth)
+
+	// Nothing left
+	_, err = server.Request(ctx, request("3", &qoscontext.QoS{Bandwidth: 10}))
+	require.Error(t, err)
+
+	// Refresh keeps the grant
+	conn1, err = server.Request(ctx, request("1", &qoscontext.QoS{Bandwidth: 70}))
+	require.NoError(t, err)
+	require.Equal(t, uint64(70), granted(t, conn1).Bandwidth)
+
+	// Close releases the grant
+	_, err = server.Close(ctx, conn1)
+	require.NoError(t, err)
+
+	conn3, err := server.Request(ctx, request("3", &qoscontext.QoS{Bandwidth: 10}))
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), granted(t, conn3).Bandwidth)
+}
+
+func TestQoSServer_DefaultBandwidth(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	ctx := context.Background()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		qoscontext.NewServer(
+			qoscontext.WithCapacity(100),
+			qoscontext.WithDefaultBandwidth(60),
+		),
+	)
+
+	conn, err := server.Request(ctx, request("1", nil))
+	require.NoError(t, err)
+	require.Equal(t, &qoscontext.QoS{Bandwidth: 60}, granted(t, conn))
+
+	conn, err = server.Request(ctx, request("2", nil))
+	require.NoError(t, err)
+	require.Equal(t, &qoscontext.QoS{Bandwidth: 40}, granted(t, conn))
+}
+
+func TestQoSServer_ClassPolicy(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	ctx := context.Background()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		qoscontext.NewServer(
+			qoscontext.WithAdmissionPolicy(qoscontext.ClassPolicy(map[string]qoscontext.ClassLimits{
+				"gold":   {MaxBandwidth: 50, DSCP: qoscontext.DSCP(46)},
+				"bronze": {MaxBandwidth: 10},
+			})),
+		),
+	)
+
+	conn, err := server.Request(ctx, request("1", &qoscontext.QoS{Bandwidth: 100, PriorityClass: "gold", DSCP: qoscontext.DSCP(0)}))
+	require.NoError(t, err)
+	require.Equal(t, &qoscontext.QoS{Bandwidth: 50, PriorityClass: "gold", DSCP: qoscontext.DSCP(46)}, granted(t, conn))
+
+	conn, err = server.Request(ctx, request("2", &qoscontext.QoS{PriorityClass: "bronze"}))
+	require.NoError(t, err)
+	require.Equal(t, &qoscontext.QoS{Bandwidth: 10, PriorityClass: "bronze"}, granted(t, conn))
+
+	_, err = server.Request(ctx, request("3", &qoscontext.QoS{Bandwidth: 1, PriorityClass: "platinum"}))
+	require.Error(t, err)
+
+	_, err = server.Request(ctx, request("4", nil))
+	require.Error(t, err)
+}
+
+func TestQoSServer_InvalidRequest(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	req := request("1", nil)
+	req.GetConnection().GetContext().ExtraContext[qoscontext.RequestedDSCPKey] = "64"
+
+	_, err := qoscontext.NewServer().Request(context.Background(), req)
+	require.Error(t, err)
+}
+
+func TestQoSServer_NextFailed(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	ctx := context.Background()
+
+	injectErr := injecterror.NewServer(
+		injecterror.WithRequestErrorTimes(1),
+		injecterror.WithCloseErrorTimes(),
+	)
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		qoscontext.NewServer(qoscontext.WithCapacity(100)),
+		injectErr,
+	)
+
+	conn, err := server.Request(ctx, request("1", &qoscontext.QoS{Bandwidth: 60}))
+	require.NoError(t, err)
+	require.Equal(t, uint64(60), granted(t, conn).Bandwidth)
+
+	// Failed refresh restores the previous grant
+	_, err = server.Request(ctx, request("1", &qoscontext.QoS{Bandwidth: 100}))
+	require.Error(t, err)
+
+	conn, err = server.Request(ctx, request("2", &qoscontext.QoS{Bandwidth: 100}))
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), granted(t, conn).Bandwidth)
+}
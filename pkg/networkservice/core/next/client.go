@@ -25,10 +25,14 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/profile"
 )
 
 type nextClient struct {
-	clients    []networkservice.NetworkServiceClient
+	clients []networkservice.NetworkServiceClient
+	// elements are not wrapped, they only name the elements in the profiles and are skipped by introspect
+	elements   []networkservice.NetworkServiceClient `introspect:"-"`
 	index      int
 	nextParent networkservice.NetworkServiceClient
 }
@@ -44,6 +48,7 @@ func NewWrappedNetworkServiceClient(wrapper ClientWrapper, clients ...networkser
 	rv := &nextClient{clients: make([]networkservice.NetworkServiceClient, 0, len(clients))}
 	for _, c := range clients {
 		rv.clients = append(rv.clients, wrapper(c))
+		rv.elements = append(rv.elements, c)
 	}
 	return rv
 }
@@ -57,11 +62,15 @@ func NewNetworkServiceClient(clients ...networkservice.NetworkServiceClient) net
 
 func (n *nextClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
 	client, ctx := n.getClientAndContext(ctx)
+	ctx, done := n.measure(ctx, "Request")
+	defer done()
 	return client.Request(ctx, request, opts...)
 }
 
 func (n *nextClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
 	client, ctx := n.getClientAndContext(ctx)
+	ctx, done := n.measure(ctx, "Close")
+	defer done()
 	return client.Close(ctx, conn, opts...)
 }
 
@@ -74,7 +83,19 @@ func (n *nextClient) getClientAndContext(ctx context.Context) (networkservice.Ne
 		}
 	}
 	if n.index+1 < len(n.clients) {
-		return n.clients[n.index], withNextClient(ctx, &nextClient{nextParent: nextParent, clients: n.clients, index: n.index + 1})
+		return n.clients[n.index], withNextClient(ctx, &nextClient{nextParent: nextParent, clients: n.clients, elements: n.elements, index: n.index + 1})
 	}
 	return n.clients[n.index], withNextClient(ctx, nextParent)
 }
+
+// measure starts measuring the call of the current element, see profile.Start
+func (n *nextClient) measure(ctx context.Context, method string) (context.Context, func()) {
+	if !profile.IsEnabled() || n.index >= len(n.elements) {
+		return ctx, func() {}
+	}
+	// Nested chains are not elements, their elements are measured
+	if _, ok := n.elements[n.index].(*nextClient); ok {
+		return ctx, func() {}
+	}
+	return profile.Start(ctx, n.elements[n.index], method)
+}
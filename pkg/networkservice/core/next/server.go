@@ -24,12 +24,16 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/profile"
 )
 
 type nextServer struct {
 	nextParent networkservice.NetworkServiceServer
-	servers    []networkservice.NetworkServiceServer
-	index      int
+	// elements are not wrapped, they only name the elements in the profiles and are skipped by introspect
+	elements []networkservice.NetworkServiceServer `introspect:"-"`
+	servers  []networkservice.NetworkServiceServer
+	index    int
 }
 
 // ServerWrapper - A function that wraps a networkservice.NetworkServiceServer
@@ -43,6 +47,7 @@ func NewWrappedNetworkServiceServer(wrapper ServerWrapper, servers ...networkser
 	rv := &nextServer{servers: make([]networkservice.NetworkServiceServer, 0, len(servers))}
 	for _, s := range servers {
 		rv.servers = append(rv.servers, wrapper(s))
+		rv.elements = append(rv.elements, s)
 	}
 	return rv
 }
@@ -57,11 +62,15 @@ func NewNetworkServiceServer(servers ...networkservice.NetworkServiceServer) net
 
 func (n *nextServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
 	server, ctx := n.getServerAndContext(ctx)
+	ctx, done := n.measure(ctx, "Request")
+	defer done()
 	return server.Request(ctx, request)
 }
 
 func (n *nextServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
 	server, ctx := n.getServerAndContext(ctx)
+	ctx, done := n.measure(ctx, "Close")
+	defer done()
 	return server.Close(ctx, conn)
 }
 
@@ -74,7 +83,19 @@ func (n *nextServer) getServerAndContext(ctx context.Context) (networkservice.Ne
 		}
 	}
 	if n.index+1 < len(n.servers) {
-		return n.servers[n.index], withNextServer(ctx, &nextServer{nextParent: nextParent, servers: n.servers, index: n.index + 1})
+		return n.servers[n.index], withNextServer(ctx, &nextServer{nextParent: nextParent, servers: n.servers, elements: n.elements, index: n.index + 1})
 	}
 	return n.servers[n.index], withNextServer(ctx, nextParent)
 }
+
+// measure starts measuring the call of the current element, see profile.Start
+func (n *nextServer) measure(ctx context.Context, method string) (context.Context, func()) {
+	if !profile.IsEnabled() || n.index >= len(n.elements) {
+		return ctx, func() {}
+	}
+	// Nested chains are not elements, their elements are measured
+	if _, ok := n.elements[n.index].(*nextServer); ok {
+		return ctx, func() {}
+	}
+	return profile.Start(ctx, n.elements[n.index], method)
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conformance provides a test suite checking that a Network Service Endpoint meets the Network Service Mesh
+// protocol contracts: idempotent refresh, Close of an unknown ID, path and token handling, stable IPContext across
+// refreshes and the monitor events.
+//
+// The suite talks to the endpoint with gRPC, so it can check any endpoint served on a URL, or a
+// networkservice.NetworkServiceServer served by the suite itself:
+//
+//	suite.Run(t, conformance.NewServerSuite(endpoint.NewServer(ctx, tokenGenerator, ...)))
+//	suite.Run(t, conformance.NewURLSuite(nseURL, conformance.WithDialOptions(dialOptions...)))
+//
+// A forwarder can be checked the same way when it is configured to connect to a test endpoint.
+package conformance
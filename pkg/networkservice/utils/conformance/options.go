@@ -0,0 +1,116 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
+	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+)
+
+type options struct {
+	name           string
+	request        *networkservice.NetworkServiceRequest
+	dialOptions    []grpc.DialOption
+	tokenGenerator token.GeneratorFunc
+	timeout        time.Duration
+}
+
+// Option is an option pattern for NewServerSuite, NewURLSuite
+type Option func(o *options)
+
+// WithName sets the name of the client path segment. Default is "conformance-nsc".
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithRequest sets the template of the requests, the suite sets the connection ID and path. Default is the request of
+// "conformance" network service with the local kernel mechanism preference.
+func WithRequest(request *networkservice.NetworkServiceRequest) Option {
+	return func(o *options) {
+		o.request = request
+	}
+}
+
+// WithDialOptions sets the options for dialing the endpoint. Default is the insecure transport credentials.
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOptions = dialOptions
+	}
+}
+
+// WithTokenGenerator sets the generator of the client path segment tokens. Default generates unsigned JWT tokens
+// expiring in an hour.
+func WithTokenGenerator(tokenGenerator token.GeneratorFunc) Option {
+	if tokenGenerator == nil {
+		panic("tokenGenerator cannot be nil")
+	}
+	return func(o *options) {
+		o.tokenGenerator = tokenGenerator
+	}
+}
+
+// WithTimeout sets the timeout of every check. Default is 10 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		name: "conformance-nsc",
+		request: &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{
+				NetworkService: "conformance",
+			},
+			MechanismPreferences: []*networkservice.Mechanism{
+				{Cls: cls.LOCAL, Type: kernelmech.MECHANISM},
+			},
+		},
+		dialOptions:    []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+		tokenGenerator: generateUnsignedToken,
+		timeout:        10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func generateUnsignedToken(_ credentials.AuthInfo) (string, time.Time, error) {
+	expireTime := time.Now().Add(time.Hour)
+
+	claims := jwt.RegisteredClaims{
+		Subject:   "conformance",
+		ExpiresAt: jwt.NewNumericDate(expireTime),
+	}
+
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	return tok, expireTime, errors.Wrap(err, "failed to create a new token")
+}
@@ -0,0 +1,335 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+)
+
+// Suite - test suite to check that a Network Service Endpoint meets the Network Service Mesh protocol contracts
+type Suite struct {
+	suite.Suite
+
+	server networkservice.NetworkServiceServer
+	u      *url.URL
+	opts   *options
+
+	ctx           context.Context
+	cancel        context.CancelFunc
+	cc            *grpc.ClientConn
+	client        networkservice.NetworkServiceClient
+	monitorClient networkservice.MonitorConnectionClient
+}
+
+// NewServerSuite - returns a Suite checking the server served with gRPC by the suite. If the server has
+// Register(*grpc.Server) method, like endpoint.Endpoint, it is used for the registration, else the server is registered
+// as networkservice.NetworkServiceServer and, if it implements it, as networkservice.MonitorConnectionServer.
+func NewServerSuite(server networkservice.NetworkServiceServer, opts ...Option) *Suite {
+	return &Suite{
+		server: server,
+		opts:   newOptions(opts...),
+	}
+}
+
+// NewURLSuite - returns a Suite checking the endpoint served on u
+func NewURLSuite(u *url.URL, opts ...Option) *Suite {
+	return &Suite{
+		u:    u,
+		opts: newOptions(opts...),
+	}
+}
+
+// SetupSuite - serves the server if needed and dials the endpoint
+func (s *Suite) SetupSuite() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	u := s.u
+	if s.server != nil {
+		u = &url.URL{Scheme: "tcp", Host: "127.0.0.1:0"}
+		s.serve(u)
+	}
+
+	dialCtx, cancel := context.WithTimeout(s.ctx, s.opts.timeout)
+	defer cancel()
+
+	var err error
+	s.cc, err = grpc.DialContext(dialCtx, grpcutils.URLToTarget(u), append(s.opts.dialOptions, grpc.WithBlock())...)
+	s.Require().NoError(err, "failed to dial the endpoint: %s", u)
+
+	s.client = networkservice.NewNetworkServiceClient(s.cc)
+	s.monitorClient = networkservice.NewMonitorConnectionClient(s.cc)
+}
+
+// TearDownSuite - closes the connection to the endpoint and stops the served server
+func (s *Suite) TearDownSuite() {
+	if s.cc != nil {
+		_ = s.cc.Close()
+	}
+	s.cancel()
+}
+
+func (s *Suite) serve(u *url.URL) {
+	server := grpc.NewServer()
+	if r, ok := s.server.(interface{ Register(*grpc.Server) }); ok {
+		r.Register(server)
+	} else {
+		networkservice.RegisterNetworkServiceServer(server, s.server)
+		if monitorServer, ok := s.server.(networkservice.MonitorConnectionServer); ok {
+			networkservice.RegisterMonitorConnectionServer(server, monitorServer)
+		}
+	}
+
+	errCh := grpcutils.ListenAndServe(s.ctx, u, server)
+	select {
+	case err := <-errCh:
+		s.Require().NoError(err, "failed to serve the server")
+	default:
+	}
+}
+
+// TestRequest - tests that the endpoint returns the requested connection with the mechanism selected from the preferences
+func (s *Suite) TestRequest() {
+	ctx, cancel := s.checkContext()
+	defer cancel()
+
+	request := s.newRequest()
+	conn, err := s.client.Request(ctx, request.Clone())
+	s.Require().NoError(err)
+	defer s.close(conn)
+
+	s.Require().Equal(request.GetConnection().GetId(), conn.GetId(), "connection ID is changed")
+	s.Require().Equal(request.GetConnection().GetNetworkService(), conn.GetNetworkService(), "network service is changed")
+	s.Require().NotNil(conn.GetMechanism(), "mechanism is not selected")
+
+	var preferred bool
+	for _, mechanism := range request.GetMechanismPreferences() {
+		preferred = preferred || mechanism.GetType() == conn.GetMechanism().GetType()
+	}
+	s.Require().True(preferred, "mechanism is not selected from the preferences: %s", conn.GetMechanism().GetType())
+}
+
+// TestPath - tests that the endpoint adds its path segment after the client one and keeps the client path segment
+func (s *Suite) TestPath() {
+	ctx, cancel := s.checkContext()
+	defer cancel()
+
+	request := s.newRequest()
+	conn, err := s.client.Request(ctx, request.Clone())
+	s.Require().NoError(err)
+	defer s.close(conn)
+
+	segments := conn.GetPath().GetPathSegments()
+	s.Require().Equal(uint32(0), conn.GetPath().GetIndex(), "path index is not restored")
+	s.Require().GreaterOrEqual(len(segments), 2, "endpoint path segment is not added")
+	s.Require().Equal(s.opts.name, segments[0].GetName(), "client path segment name is changed")
+	s.Require().Equal(request.GetConnection().GetId(), segments[0].GetId(), "client path segment ID is changed")
+	s.Require().NotEmpty(segments[1].GetId(), "endpoint path segment ID is empty")
+	s.Require().NotEqual(segments[0].GetId(), segments[1].GetId(), "endpoint path segment ID is the same as the client one")
+}
+
+// TestTokens - tests that the endpoint sets the tokens with the expiration times in future for the path segments
+func (s *Suite) TestTokens() {
+	ctx, cancel := s.checkContext()
+	defer cancel()
+
+	conn, err := s.client.Request(ctx, s.newRequest())
+	s.Require().NoError(err)
+	defer s.close(conn)
+
+	segments := conn.GetPath().GetPathSegments()
+	s.Require().GreaterOrEqual(len(segments), 2, "endpoint path segment is not added")
+
+	for i, segment := range segments[:2] {
+		s.Require().NotEmpty(segment.GetToken(), "token is not set for the path segment %d", i)
+		s.Require().NotNil(segment.GetExpires(), "expiration time is not set for the path segment %d", i)
+		s.Require().True(segment.GetExpires().AsTime().After(time.Now()), "expiration time is in past for the path segment %d", i)
+	}
+}
+
+// TestRefreshIsIdempotent - tests that the refresh with the returned connection returns the same connection
+func (s *Suite) TestRefreshIsIdempotent() {
+	ctx, cancel := s.checkContext()
+	defer cancel()
+
+	conn, err := s.client.Request(ctx, s.newRequest())
+	s.Require().NoError(err)
+	defer s.close(conn)
+
+	refreshed, err := s.client.Request(ctx, s.refreshRequest(conn))
+	s.Require().NoError(err)
+
+	s.Require().Equal(conn.GetId(), refreshed.GetId(), "connection ID is changed by the refresh")
+	s.Require().Equal(conn.GetNetworkService(), refreshed.GetNetworkService(), "network service is changed by the refresh")
+	s.Require().True(proto.Equal(conn.GetMechanism(), refreshed.GetMechanism()), "mechanism is changed by the refresh")
+	s.Require().True(proto.Equal(conn.GetContext(), refreshed.GetContext()), "connection context is changed by the refresh")
+	s.Require().Equal(segmentIDs(conn), segmentIDs(refreshed), "path segments are changed by the refresh")
+}
+
+// TestRefreshKeepsIPContext - tests that IPContext stays stable across the refreshes
+func (s *Suite) TestRefreshKeepsIPContext() {
+	ctx, cancel := s.checkContext()
+	defer cancel()
+
+	conn, err := s.client.Request(ctx, s.newRequest())
+	s.Require().NoError(err)
+	defer func() { s.close(conn) }()
+
+	ipContext := conn.GetContext().GetIpContext()
+	for i := 0; i < 3; i++ {
+		conn, err = s.client.Request(ctx, s.refreshRequest(conn))
+		s.Require().NoError(err)
+		s.Require().True(proto.Equal(ipContext, conn.GetContext().GetIpContext()),
+			"IPContext is changed by the refresh %d: %s -> %s", i, ipContext, conn.GetContext().GetIpContext())
+	}
+}
+
+// TestCloseUnknownID - tests that Close of the connection unknown for the endpoint succeeds
+func (s *Suite) TestCloseUnknownID() {
+	ctx, cancel := s.checkContext()
+	defer cancel()
+
+	_, err := s.client.Close(ctx, s.newRequest().GetConnection())
+	s.Require().NoError(err)
+}
+
+// TestMonitorEvents - tests that the endpoint sends the initial state transfer, update and delete events for the
+// connection. The test is skipped if the endpoint doesn't implement networkservice.MonitorConnectionServer.
+func (s *Suite) TestMonitorEvents() {
+	ctx, cancel := s.checkContext()
+	defer cancel()
+
+	conn, err := s.client.Request(ctx, s.newRequest())
+	s.Require().NoError(err)
+
+	var closed bool
+	defer func() {
+		if !closed {
+			s.close(conn)
+		}
+	}()
+
+	stream, err := s.monitorClient.MonitorConnections(ctx, &networkservice.MonitorScopeSelector{
+		PathSegments: []*networkservice.PathSegment{{Id: conn.GetId()}},
+	})
+	s.Require().NoError(err)
+
+	event, err := stream.Recv()
+	if status.Code(err) == codes.Unimplemented {
+		s.T().Skip("monitor is not implemented")
+	}
+	s.Require().NoError(err)
+	s.Require().Equal(networkservice.ConnectionEventType_INITIAL_STATE_TRANSFER, event.GetType())
+	s.requireEventConnection(event, conn.GetId())
+
+	conn, err = s.client.Request(ctx, s.refreshRequest(conn))
+	s.Require().NoError(err)
+
+	event, err = stream.Recv()
+	s.Require().NoError(err)
+	s.Require().Equal(networkservice.ConnectionEventType_UPDATE, event.GetType())
+	s.requireEventConnection(event, conn.GetId())
+
+	_, err = s.client.Close(ctx, conn)
+	closed = true
+	s.Require().NoError(err)
+
+	event, err = stream.Recv()
+	s.Require().NoError(err)
+	s.Require().Equal(networkservice.ConnectionEventType_DELETE, event.GetType())
+	s.requireEventConnection(event, conn.GetId())
+}
+
+func (s *Suite) requireEventConnection(event *networkservice.ConnectionEvent, id string) {
+	for _, conn := range event.GetConnections() {
+		for _, segment := range conn.GetPath().GetPathSegments() {
+			if segment.GetId() == id {
+				return
+			}
+		}
+	}
+	s.FailNow("no connection in the event", "connection ID: %s, event: %s", id, event)
+}
+
+func (s *Suite) checkContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(s.ctx, s.opts.timeout)
+}
+
+// newRequest returns a new request with the client path segment
+func (s *Suite) newRequest() *networkservice.NetworkServiceRequest {
+	request := s.opts.request.Clone()
+	if request.Connection == nil {
+		request.Connection = &networkservice.Connection{}
+	}
+	request.Connection.Id = uuid.New().String()
+	request.Connection.Path = &networkservice.Path{
+		PathSegments: []*networkservice.PathSegment{
+			{
+				Name: s.opts.name,
+				Id:   request.GetConnection().GetId(),
+			},
+		},
+	}
+	s.updateToken(request.GetConnection())
+	return request
+}
+
+// refreshRequest returns a request refreshing the connection
+func (s *Suite) refreshRequest(conn *networkservice.Connection) *networkservice.NetworkServiceRequest {
+	request := s.opts.request.Clone()
+	request.Connection = conn.Clone()
+	s.updateToken(request.GetConnection())
+	return request
+}
+
+// updateToken sets a new token for the client path segment
+func (s *Suite) updateToken(conn *networkservice.Connection) {
+	tok, expireTime, err := s.opts.tokenGenerator(nil)
+	s.Require().NoError(err, "failed to generate the client token")
+
+	segment := conn.GetPath().GetPathSegments()[0]
+	segment.Token = tok
+	segment.Expires = timestamppb.New(expireTime)
+}
+
+func (s *Suite) close(conn *networkservice.Connection) {
+	ctx, cancel := s.checkContext()
+	defer cancel()
+
+	_, err := s.client.Close(ctx, conn)
+	s.Require().NoError(err)
+}
+
+func segmentIDs(conn *networkservice.Connection) []string {
+	var ids []string
+	for _, segment := range conn.GetPath().GetPathSegments() {
+		ids = append(ids, segment.GetId())
+	}
+	return ids
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/client"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/endpoint"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/clienturl"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/connect"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanismtranslation"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/null"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/point2pointipam"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/conformance"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+func newEndpoint(ctx context.Context) endpoint.Endpoint {
+	_, ipNet, _ := net.ParseCIDR("172.16.0.0/24")
+
+	return endpoint.NewServer(ctx, sandbox.GenerateTestToken,
+		endpoint.WithName("nse"),
+		endpoint.WithAdditionalFunctionality(
+			point2pointipam.NewServer(ipNet),
+			mechanisms.NewServer(map[string]networkservice.NetworkServiceServer{
+				kernelmech.MECHANISM: null.NewServer(),
+			}),
+		),
+	)
+}
+
+func newForwarder(ctx context.Context, nseURL *url.URL) endpoint.Endpoint {
+	return endpoint.NewServer(ctx, sandbox.GenerateTestToken,
+		endpoint.WithName("forwarder"),
+		endpoint.WithAdditionalFunctionality(
+			clienturl.NewServer(nseURL),
+			mechanisms.NewServer(map[string]networkservice.NetworkServiceServer{
+				kernelmech.MECHANISM: null.NewServer(),
+			}),
+			connect.NewServer(
+				client.NewClient(ctx,
+					client.WithName("forwarder"),
+					client.WithAdditionalFunctionality(
+						mechanismtranslation.NewClient(),
+						kernel.NewClient(),
+					),
+					client.WithDialOptions(sandbox.DialOptions()...),
+					client.WithoutRefresh(),
+				),
+			),
+		),
+	)
+}
+
+func serve(ctx context.Context, t *testing.T, e endpoint.Endpoint) *url.URL {
+	server := grpc.NewServer()
+	e.Register(server)
+
+	u := &url.URL{Scheme: "tcp", Host: "127.0.0.1:0"}
+	require.Len(t, grpcutils.ListenAndServe(ctx, u, server), 0)
+
+	return u
+}
+
+func TestServerSuite(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	suite.Run(t, conformance.NewServerSuite(newEndpoint(ctx)))
+}
+
+func TestURLSuite(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	suite.Run(t, conformance.NewURLSuite(serve(ctx, t, newEndpoint(ctx)), conformance.WithName("nsc")))
+}
+
+func TestForwarderSuite(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	suite.Run(t, conformance.NewServerSuite(newForwarder(ctx, serve(ctx, t, newEndpoint(ctx)))))
+}
+
+// changingIPServer sets a new IP address on every Request
+type changingIPServer struct {
+	counter atomic.Int32
+}
+
+func (s *changingIPServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if request.GetConnection().GetContext() == nil {
+		request.GetConnection().Context = &networkservice.ConnectionContext{}
+	}
+	request.GetConnection().GetContext().IpContext = &networkservice.IPContext{
+		SrcIpAddrs: []string{fmt.Sprintf("10.0.0.%d/32", s.counter.Add(1))},
+	}
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *changingIPServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func TestServerSuite_BrokenEndpoint(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newBrokenEndpoint := func(server networkservice.NetworkServiceServer) endpoint.Endpoint {
+		return endpoint.NewServer(ctx, sandbox.GenerateTestToken,
+			endpoint.WithName("nse"),
+			endpoint.WithAdditionalFunctionality(
+				server,
+				mechanisms.NewServer(map[string]networkservice.NetworkServiceServer{
+					kernelmech.MECHANISM: null.NewServer(),
+				}),
+			),
+		)
+	}
+
+	for name, check := range map[string]struct {
+		server networkservice.NetworkServiceServer
+		run    func(s *conformance.Suite)
+	}{
+		"IPContextChanged": {
+			server: newBrokenEndpoint(new(changingIPServer)),
+			run:    (*conformance.Suite).TestRefreshKeepsIPContext,
+		},
+		"CloseUnknownIDFailed": {
+			server: injecterror.NewServer(
+				injecterror.WithRequestErrorTimes(),
+				injecterror.WithCloseErrorTimes(0),
+			),
+			run: (*conformance.Suite).TestCloseUnknownID,
+		},
+	} {
+		check := check
+		// The check is run as a separate test, so its failure doesn't fail this test
+		ok := testing.RunTests(regexp.MatchString, []testing.InternalTest{{
+			Name: t.Name() + "/" + name,
+			F: func(t *testing.T) {
+				s := conformance.NewServerSuite(check.server)
+				s.SetT(t)
+				s.SetupSuite()
+				defer s.TearDownSuite()
+
+				check.run(s)
+			},
+		}})
+		require.False(t, ok, "broken endpoint passes the check: %s", name)
+	}
+}
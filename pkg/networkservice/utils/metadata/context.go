@@ -63,6 +63,25 @@ func Map(parent context.Context, isClient bool) *sync.Map {
 	return &m.server
 }
 
+// WithCopy - returns a context with a copy of the per Connection.Id metadata, so the chain elements can close the
+// previous state of the connection while the connection keeps the new one
+func WithCopy(parent context.Context) context.Context {
+	m, ok := parent.Value(metaDataKey{}).(*metaData)
+	if !ok || m == nil {
+		panic("please add metadata chain element to your chain")
+	}
+	c := new(metaData)
+	m.client.Range(func(key, value any) bool {
+		c.client.Store(key, value)
+		return true
+	})
+	m.server.Range(func(key, value any) bool {
+		c.server.Store(key, value)
+		return true
+	})
+	return context.WithValue(parent, metaDataKey{}, c)
+}
+
 // IsClient - returns true if in implements networkservice.NetworkServiceClient
 func IsClient(in interface{}) bool {
 	_, ok := in.(networkservice.NetworkServiceClient)
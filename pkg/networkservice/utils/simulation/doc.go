@@ -0,0 +1,33 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package simulation provides a seeded simulation harness for the chain elements.
+//
+// The harness generates schedules of Request, Close, clock advance and failure operations for several connections,
+// drives a freshly built chain with each schedule on a mock clock and checks the invariants on the calls reaching the
+// end of the chain. A schedule breaking an invariant is shrunk to a minimal one and reported with the seed, so it can be
+// replayed with Replay.
+//
+// The operations are executed one by one and the chain is let settle after each of them: the harness waits until all
+// the goroutines of the chain are blocked, so the background calls made by the chain elements (refreshes, timeouts) are
+// executed in the order defined by the schedule. So the chain elements should use the clock from the context. The
+// goroutines of the chain are told apart by their pprof label only if the labels are printed in the stacks (GODEBUG
+// tracebacklabels=1, the default since Go 1.27), otherwise the harness waits for all the goroutines of the process, so
+// the simulations can't run with t.Parallel.
+//
+// Client chains are simulated with Client. The heal client is simulated only on the Request and Close path: it reacts
+// to the monitor events of the server, and the simulated chain has no server sending them.
+package simulation
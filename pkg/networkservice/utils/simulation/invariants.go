@@ -0,0 +1,268 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+// Invariant checks the calls reaching the end of the chain. The calls are never made concurrently.
+type Invariant interface {
+	// Request checks the Request, failed is true if the Request is failed with the Fail operation
+	Request(ctx context.Context, conn *networkservice.Connection, failed bool) error
+	// Close checks the Close
+	Close(ctx context.Context, conn *networkservice.Connection) error
+	// Done checks the state after all the connections are closed and all the timers are fired
+	Done() error
+}
+
+// Prober is implemented by the invariants driving the chain with their own calls after all the connections are closed
+// and all the timers are fired. The calls reach the invariants as well, Probe is called before Done.
+type Prober interface {
+	Probe(ctx context.Context, server networkservice.NetworkServiceServer) error
+}
+
+type noCloseAfterClose struct {
+	closed map[string]bool
+}
+
+// NoCloseAfterClose checks that the connection is not closed twice without a successful Request between the Closes
+func NoCloseAfterClose() Invariant {
+	return &noCloseAfterClose{
+		closed: make(map[string]bool),
+	}
+}
+
+func (i *noCloseAfterClose) Request(_ context.Context, conn *networkservice.Connection, failed bool) error {
+	if !failed {
+		delete(i.closed, conn.GetId())
+	}
+	return nil
+}
+
+func (i *noCloseAfterClose) Close(_ context.Context, conn *networkservice.Connection) error {
+	if i.closed[conn.GetId()] {
+		return errors.Errorf("close after close: %s", conn.GetId())
+	}
+	i.closed[conn.GetId()] = true
+	return nil
+}
+
+func (i *noCloseAfterClose) Done() error {
+	return nil
+}
+
+type noLeakedConnections struct {
+	open map[string]struct{}
+}
+
+// NoLeakedConnections checks that all the requested connections are closed in the end
+func NoLeakedConnections() Invariant {
+	return &noLeakedConnections{
+		open: make(map[string]struct{}),
+	}
+}
+
+func (i *noLeakedConnections) Request(_ context.Context, conn *networkservice.Connection, failed bool) error {
+	if !failed {
+		i.open[conn.GetId()] = struct{}{}
+	}
+	return nil
+}
+
+func (i *noLeakedConnections) Close(_ context.Context, conn *networkservice.Connection) error {
+	delete(i.open, conn.GetId())
+	return nil
+}
+
+func (i *noLeakedConnections) Done() error {
+	if len(i.open) > 0 {
+		return errors.Errorf("connections are not closed: %s", joinKeys(i.open))
+	}
+	return nil
+}
+
+// probeLimit is the maximum number of the connections requested by BalancedIPAM to probe the pool
+const probeLimit = 1024
+
+type balancedIPAM struct {
+	owners      map[string]string
+	addrs       map[string][]string
+	allocated   map[string]struct{}
+	probing     bool
+	reallocated map[string]struct{}
+}
+
+// BalancedIPAM checks that the IP addresses of the connections are not shared and all of them are returned to the
+// pool in the end, including the addresses allocated for the failed Requests. It probes the pool with the new
+// connections until all the addresses allocated during the schedule are allocated again, the pool is exhausted or
+// probeLimit connections are requested.
+func BalancedIPAM() Invariant {
+	return &balancedIPAM{
+		owners:      make(map[string]string),
+		addrs:       make(map[string][]string),
+		allocated:   make(map[string]struct{}),
+		reallocated: make(map[string]struct{}),
+	}
+}
+
+func (i *balancedIPAM) Request(_ context.Context, conn *networkservice.Connection, failed bool) error {
+	ipContext := conn.GetContext().GetIpContext()
+	addrs := append(append([]string{}, ipContext.GetSrcIpAddrs()...), ipContext.GetDstIpAddrs()...)
+
+	for _, addr := range addrs {
+		if owner, ok := i.owners[addr]; ok && owner != conn.GetId() {
+			return errors.Errorf("address %s is allocated for both %s and %s", addr, owner, conn.GetId())
+		}
+		if i.probing {
+			i.reallocated[addr] = struct{}{}
+		} else {
+			i.allocated[addr] = struct{}{}
+		}
+	}
+	if failed {
+		return nil
+	}
+
+	i.release(conn.GetId())
+	for _, addr := range addrs {
+		i.owners[addr] = conn.GetId()
+		i.addrs[conn.GetId()] = append(i.addrs[conn.GetId()], addr)
+	}
+	return nil
+}
+
+func (i *balancedIPAM) Close(_ context.Context, conn *networkservice.Connection) error {
+	i.release(conn.GetId())
+	return nil
+}
+
+func (i *balancedIPAM) Probe(ctx context.Context, server networkservice.NetworkServiceServer) error {
+	i.probing = true
+	defer func() { i.probing = false }()
+
+	var conns []*networkservice.Connection
+	for n := 0; n < probeLimit && len(i.lost()) > 0; n++ {
+		conn, err := server.Request(ctx, &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{
+				Id:             fmt.Sprintf("probe-%d", n),
+				NetworkService: "ns",
+				Context:        &networkservice.ConnectionContext{},
+			},
+		})
+		if err != nil {
+			break
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		_, _ = server.Close(ctx, conn)
+	}
+
+	if lost := i.lost(); len(lost) > 0 {
+		return errors.Errorf("addresses are not returned to the pool: %s", joinKeys(lost))
+	}
+	return nil
+}
+
+// lost returns the addresses allocated during the schedule and not allocated again by Probe
+func (i *balancedIPAM) lost() map[string]struct{} {
+	lost := make(map[string]struct{})
+	for addr := range i.allocated {
+		if _, ok := i.reallocated[addr]; !ok {
+			lost[addr] = struct{}{}
+		}
+	}
+	return lost
+}
+
+func (i *balancedIPAM) Done() error {
+	if len(i.owners) > 0 {
+		return errors.Errorf("addresses are not released: %s", joinKeys(i.owners))
+	}
+	return nil
+}
+
+func (i *balancedIPAM) release(id string) {
+	for _, addr := range i.addrs[id] {
+		delete(i.owners, addr)
+	}
+	delete(i.addrs, id)
+}
+
+type metadataKey struct{}
+
+type metadataSentinel struct {
+	id string
+}
+
+type metadataIsolation struct {
+	open map[string]*metadataSentinel
+}
+
+// MetadataIsolation checks that the server metadata is kept across the refreshes and doesn't leak from the closed
+// connection into the new one with the same ID. It requires metadata.NewServer or metadata.NewClient in the chain.
+func MetadataIsolation() Invariant {
+	return &metadataIsolation{
+		open: make(map[string]*metadataSentinel),
+	}
+}
+
+func (i *metadataIsolation) Request(ctx context.Context, conn *networkservice.Connection, failed bool) error {
+	sentinel, open := i.open[conn.GetId()]
+
+	value, ok := metadata.Map(ctx, false).Load(metadataKey{})
+	switch {
+	case open && (!ok || value != sentinel):
+		return errors.Errorf("metadata is lost: %s", conn.GetId())
+	case !open && ok:
+		return errors.Errorf("metadata is leaked from the closed connection: %s", conn.GetId())
+	}
+
+	if !failed && !open {
+		sentinel = &metadataSentinel{id: conn.GetId()}
+		metadata.Map(ctx, false).Store(metadataKey{}, sentinel)
+		i.open[conn.GetId()] = sentinel
+	}
+	return nil
+}
+
+func (i *metadataIsolation) Close(_ context.Context, conn *networkservice.Connection) error {
+	delete(i.open, conn.GetId())
+	return nil
+}
+
+func (i *metadataIsolation) Done() error {
+	return nil
+}
+
+func joinKeys[V any](m map[string]V) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulation
+
+import (
+	"time"
+)
+
+type options struct {
+	seed        int64
+	iterations  int
+	connections int
+	steps       int
+	maxAdvance  time.Duration
+	invariants  []func() Invariant
+}
+
+// Option is an option pattern for Run, Check, Replay and Generate
+type Option func(o *options)
+
+// WithSeed sets the seed of the first generated schedule, the next schedules use the next seeds. Default is the
+// current time, Run logs it.
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.seed = seed
+	}
+}
+
+// WithIterations sets the number of the schedules generated by Run and Check
+func WithIterations(iterations int) Option {
+	if iterations < 0 {
+		panic("iterations cannot be negative")
+	}
+	return func(o *options) {
+		o.iterations = iterations
+	}
+}
+
+// WithConnections sets the number of the connections in the generated schedules
+func WithConnections(connections int) Option {
+	if connections <= 0 {
+		panic("connections must be positive")
+	}
+	return func(o *options) {
+		o.connections = connections
+	}
+}
+
+// WithSteps sets the number of the operations in the generated schedules
+func WithSteps(steps int) Option {
+	if steps < 0 {
+		panic("steps cannot be negative")
+	}
+	return func(o *options) {
+		o.steps = steps
+	}
+}
+
+// WithMaxAdvance sets the maximum duration of the Advance operations in the generated schedules
+func WithMaxAdvance(maxAdvance time.Duration) Option {
+	if maxAdvance <= 0 {
+		panic("maxAdvance must be positive")
+	}
+	return func(o *options) {
+		o.maxAdvance = maxAdvance
+	}
+}
+
+// WithInvariants sets the invariants checked on the calls reaching the end of the chain. Default are
+// NoCloseAfterClose, NoLeakedConnections and BalancedIPAM.
+func WithInvariants(invariants ...func() Invariant) Option {
+	return func(o *options) {
+		o.invariants = invariants
+	}
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		seed:        time.Now().UnixNano(),
+		iterations:  10,
+		connections: 3,
+		steps:       50,
+		maxAdvance:  time.Minute,
+		invariants:  []func() Invariant{NoCloseAfterClose, NoLeakedConnections, BalancedIPAM},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Kind is a kind of the Op
+type Kind int
+
+const (
+	// Request requests the connection, or refreshes it if it is already established
+	Request Kind = iota
+	// Close closes the established connection
+	Close
+	// Advance moves the mock clock forward by the Op duration
+	Advance
+	// Fail makes the next Request of the connection reaching the end of the chain fail
+	Fail
+)
+
+var kindNames = map[Kind]string{
+	Request: "Request",
+	Close:   "Close",
+	Advance: "Advance",
+	Fail:    "Fail",
+}
+
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Op is an operation of the Schedule
+type Op struct {
+	Kind Kind
+	// Conn is the index of the connection for the Request, Close and Fail operations
+	Conn int
+	// Duration is the duration for the Advance operation
+	Duration time.Duration
+}
+
+// String returns Go syntax of the Op
+func (o Op) String() string {
+	if o.Kind == Advance {
+		return fmt.Sprintf("{Kind: simulation.%s, Duration: %d}", o.Kind, o.Duration)
+	}
+	return fmt.Sprintf("{Kind: simulation.%s, Conn: %d}", o.Kind, o.Conn)
+}
+
+// Schedule is a sequence of the operations driving the chain
+type Schedule []Op
+
+// String returns Go syntax of the Schedule, so it can be pasted into the Replay call
+func (s Schedule) String() string {
+	var sb strings.Builder
+	sb.WriteString("simulation.Schedule{\n")
+	for _, op := range s {
+		_, _ = fmt.Fprintf(&sb, "\t%s,\n", op)
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// Generate returns a schedule generated from the seed
+func Generate(seed int64, opts ...Option) Schedule {
+	o := newOptions(opts...)
+	return generate(rand.New(rand.NewSource(seed)), o) // #nosec
+}
+
+func generate(r *rand.Rand, o *options) Schedule {
+	schedule := make(Schedule, 0, o.steps)
+	for i := 0; i < o.steps; i++ {
+		op := Op{
+			Conn: r.Intn(o.connections),
+		}
+		switch n := r.Intn(10); {
+		case n < 4:
+			op.Kind = Request
+		case n < 6:
+			op.Kind = Close
+		case n < 9:
+			op.Kind = Advance
+			op.Duration = time.Duration(r.Int63n(int64(o.maxAdvance))) + 1
+			op.Conn = 0
+		default:
+			op.Kind = Fail
+		}
+		schedule = append(schedule, op)
+	}
+	return schedule
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulation
+
+import (
+	"context"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+// settleTimeout only guards against the chains never settling, it doesn't affect the schedule execution
+const settleTimeout = 10 * time.Second
+
+// simulationLabel is the pprof label of the goroutines of the simulated chain as it is printed in the stacks
+const simulationLabel = "simulation: chain"
+
+var goroutineHeader = regexp.MustCompile(`^goroutine \d+ \[([^\]]+)\]`)
+
+// endServer is the last element of the simulated chain, it checks the invariants and injects the failures
+type endServer struct {
+	mu         sync.Mutex
+	invariants []Invariant
+	fail       map[string]bool
+	violation  error
+}
+
+func newEndServer(invariants []func() Invariant) *endServer {
+	s := &endServer{
+		fail: make(map[string]bool),
+	}
+	for _, invariant := range invariants {
+		s.invariants = append(s.invariants, invariant())
+	}
+	return s
+}
+
+func (s *endServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.mu.Lock()
+	id := request.GetConnection().GetId()
+	failed := s.fail[id]
+	delete(s.fail, id)
+	for _, invariant := range s.invariants {
+		s.check(invariant.Request(ctx, request.GetConnection(), failed))
+	}
+	s.mu.Unlock()
+
+	if failed {
+		return nil, errors.Errorf("simulated failure: %s", id)
+	}
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *endServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.mu.Lock()
+	for _, invariant := range s.invariants {
+		s.check(invariant.Close(ctx, conn))
+	}
+	s.mu.Unlock()
+
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+// failNext makes the next Request of the connection fail
+func (s *endServer) failNext(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fail[id] = true
+}
+
+// check keeps the first violation of the invariants
+func (s *endServer) check(err error) {
+	if err != nil && s.violation == nil {
+		s.violation = err
+	}
+}
+
+// err returns the first violation of the invariants
+func (s *endServer) err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.violation
+}
+
+// done checks the invariants in the end of the schedule
+func (s *endServer) done() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, invariant := range s.invariants {
+		s.check(invariant.Done())
+	}
+	return s.violation
+}
+
+// probe runs the invariants implementing Prober on the server
+func (s *endServer) probe(ctx context.Context, server networkservice.NetworkServiceServer) {
+	for _, invariant := range s.invariants {
+		if prober, ok := invariant.(Prober); ok {
+			err := prober.Probe(ctx, server)
+
+			s.mu.Lock()
+			s.check(err)
+			s.mu.Unlock()
+		}
+	}
+}
+
+// settle waits until all the goroutines of the chain are blocked. The background calls of the chain are started only
+// by the operations and the mock clock timers, so nothing happens in the chain until the next operation once all its
+// goroutines are blocked.
+func settle() error {
+	deadline := time.Now().Add(settleTimeout)
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n == len(buf) {
+			buf = make([]byte, 2*len(buf))
+			continue
+		}
+		if blocked(buf[:n]) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("chain doesn't settle in %s", settleTimeout)
+		}
+		runtime.Gosched()
+	}
+}
+
+// blocked returns true if all the goroutines of the chain in the stacks except the first one, which is the current
+// goroutine, are waiting. Goroutines in syscalls are not considered waiting, they may be writing logs.
+//
+// The goroutines of the chain inherit the simulation label from the goroutine executing the schedule. If the labels
+// are not printed in the stacks (GODEBUG tracebacklabels=0, the default before Go 1.27), all the goroutines are
+// checked except the signal receiver and the runtime ones, which never settle.
+func blocked(stacks []byte) bool {
+	goroutines := strings.Split(string(stacks), "\n\n")
+	labeled := strings.Contains(header(goroutines[0]), simulationLabel)
+	for _, goroutine := range goroutines[1:] {
+		if labeled && !strings.Contains(header(goroutine), simulationLabel) {
+			continue
+		}
+		if !labeled && ignored(goroutine) {
+			continue
+		}
+		m := goroutineHeader.FindStringSubmatch(header(goroutine))
+		if m == nil {
+			continue
+		}
+		for _, active := range []string{"running", "runnable", "syscall", "preempted", "copystack"} {
+			if strings.HasPrefix(m[1], active) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func header(goroutine string) string {
+	if i := strings.IndexByte(goroutine, '\n'); i >= 0 {
+		return goroutine[:i]
+	}
+	return goroutine
+}
+
+// ignored returns true for the goroutine receiving the signals and for the goroutines running only the runtime code,
+// the latter are printed with GOTRACEBACK=system
+func ignored(goroutine string) bool {
+	for _, line := range strings.Split(goroutine, "\n")[1:] {
+		if strings.HasPrefix(line, "\t") || line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "os/signal.signal_recv(") {
+			return true
+		}
+		if !strings.HasPrefix(line, "runtime.") && !strings.HasPrefix(line, "created by runtime.") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"runtime/pprof"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+)
+
+// teardownAdvance is the duration the clock is moved forward by after all the connections are closed, so all the
+// timers of the chain fire
+const teardownAdvance = 24 * time.Hour
+
+// ChainFunc returns the chain driven by the simulation. ctx has the mock clock and is canceled when the schedule is
+// over, so the chain should be created with it.
+type ChainFunc func(ctx context.Context) networkservice.NetworkServiceServer
+
+// ClientChainFunc returns the client chain driven by the simulation, see ChainFunc
+type ClientChainFunc func(ctx context.Context) networkservice.NetworkServiceClient
+
+// Client returns ChainFunc for the client chain returned by clientChainFunc, so the client chain elements like refresh
+// and heal are simulated. The client chain is followed by the end of the simulated chain.
+func Client(clientChainFunc ClientChainFunc) ChainFunc {
+	return func(ctx context.Context) networkservice.NetworkServiceServer {
+		return adapters.NewClientToServer(clientChainFunc(ctx))
+	}
+}
+
+// Run drives the chains returned by chainFunc with the generated schedules and fails t with the seed and the minimal
+// schedule breaking the invariants
+func Run(t *testing.T, chainFunc ChainFunc, opts ...Option) {
+	o := newOptions(opts...)
+	t.Logf("simulation seed: %d", o.seed)
+
+	if seed, schedule, err := check(chainFunc, o); err != nil {
+		t.Fatalf("seed %d: %s\nminimal schedule (%d operations):\n%s", seed, err.Error(), len(schedule), schedule)
+	}
+}
+
+// Check drives the chains returned by chainFunc with the generated schedules and returns the seed and the minimal
+// schedule breaking the invariants with the violation error, or nil error if the invariants hold
+func Check(chainFunc ChainFunc, opts ...Option) (int64, Schedule, error) {
+	return check(chainFunc, newOptions(opts...))
+}
+
+func check(chainFunc ChainFunc, o *options) (int64, Schedule, error) {
+	for i := 0; i < o.iterations; i++ {
+		seed := o.seed + int64(i)
+		schedule := generate(rand.New(rand.NewSource(seed)), o) // #nosec
+		if err := execute(chainFunc, schedule, o); err != nil {
+			schedule, err = shrink(chainFunc, schedule, err, o)
+			return seed, schedule, err
+		}
+	}
+	return 0, nil, nil
+}
+
+// Replay drives the chain returned by chainFunc with the schedule and fails t if it breaks the invariants
+func Replay(t *testing.T, chainFunc ChainFunc, schedule Schedule, opts ...Option) {
+	if err := execute(chainFunc, schedule, newOptions(opts...)); err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+// shrink removes the operations from the failing schedule while it still breaks the invariants
+func shrink(chainFunc ChainFunc, schedule Schedule, err error, o *options) (Schedule, error) {
+	for chunk := len(schedule) / 2; chunk > 0; chunk /= 2 {
+		for i := 0; i+chunk <= len(schedule); {
+			candidate := append(append(Schedule{}, schedule[:i]...), schedule[i+chunk:]...)
+			if candidateErr := execute(chainFunc, candidate, o); candidateErr != nil {
+				schedule, err = candidate, candidateErr
+				continue
+			}
+			i += chunk
+		}
+	}
+	return schedule, err
+}
+
+type execution struct {
+	ctx       context.Context
+	clockMock *clockmock.Mock
+	end       *endServer
+	server    networkservice.NetworkServiceServer
+	conns     map[int]*networkservice.Connection
+}
+
+// execute runs the schedule with the simulation label, so the goroutines started by the chain inherit it, see settle
+func execute(chainFunc ChainFunc, schedule Schedule, o *options) (err error) {
+	pprof.Do(context.Background(), pprof.Labels("simulation", "chain"), func(ctx context.Context) {
+		err = executeLabeled(ctx, chainFunc, schedule, o)
+	})
+	return err
+}
+
+func executeLabeled(ctx context.Context, chainFunc ChainFunc, schedule Schedule, o *options) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	clockMock := clockmock.New(ctx)
+	ctx = clock.WithClock(ctx, clockMock)
+
+	e := &execution{
+		ctx:       ctx,
+		clockMock: clockMock,
+		end:       newEndServer(o.invariants),
+		conns:     make(map[int]*networkservice.Connection),
+	}
+	e.server = next.NewNetworkServiceServer(chainFunc(ctx), e.end)
+
+	for i, op := range schedule {
+		if err := e.do(op); err != nil {
+			return errors.Wrapf(err, "operation %d %s", i, op)
+		}
+	}
+	return errors.Wrap(e.teardown(), "teardown")
+}
+
+// do executes the operation and waits for the chain to settle
+func (e *execution) do(op Op) error {
+	switch op.Kind {
+	case Request:
+		request := &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{
+				Id:             connID(op.Conn),
+				NetworkService: "ns",
+				Context:        &networkservice.ConnectionContext{},
+			},
+		}
+		if conn, ok := e.conns[op.Conn]; ok {
+			request.Connection = conn.Clone()
+		}
+		if conn, err := e.server.Request(e.ctx, request); err == nil {
+			e.conns[op.Conn] = conn
+		}
+	case Close:
+		conn, ok := e.conns[op.Conn]
+		if !ok {
+			conn = &networkservice.Connection{Id: connID(op.Conn)}
+		}
+		_, _ = e.server.Close(e.ctx, conn)
+		delete(e.conns, op.Conn)
+	case Advance:
+		e.clockMock.Add(op.Duration)
+	case Fail:
+		e.end.failNext(connID(op.Conn))
+	default:
+		return errors.Errorf("unknown operation: %s", op)
+	}
+
+	if err := settle(); err != nil {
+		return err
+	}
+	return e.end.err()
+}
+
+// teardown closes all the established connections, fires all the timers, probes the chain and checks the end state
+func (e *execution) teardown() error {
+	indexes := make([]int, 0, len(e.conns))
+	for index := range e.conns {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		_, _ = e.server.Close(e.ctx, e.conns[index])
+	}
+	if err := settle(); err != nil {
+		return err
+	}
+
+	e.clockMock.Add(teardownAdvance)
+	if err := settle(); err != nil {
+		return err
+	}
+
+	e.end.probe(e.ctx, e.server)
+	if err := settle(); err != nil {
+		return err
+	}
+	return e.end.done()
+}
+
+func connID(index int) string {
+	return fmt.Sprintf("conn-%d", index)
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulation_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/begin"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/refresh"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/timeout"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatetoken"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/point2pointipam"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/simulation"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+const tokenTimeout = time.Minute
+
+func testChain(ctx context.Context) networkservice.NetworkServiceClient {
+	_, ipNet, _ := net.ParseCIDR("172.16.0.0/24")
+
+	return next.NewNetworkServiceClient(
+		updatepath.NewClient("nsc"),
+		begin.NewClient(),
+		metadata.NewClient(),
+		refresh.NewClient(ctx),
+		adapters.NewServerToClient(
+			next.NewNetworkServiceServer(
+				// There is no gRPC call between the client and the server, so the token of the client path segment
+				// is set on the server side
+				updatetoken.NewServer(func(_ credentials.AuthInfo) (string, time.Time, error) {
+					return "token", clock.FromContext(ctx).Now().Add(tokenTimeout), nil
+				}),
+				updatepath.NewServer("nse"),
+				begin.NewServer(),
+				metadata.NewServer(),
+				timeout.NewServer(ctx),
+				point2pointipam.NewServer(ipNet),
+			),
+		),
+	)
+}
+
+func TestRun(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	simulation.Run(t, simulation.Client(testChain),
+		simulation.WithSeed(1),
+		simulation.WithIterations(3),
+		simulation.WithSteps(30),
+		simulation.WithMaxAdvance(tokenTimeout),
+		simulation.WithInvariants(
+			simulation.NoCloseAfterClose,
+			simulation.NoLeakedConnections,
+			simulation.BalancedIPAM,
+			simulation.MetadataIsolation,
+		),
+	)
+}
+
+type doubleCloseServer struct{}
+
+func (s *doubleCloseServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *doubleCloseServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, _ = next.Server(ctx).Close(ctx, conn)
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func TestCheck_Shrink(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	chainFunc := func(ctx context.Context) networkservice.NetworkServiceServer {
+		return next.NewNetworkServiceServer(
+			begin.NewServer(),
+			new(doubleCloseServer),
+		)
+	}
+
+	seed, schedule, err := simulation.Check(chainFunc,
+		simulation.WithSeed(1),
+		simulation.WithSteps(20),
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "close after close")
+	require.Equal(t, int64(1), seed)
+
+	// The connection is closed by the teardown, so a single Request is enough
+	require.Len(t, schedule, 1)
+	require.Equal(t, simulation.Request, schedule[0].Kind)
+}
+
+// leakyIPAMServer doesn't return the address to the pool if the Request fails
+type leakyIPAMServer struct {
+	mu    sync.Mutex
+	free  []string
+	owned map[string]string
+}
+
+func newLeakyIPAMServer() *leakyIPAMServer {
+	return &leakyIPAMServer{
+		free:  []string{"10.0.0.1/32", "10.0.0.2/32", "10.0.0.3/32", "10.0.0.4/32"},
+		owned: make(map[string]string),
+	}
+}
+
+func (s *leakyIPAMServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.mu.Lock()
+	id := request.GetConnection().GetId()
+	addr, ok := s.owned[id]
+	if !ok {
+		if len(s.free) == 0 {
+			s.mu.Unlock()
+			return nil, errors.New("pool is exhausted")
+		}
+		addr, s.free = s.free[0], s.free[1:]
+	}
+	s.mu.Unlock()
+
+	request.GetConnection().GetContext().IpContext = &networkservice.IPContext{SrcIpAddrs: []string{addr}}
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.owned[id] = addr
+	s.mu.Unlock()
+
+	return conn, nil
+}
+
+func (s *leakyIPAMServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.mu.Lock()
+	if addr, ok := s.owned[conn.GetId()]; ok {
+		s.free = append(s.free, addr)
+		delete(s.owned, conn.GetId())
+	}
+	s.mu.Unlock()
+
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func TestCheck_BalancedIPAM(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	chainFunc := func(ctx context.Context) networkservice.NetworkServiceServer {
+		return next.NewNetworkServiceServer(
+			begin.NewServer(),
+			newLeakyIPAMServer(),
+		)
+	}
+
+	seed, schedule, err := simulation.Check(chainFunc,
+		simulation.WithSeed(1),
+		simulation.WithSteps(20),
+		simulation.WithInvariants(simulation.BalancedIPAM),
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "addresses are not returned to the pool")
+
+	// The simulation is deterministic, so the same seed gives the same minimal schedule
+	replaySeed, replaySchedule, replayErr := simulation.Check(chainFunc,
+		simulation.WithSeed(1),
+		simulation.WithSteps(20),
+		simulation.WithInvariants(simulation.BalancedIPAM),
+	)
+	require.Equal(t, seed, replaySeed)
+	require.Equal(t, schedule, replaySchedule)
+	require.Equal(t, err.Error(), replayErr.Error())
+
+	require.Len(t, schedule, 2)
+	require.Equal(t, simulation.Fail, schedule[0].Kind)
+	require.Equal(t, simulation.Request, schedule[1].Kind)
+	require.Equal(t, schedule[0].Conn, schedule[1].Conn)
+}
+
+func TestOptions_Invalid(t *testing.T) {
+	require.Panics(t, func() { simulation.WithConnections(0) })
+	require.Panics(t, func() { simulation.WithMaxAdvance(0) })
+	require.Panics(t, func() { simulation.WithSteps(-1) })
+	require.Panics(t, func() { simulation.WithIterations(-1) })
+}
+
+func TestReplay(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	simulation.Replay(t, simulation.Client(testChain), simulation.Schedule{
+		{Kind: simulation.Request, Conn: 0},
+		{Kind: simulation.Fail, Conn: 0},
+		{Kind: simulation.Advance, Duration: tokenTimeout / 2},
+		{Kind: simulation.Request, Conn: 1},
+		{Kind: simulation.Close, Conn: 0},
+		{Kind: simulation.Request, Conn: 0},
+		{Kind: simulation.Advance, Duration: 2 * tokenTimeout},
+	}, simulation.WithInvariants(simulation.MetadataIsolation, simulation.BalancedIPAM))
+}
+
+func TestReplay_SignalReceiver(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	// The goroutine receiving the signals is always in the syscall, it is not a goroutine of the chain
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1)
+	defer signal.Stop(ch)
+
+	simulation.Replay(t, simulation.Client(testChain), simulation.Schedule{
+		{Kind: simulation.Request, Conn: 0},
+		{Kind: simulation.Advance, Duration: tokenTimeout},
+		{Kind: simulation.Close, Conn: 0},
+	}, simulation.WithInvariants(simulation.NoLeakedConnections))
+}
+
+func TestSchedule_String(t *testing.T) {
+	require.Equal(t, simulation.Generate(1), simulation.Generate(1))
+
+	require.Equal(t, `simulation.Schedule{
+	{Kind: simulation.Request, Conn: 1},
+	{Kind: simulation.Advance, Duration: 1000000000},
+}`, simulation.Schedule{
+		{Kind: simulation.Request, Conn: 1},
+		{Kind: simulation.Advance, Duration: time.Second},
+	}.String())
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/common/null"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/refresh"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/retry"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/watchhub"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/chain"
 	"github.com/networkservicemesh/sdk/pkg/registry/utils/metadata"
 )
@@ -57,6 +58,7 @@ func NewNetworkServiceEndpointRegistryClient(ctx context.Context, opts ...Option
 				metadata.NewNetworkServiceEndpointClient(),
 				retry.NewNetworkServiceEndpointRegistryClient(ctx),
 				heal.NewNetworkServiceEndpointRegistryClient(ctx),
+				watchhub.NewNetworkServiceEndpointRegistryClient(ctx),
 				refresh.NewNetworkServiceEndpointRegistryClient(ctx),
 				clientOpts.authorizeNSERegistryClient,
 				clientOpts.nseClientURLResolver,
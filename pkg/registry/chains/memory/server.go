@@ -42,6 +42,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/setpayload"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/setregistrationtime"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/sweep"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/chain"
 	"github.com/networkservicemesh/sdk/pkg/registry/switchcase"
 	"github.com/networkservicemesh/sdk/pkg/registry/utils/metadata"
@@ -57,6 +58,8 @@ type serverOptions struct {
 	defaultExpiration          time.Duration
 	proxyRegistryURL           *url.URL
 	dialOptions                []grpc.DialOption
+	sweep                      bool
+	sweepOptions               []sweep.Option
 }
 
 // Option modifies server option value
@@ -123,6 +126,15 @@ func WithDialOptions(dialOptions ...grpc.DialOption) Option {
 	}
 }
 
+// WithSweep enables probing URLs of the registered endpoints, the endpoints failed the probes are unregistered, see
+// sweep.NewNetworkServiceEndpointRegistryServer
+func WithSweep(sweepOptions ...sweep.Option) Option {
+	return func(o *serverOptions) {
+		o.sweep = true
+		o.sweepOptions = sweepOptions
+	}
+}
+
 // NewServer creates new registry server based on memory storage
 func NewServer(ctx context.Context, tokenGenerator token.GeneratorFunc, options ...Option) registryserver.Registry {
 	opts := &serverOptions{
@@ -137,6 +149,15 @@ func NewServer(ctx context.Context, tokenGenerator token.GeneratorFunc, options
 		opt(opts)
 	}
 
+	localNSEChain := []registry.NetworkServiceEndpointRegistryServer{
+		setregistrationtime.NewNetworkServiceEndpointRegistryServer(),
+		expire.NewNetworkServiceEndpointRegistryServer(ctx, expire.WithDefaultExpiration(opts.defaultExpiration)),
+	}
+	if opts.sweep {
+		localNSEChain = append(localNSEChain, sweep.NewNetworkServiceEndpointRegistryServer(ctx, opts.sweepOptions...))
+	}
+	localNSEChain = append(localNSEChain, memory.NewNetworkServiceEndpointRegistryServer())
+
 	nseChain := chain.NewNetworkServiceEndpointRegistryServer(
 		grpcmetadata.NewNetworkServiceEndpointRegistryServer(),
 		updatepath.NewNetworkServiceEndpointRegistryServer(tokenGenerator),
@@ -173,11 +194,7 @@ func NewServer(ctx context.Context, tokenGenerator token.GeneratorFunc, options
 		},
 			switchcase.NSEServerCase{
 				Condition: func(c context.Context, nse *registry.NetworkServiceEndpoint) bool { return true },
-				Action: chain.NewNetworkServiceEndpointRegistryServer(
-					setregistrationtime.NewNetworkServiceEndpointRegistryServer(),
-					expire.NewNetworkServiceEndpointRegistryServer(ctx, expire.WithDefaultExpiration(opts.defaultExpiration)),
-					memory.NewNetworkServiceEndpointRegistryServer(),
-				),
+				Action:    chain.NewNetworkServiceEndpointRegistryServer(localNSEChain...),
 			},
 		),
 	)
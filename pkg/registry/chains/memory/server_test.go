@@ -21,20 +21,30 @@ package memory_test
 
 import (
 	"context"
+	"io"
+	"net/url"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice/payload"
 	"github.com/networkservicemesh/api/pkg/api/registry"
 
+	registryserver "github.com/networkservicemesh/sdk/pkg/registry"
 	registryclient "github.com/networkservicemesh/sdk/pkg/registry/chains/client"
-
+	"github.com/networkservicemesh/sdk/pkg/registry/chains/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/sweep"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/tombstone"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
 	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
 )
 
 func Test_RegistryMemory_ShouldSetDefaultPayload(t *testing.T) {
@@ -68,3 +78,65 @@ func Test_RegistryMemory_ShouldSetDefaultPayload(t *testing.T) {
 
 	require.NoError(t, ctx.Err())
 }
+
+func Test_RegistryMemory_SweepsUnreachableEndpoints(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	domain := sandbox.NewBuilder(ctx, t).
+		SetNodesCount(0).
+		SetRegistryProxySupplier(nil).
+		SetNSMgrProxySupplier(nil).
+		SetRegistrySupplier(func(ctx context.Context, tokenGenerator token.GeneratorFunc, defaultExpiration time.Duration, proxyRegistryURL *url.URL, options ...grpc.DialOption) registryserver.Registry {
+			return memory.NewServer(ctx, tokenGenerator,
+				memory.WithDefaultExpiration(defaultExpiration),
+				memory.WithProxyRegistryURL(proxyRegistryURL),
+				memory.WithDialOptions(options...),
+				memory.WithSweep(
+					sweep.WithInterval(time.Millisecond*10),
+					sweep.WithFailureThreshold(1),
+					sweep.WithProber(func(context.Context, *url.URL) error { return errors.New("unreachable") }),
+				),
+			)
+		}).
+		Build()
+
+	nserc := registryclient.NewNetworkServiceEndpointRegistryClient(ctx,
+		registryclient.WithDialOptions(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		registryclient.WithClientURL(domain.Registry.URL))
+
+	nse := &registry.NetworkServiceEndpoint{
+		Name:                "nse-1",
+		NetworkServiceNames: []string{"ns-1"},
+	}
+	// The reason is received in the trailer, so the stream is not healed
+	cc, err := grpc.DialContext(ctx, grpcutils.URLToTarget(domain.Registry.URL), grpc.WithBlock(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer func() { _ = cc.Close() }()
+
+	var trailer metadata.MD
+	stream, err := registry.NewNetworkServiceEndpointRegistryClient(cc).Find(
+		findoptions.WithOptions(ctx, &findoptions.Options{DeleteReasons: true}),
+		&registry.NetworkServiceEndpointQuery{NetworkServiceEndpoint: nse, Watch: true},
+		grpc.Trailer(&trailer),
+	)
+	require.NoError(t, err)
+
+	nse.Url = "tcp://127.0.0.1:1"
+	_, err = nserc.Register(ctx, nse.Clone())
+	require.NoError(t, err)
+
+	var deleted bool
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			require.ErrorIs(t, err, io.EOF)
+			break
+		}
+		deleted = resp.GetDeleted()
+	}
+	require.True(t, deleted)
+	require.Equal(t, tombstone.Unreachable, tombstone.TrailerReason(trailer, nse.GetName()))
+}
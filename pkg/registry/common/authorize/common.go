@@ -26,6 +26,7 @@ import (
 
 	"github.com/networkservicemesh/sdk/pkg/registry/common/grpcmetadata"
 	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
 )
 
 // RegistryOpaInput represents input for policies in authorizNSEServer and authorizeNSServer
@@ -119,3 +120,14 @@ func getLeftSideOfPath(path *grpcmetadata.Path) *grpcmetadata.Path {
 		PathSegments: path.PathSegments[:path.Index+1],
 	}
 }
+
+func checkRevoked(list *revocation.List, path *grpcmetadata.Path) error {
+	if list == nil {
+		return nil
+	}
+	var tokens []string
+	for _, segment := range getLeftSideOfPath(path).PathSegments {
+		tokens = append(tokens, segment.Token)
+	}
+	return errors.Wrap(list.CheckTokens(tokens...), "registry: registration credentials are revoked")
+}
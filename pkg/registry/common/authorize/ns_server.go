@@ -27,11 +27,13 @@ import (
 
 	"github.com/networkservicemesh/sdk/pkg/registry/common/grpcmetadata"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
 )
 
 type authorizeNSServer struct {
-	policies     policiesList
-	nsPathIdsMap *genericsync.Map[string, []string]
+	policies       policiesList
+	revocationList *revocation.List
+	nsPathIdsMap   *genericsync.Map[string, []string]
 }
 
 // NewNetworkServiceRegistryServer - returns a new authorization registry.NetworkServiceRegistryServer
@@ -46,12 +48,16 @@ func NewNetworkServiceRegistryServer(opts ...Option) registry.NetworkServiceRegi
 	}
 
 	return &authorizeNSServer{
-		policies:     o.policies,
-		nsPathIdsMap: o.resourcePathIdsMap,
+		policies:       o.policies,
+		revocationList: o.revocationList,
+		nsPathIdsMap:   o.resourcePathIdsMap,
 	}
 }
 
 func (s *authorizeNSServer) Register(ctx context.Context, ns *registry.NetworkService) (*registry.NetworkService, error) {
+	if err := checkRevoked(s.revocationList, grpcmetadata.PathFromContext(ctx)); err != nil {
+		return nil, err
+	}
 	if len(s.policies) == 0 {
 		return next.NetworkServiceRegistryServer(ctx).Register(ctx, ns)
 	}
@@ -27,11 +27,13 @@ import (
 
 	"github.com/networkservicemesh/sdk/pkg/registry/common/grpcmetadata"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
 )
 
 type authorizeNSEServer struct {
-	policies      policiesList
-	nsePathIdsMap *genericsync.Map[string, []string]
+	policies       policiesList
+	revocationList *revocation.List
+	nsePathIdsMap  *genericsync.Map[string, []string]
 }
 
 // NewNetworkServiceEndpointRegistryServer - returns a new authorization registry.NetworkServiceEndpointRegistryServer
@@ -46,12 +48,16 @@ func NewNetworkServiceEndpointRegistryServer(opts ...Option) registry.NetworkSer
 	}
 
 	return &authorizeNSEServer{
-		policies:      o.policies,
-		nsePathIdsMap: o.resourcePathIdsMap,
+		policies:       o.policies,
+		revocationList: o.revocationList,
+		nsePathIdsMap:  o.resourcePathIdsMap,
 	}
 }
 
 func (s *authorizeNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	if err := checkRevoked(s.revocationList, grpcmetadata.PathFromContext(ctx)); err != nil {
+		return nil, err
+	}
 	if len(s.policies) == 0 {
 		return next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
 	}
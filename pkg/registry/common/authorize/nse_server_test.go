@@ -32,6 +32,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/common/grpcmetadata"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
 	"github.com/networkservicemesh/sdk/pkg/tools/nanoid"
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
 
 	"go.uber.org/goleak"
 )
@@ -148,3 +149,23 @@ func TestNetworkServiceEndpointRegistryAuthorize_ResourcePathIdMapHaveNoLeaks(t
 	})
 	require.Equal(t, mapLen, 0)
 }
+
+func TestNetworkServiceEndpointRegistryAuthorize_RevocationList(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	list := revocation.NewList(nil)
+	server := authorize.NewNetworkServiceEndpointRegistryServer(authorize.Any(), authorize.WithRevocationList(list))
+
+	nse := &registry.NetworkServiceEndpoint{Name: "nse"}
+	ctx := grpcmetadata.PathWithContext(context.Background(), getPath(t, spiffeid1))
+
+	_, err := server.Register(ctx, nse)
+	require.NoError(t, err)
+
+	list.Update(&revocation.Entries{SpiffeIDs: []string{spiffeid1}})
+	_, err = server.Register(ctx, nse)
+	require.ErrorIs(t, err, revocation.ErrRevoked)
+
+	_, err = server.Unregister(ctx, nse)
+	require.NoError(t, err)
+}
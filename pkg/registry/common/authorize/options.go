@@ -21,10 +21,12 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/networkservicemesh/sdk/pkg/tools/opa"
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
 )
 
 type options struct {
 	policies           policiesList
+	revocationList     *revocation.List
 	resourcePathIdsMap *genericsync.Map[string, []string]
 }
 
@@ -53,6 +55,15 @@ func WithPolicies(policyPaths ...string) Option {
 	}
 }
 
+// WithRevocationList sets list of revoked tokens and SPIFFE IDs. Registrations with revoked tokens on the left side of
+// the path are rejected even if any call is authorized. Use revoke chain element to unregister the already registered
+// endpoints once their credentials are revoked.
+func WithRevocationList(list *revocation.List) Option {
+	return func(o *options) {
+		o.revocationList = list
+	}
+}
+
 // WithResourcePathIdsMap sets map to keep resourcePathIdsMap to authorize connections with Registry Authorize Chain Element
 func WithResourcePathIdsMap(m *genericsync.Map[string, []string]) Option {
 	return func(o *options) {
@@ -23,6 +23,7 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
 	"github.com/networkservicemesh/sdk/pkg/tools/postpone"
@@ -54,7 +55,9 @@ func (c *connectNSEServer) Register(ctx context.Context, in *registry.NetworkSer
 func (c *connectNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
 	ctx := server.Context()
 
-	clientResp, clientErr := c.client.Find(ctx, query, c.callOptions...)
+	var trailer metadata.MD
+	callOptions := append(append([]grpc.CallOption(nil), c.callOptions...), grpc.Trailer(&trailer))
+	clientResp, clientErr := c.client.Find(ctx, query, callOptions...)
 	if clientErr != nil {
 		return clientErr
 	}
@@ -64,6 +67,8 @@ func (c *connectNSEServer) Find(query *registry.NetworkServiceEndpointQuery, ser
 			return errors.Wrapf(err, "NetworkServiceEndpointRegistry find server failed to send a response %s", resp.String())
 		}
 	}
+	// Trailer of the remote registry has the watch revision, it is relayed to the client, see findoptions.Revision
+	_ = grpc.SetTrailer(ctx, trailer)
 
 	return next.NetworkServiceEndpointRegistryServer(ctx).Find(query, server)
 }
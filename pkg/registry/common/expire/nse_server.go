@@ -31,6 +31,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/common/begin"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/updatepath"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/tombstone"
 	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 	"github.com/networkservicemesh/sdk/pkg/tools/log"
 	"github.com/networkservicemesh/sdk/pkg/tools/token"
@@ -101,7 +102,7 @@ func (s *expireNSEServer) Register(ctx context.Context, nse *registry.NetworkSer
 		case <-expireContext.Done():
 			return
 		case <-expireCh:
-			factory.Unregister(begin.CancelContext(expireContext), begin.ExtendContext(ctx))
+			factory.Unregister(begin.CancelContext(expireContext), begin.ExtendContext(tombstone.WithReason(ctx, tombstone.Expired)))
 		}
 	}()
 
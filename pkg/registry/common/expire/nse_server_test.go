@@ -29,7 +29,9 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -43,8 +45,10 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/common/updatepath"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
 	"github.com/networkservicemesh/sdk/pkg/registry/utils/inject/injecterror"
 	"github.com/networkservicemesh/sdk/pkg/registry/utils/inject/injectpeertoken"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/tombstone"
 	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
 	"github.com/networkservicemesh/sdk/pkg/tools/token"
@@ -257,6 +261,54 @@ func TestExpireNSEServer_ShouldRemoveNSEAfterExpirationTime(t *testing.T) {
 	}, testWait, testTick)
 }
 
+func TestExpireNSEServer_ShouldSendExpiredTombstone(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.New(ctx)
+	ctx = clock.WithClock(ctx, clockMock)
+
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+
+	s := next.NewNetworkServiceEndpointRegistryServer(
+		begin.NewNetworkServiceEndpointRegistryServer(),
+		expire.NewNetworkServiceEndpointRegistryServer(ctx, expire.WithDefaultExpiration(expireTimeout)),
+		mem,
+	)
+
+	_, err := s.Register(ctx, &registry.NetworkServiceEndpoint{
+		Name: nseName,
+	})
+	require.NoError(t, err)
+
+	var trailer metadata.MD
+	stream, err := adapters.NetworkServiceEndpointServerToClient(mem).Find(
+		findoptions.WithOptions(ctx, &findoptions.Options{DeleteReasons: true}),
+		&registry.NetworkServiceEndpointQuery{
+			NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+			Watch:                  true,
+		},
+		grpc.Trailer(&trailer),
+	)
+	require.NoError(t, err)
+	ch := registry.ReadNetworkServiceEndpointChannel(stream)
+	require.False(t, (<-ch).GetDeleted())
+
+	clockMock.Add(expireTimeout)
+
+	select {
+	case <-ctx.Done():
+		require.FailNow(t, "no deleted event")
+	case resp := <-ch:
+		require.True(t, resp.GetDeleted())
+	}
+	_, ok := <-ch
+	require.False(t, ok)
+	require.Equal(t, tombstone.Expired, tombstone.TrailerReason(trailer, nseName))
+}
+
 func TestExpireNSEServer_DataRace(t *testing.T) {
 	t.Cleanup(func() { goleak.VerifyNone(t) })
 
@@ -18,16 +18,25 @@ package heal_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/networkservicemesh/api/pkg/api/registry"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/nsmgr"
 	registryclient "github.com/networkservicemesh/sdk/pkg/registry/chains/client"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/heal"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/streamchannel"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
 	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
 )
 
@@ -136,3 +145,129 @@ func TestHealClient_FindTest(t *testing.T) {
 		return err != nil
 	}, 2*time.Second, time.Millisecond*30)
 }
+
+// memoryNSEClient calls the memory registry like adapters.NetworkServiceEndpointServerToClient does, but the watch stream
+// is unbuffered, it is closed with the error returned by the registry and it sets the trailer like grpc does
+type memoryNSEClient struct {
+	registry.NetworkServiceEndpointRegistryClient
+	server registry.NetworkServiceEndpointRegistryServer
+}
+
+func (c *memoryNSEClient) Find(ctx context.Context, query *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	stream := new(trailerStream)
+	ch := make(chan *registry.NetworkServiceEndpointResponse)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		errCh <- c.server.Find(query, streamchannel.NewNetworkServiceEndpointFindServer(grpc.NewContextWithServerTransportStream(ctx, stream), ch))
+		for _, opt := range opts {
+			if trailer, ok := opt.(grpc.TrailerCallOption); ok {
+				*trailer.TrailerAddr = stream.trailer
+			}
+		}
+	}()
+	return &memoryNSEFindClient{
+		NetworkServiceEndpointRegistry_FindClient: streamchannel.NewNetworkServiceEndpointFindClient(ctx, ch),
+		errCh: errCh,
+	}, nil
+}
+
+type memoryNSEFindClient struct {
+	registry.NetworkServiceEndpointRegistry_FindClient
+	errCh <-chan error
+}
+
+func (c *memoryNSEFindClient) Recv() (*registry.NetworkServiceEndpointResponse, error) {
+	resp, err := c.NetworkServiceEndpointRegistry_FindClient.Recv()
+	if err != nil {
+		if findErr := <-c.errCh; findErr != nil {
+			return nil, findErr
+		}
+	}
+	return resp, err
+}
+
+type trailerStream struct {
+	trailer metadata.MD
+}
+
+func (s *trailerStream) Method() string {
+	return ""
+}
+
+func (s *trailerStream) SetHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *trailerStream) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *trailerStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestHealClient_FindLaggingWatcher(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	mem := memory.NewNetworkServiceEndpointRegistryServer(memory.WithBufferSize(2))
+	memClient := &memoryNSEClient{
+		NetworkServiceEndpointRegistryClient: adapters.NetworkServiceEndpointServerToClient(mem),
+		server:                               mem,
+	}
+	client := next.NewNetworkServiceEndpointRegistryClient(heal.NewNetworkServiceEndpointRegistryClient(ctx), memClient)
+
+	query := &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+		Watch:                  true,
+	}
+
+	_, err := mem.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-0"})
+	require.NoError(t, err)
+
+	// 1. Get the revision of nse-0
+	var trailer metadata.MD
+	watchCtx, watchCancel := context.WithCancel(ctx)
+	stream, err := memClient.Find(findoptions.WithOptions(watchCtx, &findoptions.Options{WatchRevisions: true}), query, grpc.Trailer(&trailer))
+	require.NoError(t, err)
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "nse-0", resp.GetNetworkServiceEndpoint().GetName())
+	watchCancel()
+	_, err = stream.Recv()
+	require.Error(t, err)
+	revision := findoptions.Revision(trailer)
+	require.NotZero(t, revision)
+
+	// 2. The watcher resumes after nse-0 and lags behind the next events, so it has to resync all of them
+	findCtx, findCancel := context.WithCancel(ctx)
+	defer findCancel()
+
+	stream, err = client.Find(findoptions.WithOptions(findCtx, &findoptions.Options{FromRevision: revision}), query)
+	require.NoError(t, err)
+
+	_, err = mem.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "nse-1", resp.GetNetworkServiceEndpoint().GetName())
+
+	for i := 2; i < 10; i++ {
+		_, err = mem.Register(ctx, &registry.NetworkServiceEndpoint{Name: fmt.Sprintf("nse-%d", i)})
+		require.NoError(t, err)
+	}
+
+	names := map[string]bool{"nse-1": true}
+	for len(names) < 10 {
+		resp, err = stream.Recv()
+		require.NoError(t, err)
+		names[resp.GetNetworkServiceEndpoint().GetName()] = true
+	}
+	for i := 0; i < 10; i++ {
+		require.True(t, names[fmt.Sprintf("nse-%d", i)])
+	}
+}
@@ -29,6 +29,7 @@ import (
 
 	"github.com/networkservicemesh/sdk/pkg/registry/common/begin"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
 )
 
 type healNSClient struct {
@@ -86,15 +87,28 @@ func (c *healNSClient) Find(ctx context.Context, query *registry.NetworkServiceQ
 
 	query = proto.Clone(query).(*registry.NetworkServiceQuery)
 
+	// Revisions are requested to resume the stream from the last received event
+	options, err := findoptions.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	options = options.Clone()
+	options.WatchRevisions = true
+
 	nextClient := next.NetworkServiceRegistryClient(ctx)
 
-	createStream := func() (registry.NetworkServiceRegistry_FindClient, error) {
+	findClient := &healNSFindClient{revision: options.FromRevision}
+	opts = append(append([]grpc.CallOption(nil), opts...), grpc.Trailer(&findClient.trailer))
+
+	findClient.createStream = func(revision uint64) (registry.NetworkServiceRegistry_FindClient, error) {
+		streamOptions := options.Clone()
+		streamOptions.FromRevision = revision
 		queryClone := proto.Clone(query).(*registry.NetworkServiceQuery)
-		return nextClient.Find(withNSFindHealing(ctx), queryClone, opts...)
+		return nextClient.Find(findoptions.WithOptions(withNSFindHealing(ctx), streamOptions), queryClone, opts...)
 	}
 
 	queryClone := proto.Clone(query).(*registry.NetworkServiceQuery)
-	stream, err := nextClient.Find(ctx, queryClone, opts...)
+	stream, err := nextClient.Find(findoptions.WithOptions(ctx, options), queryClone, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -108,11 +122,9 @@ func (c *healNSClient) Find(ctx context.Context, query *registry.NetworkServiceQ
 		clientCancel()
 	}()
 
-	return &healNSFindClient{
-		ctx:                               clientCtx,
-		createStream:                      createStream,
-		NetworkServiceRegistry_FindClient: stream,
-	}, nil
+	findClient.ctx = clientCtx
+	findClient.NetworkServiceRegistry_FindClient = stream
+	return findClient, nil
 }
 
 func (c *healNSClient) Unregister(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*empty.Empty, error) {
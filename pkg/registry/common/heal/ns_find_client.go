@@ -23,12 +23,19 @@ import (
 
 	"github.com/networkservicemesh/api/pkg/api/registry"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
 )
 
 type healNSFindClient struct {
 	ctx          context.Context
 	err          error
-	createStream func() (registry.NetworkServiceRegistry_FindClient, error)
+	createStream func(revision uint64) (registry.NetworkServiceRegistry_FindClient, error)
+	// trailer of the current stream, it has the revision to resume the next stream from
+	trailer  metadata.MD
+	revision uint64
 
 	registry.NetworkServiceRegistry_FindClient
 }
@@ -40,8 +47,18 @@ func (c *healNSFindClient) Recv() (*registry.NetworkServiceResponse, error) {
 
 	nsResp, err := c.NetworkServiceRegistry_FindClient.Recv()
 	for ; err != nil; nsResp, err = c.NetworkServiceRegistry_FindClient.Recv() {
-		c.NetworkServiceRegistry_FindClient, err = c.createStream()
-		for ; err != nil; c.NetworkServiceRegistry_FindClient, err = c.createStream() {
+		// The stream broken before the trailer is received is resumed from the revision received earlier
+		if revision := findoptions.Revision(c.trailer); revision > 0 {
+			c.revision = revision
+		}
+		// The lagging watcher has missed some events, so it resyncs the full state
+		if memory.IsResyncRequired(err) {
+			c.revision = 0
+		}
+		c.trailer = nil
+
+		c.NetworkServiceRegistry_FindClient, err = c.createStream(c.revision)
+		for ; err != nil; c.NetworkServiceRegistry_FindClient, err = c.createStream(c.revision) {
 			if c.ctx.Err() != nil {
 				c.err = c.ctx.Err()
 				return nil, errors.WithStack(c.err)
@@ -29,6 +29,7 @@ import (
 
 	"github.com/networkservicemesh/sdk/pkg/registry/common/begin"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
 )
 
 type healNSEClient struct {
@@ -86,15 +87,28 @@ func (c *healNSEClient) Find(ctx context.Context, query *registry.NetworkService
 
 	query = proto.Clone(query).(*registry.NetworkServiceEndpointQuery)
 
+	// Revisions are requested to resume the stream from the last received event
+	options, err := findoptions.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	options = options.Clone()
+	options.WatchRevisions = true
+
 	nextClient := next.NetworkServiceEndpointRegistryClient(ctx)
 
-	createStream := func() (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	findClient := &healNSEFindClient{revision: options.FromRevision}
+	opts = append(append([]grpc.CallOption(nil), opts...), grpc.Trailer(&findClient.trailer))
+
+	findClient.createStream = func(revision uint64) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+		streamOptions := options.Clone()
+		streamOptions.FromRevision = revision
 		queryClone := proto.Clone(query).(*registry.NetworkServiceEndpointQuery)
-		return nextClient.Find(withNSEFindHealing(ctx), queryClone, opts...)
+		return nextClient.Find(findoptions.WithOptions(withNSEFindHealing(ctx), streamOptions), queryClone, opts...)
 	}
 
 	queryClone := proto.Clone(query).(*registry.NetworkServiceEndpointQuery)
-	stream, err := nextClient.Find(ctx, queryClone, opts...)
+	stream, err := nextClient.Find(findoptions.WithOptions(ctx, options), queryClone, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -108,11 +122,9 @@ func (c *healNSEClient) Find(ctx context.Context, query *registry.NetworkService
 		clientCancel()
 	}()
 
-	return &healNSEFindClient{
-		ctx:          clientCtx,
-		createStream: createStream,
-		NetworkServiceEndpointRegistry_FindClient: stream,
-	}, nil
+	findClient.ctx = clientCtx
+	findClient.NetworkServiceEndpointRegistry_FindClient = stream
+	return findClient, nil
 }
 
 func (c *healNSEClient) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*empty.Empty, error) {
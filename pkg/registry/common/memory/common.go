@@ -16,4 +16,13 @@
 
 package memory
 
-const defaultEventChannelSize = 10
+import "time"
+
+const (
+	// defaultBufferSize is much larger than the event channel size of 10 used before: the watcher exceeding the
+	// buffer is disconnected instead of blocking the registry, so the buffer should fit bursts of registrations
+	defaultBufferSize  = 1000
+	defaultHistorySize = 1000
+
+	defaultTombstoneRetention = 30 * time.Second
+)
@@ -0,0 +1,82 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"math/rand"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type historyEntry[T proto.Message] struct {
+	name     string
+	revision uint64
+	event    T
+}
+
+// history is a bounded log of the latest registry events. Revision of the event is its sequence number, so the history
+// can tell if it still has all events after the given revision. It is not thread safe and should be used from the
+// server executor.
+type history[T proto.Message] struct {
+	size     int
+	revision uint64
+	entries  []*historyEntry[T]
+}
+
+// newHistory creates a history. Revisions start from a random number, so the watcher resuming from the revision of
+// the previous registry instance gets all matching entities instead of the wrong deltas.
+func newHistory[T proto.Message](size int) history[T] {
+	return history[T]{
+		size: size,
+		// #nosec G404 - the revision is not a secret, it only needs to differ between registry instances
+		revision: rand.Uint64() >> 1,
+	}
+}
+
+// add appends event to the history and returns its revision
+func (h *history[T]) add(name string, event T) uint64 {
+	h.revision++
+
+	if h.size <= 0 {
+		return h.revision
+	}
+	if len(h.entries) == h.size {
+		h.entries[0] = nil
+		h.entries = h.entries[1:]
+	}
+	h.entries = append(h.entries, &historyEntry[T]{name: name, revision: h.revision, event: event})
+	return h.revision
+}
+
+// since returns all events after the revision. Returns false if some of them are already dropped from the history or
+// revision is unknown to the history.
+func (h *history[T]) since(revision uint64) ([]*historyEntry[T], bool) {
+	switch {
+	case revision > h.revision:
+		return nil, false
+	case revision == h.revision:
+		return nil, true
+	case len(h.entries) == 0:
+		return nil, false
+	}
+
+	first := h.revision - uint64(len(h.entries)) + 1
+	if revision+1 < first {
+		return nil, false
+	}
+	return h.entries[revision+1-first:], true
+}
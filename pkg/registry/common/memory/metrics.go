@@ -0,0 +1,62 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
+)
+
+// registerWatcherMetrics registers lag and coalesced events gauges of the watcher if opentelemetry is enabled. Returned
+// func unregisters them, it should be called when the watcher is gone.
+func registerWatcherMetrics[T any](kind string, sub *subscriber[T]) (unregister func()) {
+	unregister = func() {}
+	if !opentelemetry.IsEnabled() {
+		return unregister
+	}
+
+	meter := otel.Meter("")
+	lagGauge, err := meter.Int64ObservableGauge(kind+"_watcher_lag",
+		metric.WithDescription("Count of events pending for the registry watcher"))
+	if err != nil {
+		return unregister
+	}
+	coalescedGauge, err := meter.Int64ObservableGauge(kind+"_watcher_coalesced_events",
+		metric.WithDescription("Count of events replaced by newer events for the same entity"))
+	if err != nil {
+		return unregister
+	}
+
+	attrs := metric.WithAttributes(attribute.String("watcher", sub.id))
+	registration, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		lag, coalesced := sub.stats()
+		o.ObserveInt64(lagGauge, lag, attrs)
+		o.ObserveInt64(coalescedGauge, coalesced, attrs)
+		return nil
+	}, lagGauge, coalescedGauge)
+	if err != nil {
+		return unregister
+	}
+	return func() {
+		_ = registration.Unregister()
+	}
+}
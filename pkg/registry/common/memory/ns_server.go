@@ -31,21 +31,23 @@ import (
 	"github.com/networkservicemesh/api/pkg/api/registry"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
 	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
 )
 
 type memoryNSServer struct {
-	networkServices  genericsync.Map[string, *registry.NetworkService]
-	executor         serialize.Executor
-	eventChannels    map[string]chan *registry.NetworkService
-	eventChannelSize int
+	networkServices genericsync.Map[string, *registry.NetworkService]
+	executor        serialize.Executor
+	subscribers     genericsync.Map[string, *subscriber[*registry.NetworkServiceResponse]]
+	history         history[*registry.NetworkServiceResponse]
+	bufferSize      int
 }
 
 // NewNetworkServiceRegistryServer creates new memory based NetworkServiceRegistryServer
 func NewNetworkServiceRegistryServer(options ...Option) registry.NetworkServiceRegistryServer {
 	s := &memoryNSServer{
-		eventChannelSize: defaultEventChannelSize,
-		eventChannels:    make(map[string]chan *registry.NetworkService),
+		bufferSize: defaultBufferSize,
+		history:    newHistory[*registry.NetworkServiceResponse](defaultHistorySize),
 	}
 	for _, o := range options {
 		o.apply(s)
@@ -53,8 +55,12 @@ func NewNetworkServiceRegistryServer(options ...Option) registry.NetworkServiceR
 	return s
 }
 
-func (s *memoryNSServer) setEventChannelSize(l int) {
-	s.eventChannelSize = l
+func (s *memoryNSServer) setBufferSize(l int) {
+	s.bufferSize = l
+}
+
+func (s *memoryNSServer) setHistorySize(l int) {
+	s.history.size = l
 }
 
 func (s *memoryNSServer) Register(ctx context.Context, ns *registry.NetworkService) (*registry.NetworkService, error) {
@@ -65,23 +71,36 @@ func (s *memoryNSServer) Register(ctx context.Context, ns *registry.NetworkServi
 
 	s.networkServices.Store(r.Name, r.Clone())
 
-	s.sendEvent(r)
+	s.sendEvent(&registry.NetworkServiceResponse{NetworkService: r})
 
 	return r, nil
 }
 
-func (s *memoryNSServer) sendEvent(event *registry.NetworkService) {
+func (s *memoryNSServer) sendEvent(event *registry.NetworkServiceResponse) {
 	event = event.Clone()
 	s.executor.AsyncExec(func() {
-		for _, ch := range s.eventChannels {
-			ch <- event.Clone()
-		}
+		revision := s.history.add(event.GetNetworkService().GetName(), event)
+		s.subscribers.Range(func(_ string, sub *subscriber[*registry.NetworkServiceResponse]) bool {
+			sub.push(event.GetNetworkService().GetName(), event.Clone(), revision)
+			return true
+		})
 	})
 }
 
 func (s *memoryNSServer) Find(query *registry.NetworkServiceQuery, server registry.NetworkServiceRegistry_FindServer) error {
+	// Network services have no labels, so only pagination and revisions options are applied to them
+	options, err := findoptions.FromContext(server.Context())
+	if err != nil {
+		return err
+	}
+
 	if !query.Watch {
-		for _, ns := range s.allMatches(query) {
+		page, nextToken, err := findoptions.Page(s.allMatches(query), options)
+		if err != nil {
+			return err
+		}
+		findoptions.SetNextToken(server.Context(), nextToken)
+		for _, ns := range page {
 			nsResp := &registry.NetworkServiceResponse{
 				NetworkService: ns,
 			}
@@ -93,19 +112,18 @@ func (s *memoryNSServer) Find(query *registry.NetworkServiceQuery, server regist
 		return next.NetworkServiceRegistryServer(server.Context()).Find(query, server)
 	}
 
-	eventCh := make(chan *registry.NetworkService, s.eventChannelSize)
-	id := uuid.New().String()
-
-	s.executor.AsyncExec(func() {
-		s.eventChannels[id] = eventCh
-		for _, entity := range s.allMatches(query) {
-			eventCh <- entity
-		}
+	sub := s.subscribe(query, options)
+	defer s.executor.AsyncExec(func() {
+		s.subscribers.Delete(sub.id)
 	})
-	defer s.closeEventChannel(id, eventCh)
+	defer registerWatcherMetrics("registry_ns", sub)()
 
-	var err error
-	for ; err == nil; err = s.receiveEvent(query, server, eventCh) {
+	var revision uint64
+	for ; err == nil; err = s.receiveEvents(query, server, sub, &revision) {
+	}
+	// The lagging watcher has missed some events, so it gets no revision to resume from and has to resync
+	if options.WatchRevisions && revision > 0 && !errors.Is(err, errResyncRequired) {
+		findoptions.SetRevision(server.Context(), revision)
 	}
 	if !errors.Is(err, io.EOF) {
 		return err
@@ -113,6 +131,31 @@ func (s *memoryNSServer) Find(query *registry.NetworkServiceQuery, server regist
 	return next.NetworkServiceRegistryServer(server.Context()).Find(query, server)
 }
 
+// subscribe creates a new subscriber and replays to it either the events missed since options.FromRevision or all
+// matching entities
+func (s *memoryNSServer) subscribe(query *registry.NetworkServiceQuery, options *findoptions.Options) *subscriber[*registry.NetworkServiceResponse] {
+	sub := newSubscriber[*registry.NetworkServiceResponse](uuid.New().String(), s.bufferSize)
+
+	s.executor.AsyncExec(func() {
+		s.subscribers.Store(sub.id, sub)
+
+		if options.FromRevision > 0 {
+			if entries, ok := s.history.since(options.FromRevision); ok {
+				for _, entry := range entries {
+					sub.replay(entry.name, entry.event.Clone(), entry.revision)
+				}
+				return
+			}
+		}
+
+		for _, entity := range s.allMatches(query) {
+			sub.replay(entity.GetName(), &registry.NetworkServiceResponse{NetworkService: entity}, s.history.revision)
+		}
+	})
+
+	return sub
+}
+
 func (s *memoryNSServer) allMatches(query *registry.NetworkServiceQuery) (matches []*registry.NetworkService) {
 	s.networkServices.Range(func(_ string, ns *registry.NetworkService) bool {
 		if matchutils.MatchNetworkServices(query.NetworkService, ns) {
@@ -123,44 +166,34 @@ func (s *memoryNSServer) allMatches(query *registry.NetworkServiceQuery) (matche
 	return matches
 }
 
-func (s *memoryNSServer) closeEventChannel(id string, eventCh <-chan *registry.NetworkService) {
-	ctx, cancel := context.WithCancel(context.Background())
-
-	s.executor.AsyncExec(func() {
-		delete(s.eventChannels, id)
-		cancel()
-	})
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-eventCh:
-		}
-	}
-}
-
-func (s *memoryNSServer) receiveEvent(
+// receiveEvents sends the pending events to the watcher and updates sent with the revision of the events sent in full.
+// sent is not updated for the lagging watcher, as the events following the pending ones are dropped.
+func (s *memoryNSServer) receiveEvents(
 	query *registry.NetworkServiceQuery,
 	server registry.NetworkServiceRegistry_FindServer,
-	eventCh <-chan *registry.NetworkService,
+	sub *subscriber[*registry.NetworkServiceResponse],
+	sent *uint64,
 ) error {
 	select {
 	case <-server.Context().Done():
 		return errors.WithStack(io.EOF)
-	case event := <-eventCh:
-		if matchutils.MatchNetworkServices(query.NetworkService, event) {
-			nse := &registry.NetworkServiceResponse{
-				NetworkService: event,
+	case <-sub.signalCh:
+		events, revision, lagging := sub.pop()
+		for _, event := range events {
+			if !matchutils.MatchNetworkServices(query.NetworkService, event.GetNetworkService()) {
+				continue
 			}
-
-			if err := server.Send(nse); err != nil {
+			if err := server.Send(event); err != nil {
 				if server.Context().Err() != nil {
 					return errors.WithStack(io.EOF)
 				}
-				return errors.Wrapf(err, "NetworkServiceRegistry find server failed to send a response %s", nse.String())
+				return errors.Wrapf(err, "NetworkServiceRegistry find server failed to send a response %s", event.String())
 			}
 		}
+		if lagging {
+			return errResyncRequired
+		}
+		*sent = revision
 		return nil
 	}
 }
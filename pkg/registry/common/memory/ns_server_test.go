@@ -29,6 +29,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
+	"google.golang.org/grpc"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/networkservicemesh/api/pkg/api/registry"
@@ -36,6 +37,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/streamchannel"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
 )
 
 func TestNetworkServiceRegistryServer_RegisterAndFind(t *testing.T) {
@@ -75,6 +77,45 @@ func TestNetworkServiceRegistryServer_RegisterAndFind(t *testing.T) {
 	require.True(t, proto.Equal(expected, <-ch))
 }
 
+func TestNetworkServiceRegistryServer_FindPages(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	s := next.NewNetworkServiceRegistryServer(memory.NewNetworkServiceRegistryServer())
+
+	for i := 0; i < 5; i++ {
+		_, err := s.Register(context.Background(), &registry.NetworkService{Name: fmt.Sprintf("ns-%d", i)})
+		require.NoError(t, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var pages [][]string
+	options := &findoptions.Options{PageSize: 2}
+	for {
+		ch := make(chan *registry.NetworkServiceResponse, 10)
+		err := s.Find(&registry.NetworkServiceQuery{
+			NetworkService: &registry.NetworkService{},
+		}, streamchannel.NewNetworkServiceFindServer(findoptions.WithOptions(ctx, options), ch))
+		require.NoError(t, err)
+		close(ch)
+
+		var page []string
+		for resp := range ch {
+			page = append(page, resp.GetNetworkService().GetName())
+		}
+		pages = append(pages, page)
+		if options.NextToken == "" {
+			break
+		}
+		options = &findoptions.Options{
+			PageSize:      2,
+			ContinueToken: options.NextToken,
+		}
+	}
+
+	require.Equal(t, [][]string{{"ns-0", "ns-1"}, {"ns-2", "ns-3"}, {"ns-4"}}, pages)
+}
+
 func TestNetworkServiceRegistryServer_RegisterAndFindWatch(t *testing.T) {
 	t.Cleanup(func() { goleak.VerifyNone(t) })
 	s := next.NewNetworkServiceRegistryServer(memory.NewNetworkServiceRegistryServer())
@@ -119,6 +160,75 @@ func TestNetworkServiceRegistryServer_RegisterAndFindWatch(t *testing.T) {
 	require.True(t, proto.Equal(&registry.NetworkServiceResponse{NetworkService: expected}, <-ch))
 }
 
+func TestNetworkServiceRegistryServer_WatchFromRevision(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	s := memory.NewNetworkServiceRegistryServer(memory.WithHistorySize(3))
+
+	// watch returns the channel of events and the func closing the stream, it returns the revision from the trailer
+	watch := func(fromRevision uint64) (<-chan *registry.NetworkServiceResponse, func() uint64) {
+		stream := new(trailerStream)
+		findCtx, findCancel := context.WithCancel(ctx)
+		findCtx = grpc.NewContextWithServerTransportStream(findoptions.WithOptions(findCtx, &findoptions.Options{
+			WatchRevisions: true,
+			FromRevision:   fromRevision,
+		}), stream)
+		ch := make(chan *registry.NetworkServiceResponse, 10)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = s.Find(&registry.NetworkServiceQuery{
+				NetworkService: &registry.NetworkService{},
+				Watch:          true,
+			}, streamchannel.NewNetworkServiceFindServer(findCtx, ch))
+		}()
+		return ch, func() uint64 {
+			findCancel()
+			<-done
+			return stream.revision()
+		}
+	}
+
+	ch, stop := watch(0)
+	_, err := s.Register(ctx, &registry.NetworkService{Name: "ns-1"})
+	require.NoError(t, err)
+	resp, err := readNSResponse(ctx, ch)
+	require.NoError(t, err)
+	require.Equal(t, "ns-1", resp.GetNetworkService().GetName())
+	revision := stop()
+	require.NotZero(t, revision)
+
+	// Only the missed events are replayed
+	_, err = s.Register(ctx, &registry.NetworkService{Name: "ns-2"})
+	require.NoError(t, err)
+	_, err = s.Register(ctx, &registry.NetworkService{Name: "ns-1", Payload: "IP"})
+	require.NoError(t, err)
+
+	ch, stop = watch(revision)
+	resp, err = readNSResponse(ctx, ch)
+	require.NoError(t, err)
+	require.Equal(t, "ns-2", resp.GetNetworkService().GetName())
+	resp, err = readNSResponse(ctx, ch)
+	require.NoError(t, err)
+	require.Equal(t, "ns-1", resp.GetNetworkService().GetName())
+	require.Equal(t, "IP", resp.GetNetworkService().GetPayload())
+	require.Equal(t, revision+2, stop())
+
+	// The revision unknown to the registry is resumed with all the network services
+	ch, stop = watch(revision + 100)
+	names := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		resp, err = readNSResponse(ctx, ch)
+		require.NoError(t, err)
+		names[resp.GetNetworkService().GetName()] = true
+	}
+	require.Len(t, names, 2)
+	require.Equal(t, revision+2, stop())
+}
+
 func TestNetworkServiceRegistryServer_DataRace(t *testing.T) {
 	t.Cleanup(func() { goleak.VerifyNone(t) })
 
@@ -21,6 +21,7 @@ package memory
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/edwarnicke/genericsync"
 	"github.com/edwarnicke/serialize"
@@ -31,21 +32,50 @@ import (
 	"github.com/networkservicemesh/api/pkg/api/registry"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/tombstone"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
 )
 
 type memoryNSEServer struct {
 	networkServiceEndpoints genericsync.Map[string, *registry.NetworkServiceEndpoint]
 	executor                serialize.Executor
-	eventChannels           map[string]chan *registry.NetworkServiceEndpointResponse
-	eventChannelSize        int
+	subscribers             genericsync.Map[string, *subscriber[*nseEvent]]
+	history                 history[*nseEvent]
+	tombstones              map[string]*tombstoneEntry
+	tombstoneRetention      time.Duration
+	pruneTimer              clock.Timer
+	bufferSize              int
+}
+
+// tombstoneEntry is a deleted event replayed to the new watchers until the retention window is over
+type tombstoneEntry struct {
+	event      *nseEvent
+	expiration time.Time
+}
+
+// nseEvent is the registry event with the reason of the deletion. The registry API has no field for the reason, so it
+// is sent in the trailer, see findoptions.Options.DeleteReasons.
+type nseEvent struct {
+	*registry.NetworkServiceEndpointResponse
+	reason tombstone.Reason
+}
+
+func (e *nseEvent) clone() *nseEvent {
+	return &nseEvent{
+		NetworkServiceEndpointResponse: e.NetworkServiceEndpointResponse.Clone(),
+		reason:                         e.reason,
+	}
 }
 
 // NewNetworkServiceEndpointRegistryServer creates new memory based NetworkServiceEndpointRegistryServer
 func NewNetworkServiceEndpointRegistryServer(options ...Option) registry.NetworkServiceEndpointRegistryServer {
 	s := &memoryNSEServer{
-		eventChannelSize: defaultEventChannelSize,
-		eventChannels:    make(map[string]chan *registry.NetworkServiceEndpointResponse),
+		bufferSize:         defaultBufferSize,
+		history:            newHistory[*nseEvent](defaultHistorySize),
+		tombstones:         make(map[string]*tombstoneEntry),
+		tombstoneRetention: defaultTombstoneRetention,
 	}
 	for _, o := range options {
 		o.apply(s)
@@ -53,8 +83,16 @@ func NewNetworkServiceEndpointRegistryServer(options ...Option) registry.Network
 	return s
 }
 
-func (s *memoryNSEServer) setEventChannelSize(l int) {
-	s.eventChannelSize = l
+func (s *memoryNSEServer) setBufferSize(l int) {
+	s.bufferSize = l
+}
+
+func (s *memoryNSEServer) setHistorySize(l int) {
+	s.history.size = l
+}
+
+func (s *memoryNSEServer) setTombstoneRetention(d time.Duration) {
+	s.tombstoneRetention = d
 }
 
 func (s *memoryNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
@@ -65,23 +103,77 @@ func (s *memoryNSEServer) Register(ctx context.Context, nse *registry.NetworkSer
 
 	s.networkServiceEndpoints.Store(r.Name, r.Clone())
 
-	s.sendEvent(&registry.NetworkServiceEndpointResponse{NetworkServiceEndpoint: r})
+	s.sendEvent(ctx, &nseEvent{NetworkServiceEndpointResponse: &registry.NetworkServiceEndpointResponse{NetworkServiceEndpoint: r}})
 
 	return r, nil
 }
 
-func (s *memoryNSEServer) sendEvent(event *registry.NetworkServiceEndpointResponse) {
-	event = event.Clone()
+func (s *memoryNSEServer) sendEvent(ctx context.Context, event *nseEvent) {
+	event = event.clone()
+	timeClock := clock.FromContext(ctx)
 	s.executor.AsyncExec(func() {
-		for _, ch := range s.eventChannels {
-			ch <- event.Clone()
+		revision := s.history.add(event.GetNetworkServiceEndpoint().GetName(), event)
+		if event.GetDeleted() && s.tombstoneRetention > 0 {
+			s.tombstones[event.GetNetworkServiceEndpoint().GetName()] = &tombstoneEntry{
+				event:      event,
+				expiration: timeClock.Now().Add(s.tombstoneRetention),
+			}
+			s.schedulePrune(timeClock, s.tombstoneRetention)
+		} else {
+			delete(s.tombstones, event.GetNetworkServiceEndpoint().GetName())
 		}
+		s.subscribers.Range(func(_ string, sub *subscriber[*nseEvent]) bool {
+			sub.push(event.GetNetworkServiceEndpoint().GetName(), event.clone(), revision)
+			return true
+		})
+	})
+}
+
+// schedulePrune schedules removal of the expired tombstones, so they don't pile up without new watchers. Only one
+// prune is scheduled at a time, it reschedules itself until there are no tombstones left.
+func (s *memoryNSEServer) schedulePrune(timeClock clock.Clock, d time.Duration) {
+	if s.pruneTimer != nil {
+		return
+	}
+	s.pruneTimer = timeClock.AfterFunc(d, func() {
+		s.executor.AsyncExec(func() {
+			s.pruneTimer = nil
+
+			now := timeClock.Now()
+			var nextExpiration time.Time
+			for name, entry := range s.tombstones {
+				if !now.Before(entry.expiration) {
+					delete(s.tombstones, name)
+					continue
+				}
+				if nextExpiration.IsZero() || entry.expiration.Before(nextExpiration) {
+					nextExpiration = entry.expiration
+				}
+			}
+			if !nextExpiration.IsZero() {
+				s.schedulePrune(timeClock, nextExpiration.Sub(now))
+			}
+		})
 	})
 }
 
 func (s *memoryNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	options, err := findoptions.FromContext(server.Context())
+	if err != nil {
+		return err
+	}
+	selector, err := options.Selector()
+	if err != nil {
+		return err
+	}
+
 	if !query.Watch {
-		for _, nse := range s.allMatches(query) {
+		page, nextToken, err := findoptions.Page(s.allMatches(query, selector), options)
+		if err != nil {
+			return err
+		}
+		findoptions.SetNextToken(server.Context(), nextToken)
+		for _, nse := range page {
 			nseResp := &registry.NetworkServiceEndpointResponse{
 				NetworkServiceEndpoint: nse,
 			}
@@ -96,19 +188,18 @@ func (s *memoryNSEServer) Find(query *registry.NetworkServiceEndpointQuery, serv
 		return err
 	}
 
-	eventCh := make(chan *registry.NetworkServiceEndpointResponse, s.eventChannelSize)
-	id := uuid.New().String()
-
-	s.executor.AsyncExec(func() {
-		s.eventChannels[id] = eventCh
-		for _, entity := range s.allMatches(query) {
-			eventCh <- &registry.NetworkServiceEndpointResponse{NetworkServiceEndpoint: entity}
-		}
+	sub := s.subscribe(clock.FromContext(server.Context()).Now(), query, selector, options)
+	defer s.executor.AsyncExec(func() {
+		s.subscribers.Delete(sub.id)
 	})
-	defer s.closeEventChannel(id, eventCh)
+	defer registerWatcherMetrics("registry_nse", sub)()
 
-	var err error
-	for ; err == nil; err = s.receiveEvent(query, server, eventCh) {
+	var revision uint64
+	for ; err == nil; err = s.receiveEvents(query, selector, options, server, sub, &revision) {
+	}
+	// The lagging watcher has missed some events, so it gets no revision to resume from and has to resync
+	if options.WatchRevisions && revision > 0 && !errors.Is(err, errResyncRequired) {
+		findoptions.SetRevision(server.Context(), revision)
 	}
 	if !errors.Is(err, io.EOF) {
 		return err
@@ -116,9 +207,9 @@ func (s *memoryNSEServer) Find(query *registry.NetworkServiceEndpointQuery, serv
 	return nil
 }
 
-func (s *memoryNSEServer) allMatches(query *registry.NetworkServiceEndpointQuery) (matches []*registry.NetworkServiceEndpoint) {
+func (s *memoryNSEServer) allMatches(query *registry.NetworkServiceEndpointQuery, selector *matchutils.Selector) (matches []*registry.NetworkServiceEndpoint) {
 	s.networkServiceEndpoints.Range(func(_ string, nse *registry.NetworkServiceEndpoint) bool {
-		if matchutils.MatchNetworkServiceEndpoints(query.NetworkServiceEndpoint, nse) {
+		if match(query, selector, nse) {
 			matches = append(matches, nse.Clone())
 		}
 		return true
@@ -126,39 +217,95 @@ func (s *memoryNSEServer) allMatches(query *registry.NetworkServiceEndpointQuery
 	return matches
 }
 
-func (s *memoryNSEServer) closeEventChannel(id string, eventCh <-chan *registry.NetworkServiceEndpointResponse) {
-	ctx, cancel := context.WithCancel(context.Background())
+// subscribe creates a new subscriber and replays to it either the events missed since options.FromRevision or all
+// matching entities
+func (s *memoryNSEServer) subscribe(
+	now time.Time,
+	query *registry.NetworkServiceEndpointQuery,
+	selector *matchutils.Selector,
+	options *findoptions.Options,
+) *subscriber[*nseEvent] {
+	sub := newSubscriber[*nseEvent](uuid.New().String(), s.bufferSize)
 
 	s.executor.AsyncExec(func() {
-		delete(s.eventChannels, id)
-		cancel()
+		s.subscribers.Store(sub.id, sub)
+
+		if options.FromRevision > 0 {
+			if entries, ok := s.history.since(options.FromRevision); ok {
+				for _, entry := range entries {
+					sub.replay(entry.name, entry.event.clone(), entry.revision)
+				}
+				return
+			}
+		}
+
+		for _, entity := range s.allMatches(query, selector) {
+			sub.replay(entity.GetName(), &nseEvent{NetworkServiceEndpointResponse: &registry.NetworkServiceEndpointResponse{NetworkServiceEndpoint: entity}}, s.history.revision)
+		}
+		for _, event := range s.matchingTombstones(now, query, selector) {
+			sub.replay(event.GetNetworkServiceEndpoint().GetName(), event, s.history.revision)
+		}
 	})
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-eventCh:
+	return sub
+}
+
+// matchingTombstones returns deleted events retained for the new watchers and not expired by now
+func (s *memoryNSEServer) matchingTombstones(now time.Time, query *registry.NetworkServiceEndpointQuery, selector *matchutils.Selector) (events []*nseEvent) {
+	for _, entry := range s.tombstones {
+		if !now.Before(entry.expiration) {
+			continue
+		}
+		if match(query, selector, entry.event.GetNetworkServiceEndpoint()) {
+			events = append(events, entry.event.clone())
 		}
 	}
+	return events
 }
 
-func (s *memoryNSEServer) receiveEvent(
+func match(query *registry.NetworkServiceEndpointQuery, selector *matchutils.Selector, nse *registry.NetworkServiceEndpoint) bool {
+	return matchutils.MatchNetworkServiceEndpoints(query.GetNetworkServiceEndpoint(), nse) &&
+		selector.MatchesNetworkServiceEndpoint(nse, query.GetNetworkServiceEndpoint().GetNetworkServiceNames()...)
+}
+
+// receiveEvents sends the pending events to the watcher and updates sent with the revision of the events sent in full.
+// sent is not updated for the lagging watcher, as the events following the pending ones are dropped. The stream is
+// closed once the deleted events with the reasons are sent, if the reasons are requested.
+func (s *memoryNSEServer) receiveEvents(
 	query *registry.NetworkServiceEndpointQuery,
+	selector *matchutils.Selector,
+	options *findoptions.Options,
 	server registry.NetworkServiceEndpointRegistry_FindServer,
-	eventCh <-chan *registry.NetworkServiceEndpointResponse,
+	sub *subscriber[*nseEvent],
+	sent *uint64,
 ) error {
 	select {
 	case <-server.Context().Done():
 		return errors.WithStack(io.EOF)
-	case event := <-eventCh:
-		if matchutils.MatchNetworkServiceEndpoints(query.NetworkServiceEndpoint, event.NetworkServiceEndpoint) {
-			if err := server.Send(event); err != nil {
+	case <-sub.signalCh:
+		events, revision, lagging := sub.pop()
+		var closeStream bool
+		for _, event := range events {
+			if !match(query, selector, event.NetworkServiceEndpoint) {
+				continue
+			}
+			if err := server.Send(event.NetworkServiceEndpointResponse); err != nil {
 				if server.Context().Err() != nil {
 					return errors.WithStack(io.EOF)
 				}
 				return errors.Wrapf(err, "NetworkServiceRegistry find server failed to send a response %s", event.String())
 			}
+			if options.DeleteReasons && event.GetDeleted() && event.reason != tombstone.Unregistered {
+				tombstone.SetTrailerReason(server.Context(), event.GetNetworkServiceEndpoint().GetName(), event.reason)
+				closeStream = true
+			}
+		}
+		if lagging {
+			return errResyncRequired
+		}
+		*sent = revision
+		if closeStream {
+			return errors.WithStack(io.EOF)
 		}
 		return nil
 	}
@@ -166,8 +313,10 @@ func (s *memoryNSEServer) receiveEvent(
 
 func (s *memoryNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
 	if unregisterNSE, ok := s.networkServiceEndpoints.LoadAndDelete(nse.GetName()); ok {
-		unregisterNSE = unregisterNSE.Clone()
-		s.sendEvent(&registry.NetworkServiceEndpointResponse{NetworkServiceEndpoint: unregisterNSE, Deleted: true})
+		s.sendEvent(ctx, &nseEvent{
+			NetworkServiceEndpointResponse: &registry.NetworkServiceEndpointResponse{NetworkServiceEndpoint: unregisterNSE, Deleted: true},
+			reason:                         tombstone.ReasonFromContext(ctx),
+		})
 	}
 	return next.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, nse)
 }
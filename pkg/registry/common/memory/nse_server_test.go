@@ -30,11 +30,17 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/streamchannel"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/tombstone"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
 )
 
 func TestNetworkServiceEndpointRegistryServer_RegisterAndFind(t *testing.T) {
@@ -189,6 +195,77 @@ func TestNetworkServiceEndpointRegistryServer_RegisterAndFindByLabelWatch(t *tes
 	require.True(t, proto.Equal(&registry.NetworkServiceEndpointResponse{NetworkServiceEndpoint: expected}, <-ch))
 }
 
+func TestNetworkServiceEndpointRegistryServer_RegisterAndFindBySelector(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	s := next.NewNetworkServiceEndpointRegistryServer(memory.NewNetworkServiceEndpointRegistryServer())
+
+	for _, nse := range []*registry.NetworkServiceEndpoint{createLabeledNSE1(), createLabeledNSE2(), createLabeledNSE3()} {
+		_, err := s.Register(context.Background(), nse)
+		require.NoError(t, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ctx = findoptions.WithOptions(ctx, &findoptions.Options{
+		LabelSelector: "c in (d,e),!foo",
+	})
+
+	ch := make(chan *registry.NetworkServiceEndpointResponse, 10)
+	err := s.Find(&registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{
+			NetworkServiceNames: []string{"Service1"},
+		},
+	}, streamchannel.NewNetworkServiceEndpointFindServer(ctx, ch))
+	require.NoError(t, err)
+	close(ch)
+
+	var names []string
+	for resp := range ch {
+		names = append(names, resp.GetNetworkServiceEndpoint().GetName())
+	}
+	require.Equal(t, []string{"nse2"}, names)
+}
+
+func TestNetworkServiceEndpointRegistryServer_FindPages(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	s := next.NewNetworkServiceEndpointRegistryServer(memory.NewNetworkServiceEndpointRegistryServer())
+
+	for i := 0; i < 5; i++ {
+		_, err := s.Register(context.Background(), &registry.NetworkServiceEndpoint{Name: fmt.Sprintf("nse-%d", i)})
+		require.NoError(t, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var pages [][]string
+	options := &findoptions.Options{PageSize: 2}
+	for {
+		ch := make(chan *registry.NetworkServiceEndpointResponse, 10)
+		err := s.Find(&registry.NetworkServiceEndpointQuery{
+			NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{},
+		}, streamchannel.NewNetworkServiceEndpointFindServer(findoptions.WithOptions(ctx, options), ch))
+		require.NoError(t, err)
+		close(ch)
+
+		var page []string
+		for resp := range ch {
+			page = append(page, resp.GetNetworkServiceEndpoint().GetName())
+		}
+		pages = append(pages, page)
+		if options.NextToken == "" {
+			break
+		}
+		options = &findoptions.Options{
+			PageSize:      2,
+			ContinueToken: options.NextToken,
+		}
+	}
+
+	require.Equal(t, [][]string{{"nse-0", "nse-1"}, {"nse-2", "nse-3"}, {"nse-4"}}, pages)
+}
+
 func TestNetworkServiceEndpointRegistryServer_DataRace(t *testing.T) {
 	t.Cleanup(func() { goleak.VerifyNone(t) })
 
@@ -280,6 +357,309 @@ func TestNetworkServiceEndpointRegistryServer_SlowReceiver(t *testing.T) {
 	}, 100*time.Millisecond, time.Millisecond)
 }
 
+func TestNetworkServiceEndpointRegistryServer_LaggingWatcher(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	s := memory.NewNetworkServiceEndpointRegistryServer(memory.WithBufferSize(5))
+
+	// Nobody reads from the slow watcher channel
+	slowCh := make(chan *registry.NetworkServiceEndpointResponse)
+	slowErrCh := make(chan error, 1)
+	go func() {
+		slowErrCh <- s.Find(&registry.NetworkServiceEndpointQuery{
+			NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{},
+			Watch:                  true,
+		}, streamchannel.NewNetworkServiceEndpointFindServer(ctx, slowCh))
+	}()
+
+	fastCh := make(chan *registry.NetworkServiceEndpointResponse, 100)
+	go func() {
+		_ = s.Find(&registry.NetworkServiceEndpointQuery{
+			NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{},
+			Watch:                  true,
+		}, streamchannel.NewNetworkServiceEndpointFindServer(ctx, fastCh))
+	}()
+
+	_, err := s.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-first"})
+	require.NoError(t, err)
+	resp, err := receiveNSER(ctx, fastCh)
+	require.NoError(t, err)
+	require.Equal(t, "nse-first", resp.GetNetworkServiceEndpoint().GetName())
+
+	// Fast watcher receives all events while the slow one is stuck
+	for i := 0; i < 20; i++ {
+		_, err = s.Register(ctx, &registry.NetworkServiceEndpoint{Name: fmt.Sprintf("nse-%d", i)})
+		require.NoError(t, err)
+
+		resp, err = receiveNSER(ctx, fastCh)
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("nse-%d", i), resp.GetNetworkServiceEndpoint().GetName())
+	}
+
+	// Slow watcher is disconnected with the resync error
+	<-slowCh
+	select {
+	case err = <-slowErrCh:
+		require.True(t, memory.IsResyncRequired(err))
+	case <-ctx.Done():
+		require.FailNow(t, "slow watcher is not disconnected")
+	}
+}
+
+func TestNetworkServiceEndpointRegistryServer_CoalesceEvents(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	s := memory.NewNetworkServiceEndpointRegistryServer(memory.WithBufferSize(1))
+
+	findCtx, findCancel := context.WithCancel(ctx)
+	defer findCancel()
+
+	ch := make(chan *registry.NetworkServiceEndpointResponse)
+	go func() {
+		_ = s.Find(&registry.NetworkServiceEndpointQuery{
+			NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{},
+			Watch:                  true,
+		}, streamchannel.NewNetworkServiceEndpointFindServer(findCtx, ch))
+	}()
+
+	_, err := s.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse", Url: "tcp://0"})
+	require.NoError(t, err)
+
+	// The watcher is blocked sending the first event, the next ones are coalesced
+	for i := 1; i <= 10; i++ {
+		_, err = s.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse", Url: fmt.Sprintf("tcp://%d", i)})
+		require.NoError(t, err)
+	}
+
+	// Coalesced events are skipped, so the latest one is received in less than 11 events
+	for i := 0; ; i++ {
+		require.Less(t, i, 11)
+		resp, err := receiveNSER(ctx, ch)
+		require.NoError(t, err)
+		if resp.GetNetworkServiceEndpoint().GetUrl() == "tcp://10" {
+			break
+		}
+	}
+}
+
+func TestNetworkServiceEndpointRegistryServer_WatchFromRevision(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	s := memory.NewNetworkServiceEndpointRegistryServer(memory.WithHistorySize(3))
+
+	// watch returns the channel of events and the func closing the stream, it returns the revision from the trailer
+	watch := func(fromRevision uint64) (<-chan *registry.NetworkServiceEndpointResponse, func() uint64) {
+		stream := new(trailerStream)
+		findCtx, findCancel := context.WithCancel(ctx)
+		findCtx = grpc.NewContextWithServerTransportStream(findoptions.WithOptions(findCtx, &findoptions.Options{
+			WatchRevisions: true,
+			FromRevision:   fromRevision,
+		}), stream)
+		ch := make(chan *registry.NetworkServiceEndpointResponse, 10)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = s.Find(&registry.NetworkServiceEndpointQuery{
+				NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{},
+				Watch:                  true,
+			}, streamchannel.NewNetworkServiceEndpointFindServer(findCtx, ch))
+		}()
+		return ch, func() uint64 {
+			findCancel()
+			<-done
+			return stream.revision()
+		}
+	}
+
+	ch, stop := watch(0)
+	_, err := s.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+	resp, err := receiveNSER(ctx, ch)
+	require.NoError(t, err)
+	require.Equal(t, "nse-1", resp.GetNetworkServiceEndpoint().GetName())
+	revision := stop()
+	require.NotZero(t, revision)
+
+	// Missed events are still in the history
+	_, err = s.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-2"})
+	require.NoError(t, err)
+	_, err = s.Unregister(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+
+	ch, stop = watch(revision)
+	resp, err = receiveNSER(ctx, ch)
+	require.NoError(t, err)
+	require.Equal(t, "nse-2", resp.GetNetworkServiceEndpoint().GetName())
+	resp, err = receiveNSER(ctx, ch)
+	require.NoError(t, err)
+	require.Equal(t, "nse-1", resp.GetNetworkServiceEndpoint().GetName())
+	require.True(t, resp.GetDeleted())
+	require.Equal(t, revision+2, stop())
+	revision += 2
+
+	// Missed events are dropped from the history, so the watcher receives all entities
+	for i := 3; i < 10; i++ {
+		_, err = s.Register(ctx, &registry.NetworkServiceEndpoint{Name: fmt.Sprintf("nse-%d", i)})
+		require.NoError(t, err)
+	}
+	_, err = s.Unregister(ctx, &registry.NetworkServiceEndpoint{Name: "nse-2"})
+	require.NoError(t, err)
+
+	ch, stop = watch(revision)
+	names := make(map[string]bool)
+	for i := 3; i < 10; i++ {
+		resp, err = receiveNSER(ctx, ch)
+		require.NoError(t, err)
+		require.False(t, resp.GetDeleted())
+		names[resp.GetNetworkServiceEndpoint().GetName()] = true
+	}
+	require.Len(t, names, 7)
+
+	// Tombstones of the deleted NSEs are replayed after the registered ones
+	for i := 0; i < 2; i++ {
+		resp, err = receiveNSER(ctx, ch)
+		require.NoError(t, err)
+		require.True(t, resp.GetDeleted())
+	}
+	require.Equal(t, revision+8, stop())
+}
+
+func TestNetworkServiceEndpointRegistryServer_Tombstones(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	s := memory.NewNetworkServiceEndpointRegistryServer(memory.WithTombstoneRetention(time.Hour))
+
+	for _, name := range []string{"nse-1", "nse-2", "nse-3"} {
+		_, err := s.Register(ctx, &registry.NetworkServiceEndpoint{Name: name})
+		require.NoError(t, err)
+	}
+
+	_, err := s.Unregister(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+	_, err = s.Unregister(tombstone.WithReason(ctx, tombstone.Expired), &registry.NetworkServiceEndpoint{Name: "nse-2"})
+	require.NoError(t, err)
+
+	watch := func(revision uint64) (<-chan *registry.NetworkServiceEndpointResponse, <-chan error, *trailerStream) {
+		stream := new(trailerStream)
+		findCtx := grpc.NewContextWithServerTransportStream(findoptions.WithOptions(ctx, &findoptions.Options{
+			WatchRevisions: true,
+			FromRevision:   revision,
+			DeleteReasons:  true,
+		}), stream)
+		ch := make(chan *registry.NetworkServiceEndpointResponse, 10)
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- s.Find(&registry.NetworkServiceEndpointQuery{
+				NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{},
+				Watch:                  true,
+			}, streamchannel.NewNetworkServiceEndpointFindServer(findCtx, ch))
+		}()
+		return ch, errCh, stream
+	}
+	waitClosed := func(errCh <-chan error) {
+		select {
+		case err := <-errCh:
+			require.NoError(t, err)
+		case <-ctx.Done():
+			require.FailNow(t, "stream is not closed")
+		}
+	}
+
+	// Replayed tombstone with the reason closes the stream
+	ch, errCh, stream := watch(0)
+	deleted := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		resp, err := receiveNSER(ctx, ch)
+		require.NoError(t, err)
+		if resp.GetDeleted() {
+			deleted[resp.GetNetworkServiceEndpoint().GetName()] = true
+		}
+	}
+	require.Equal(t, map[string]bool{"nse-1": true, "nse-2": true}, deleted)
+	waitClosed(errCh)
+	require.Equal(t, tombstone.Unregistered, tombstone.TrailerReason(stream.trailer, "nse-1"))
+	require.Equal(t, tombstone.Expired, tombstone.TrailerReason(stream.trailer, "nse-2"))
+
+	// Watcher resuming from the revision doesn't receive the tombstones again, the new deletion with the reason closes
+	// the stream
+	revision := findoptions.Revision(stream.trailer)
+	require.NotZero(t, revision)
+	ch, errCh, stream = watch(revision)
+
+	_, err = s.Unregister(tombstone.WithReason(ctx, tombstone.Unreachable), &registry.NetworkServiceEndpoint{Name: "nse-3"})
+	require.NoError(t, err)
+	resp, err := receiveNSER(ctx, ch)
+	require.NoError(t, err)
+	require.True(t, resp.GetDeleted())
+	require.Equal(t, "nse-3", resp.GetNetworkServiceEndpoint().GetName())
+
+	waitClosed(errCh)
+	require.Equal(t, tombstone.Unreachable, tombstone.TrailerReason(stream.trailer, "nse-3"))
+	require.Equal(t, revision+1, findoptions.Revision(stream.trailer))
+}
+
+func TestNetworkServiceEndpointRegistryServer_TombstonesExpire(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	clockMock := clockmock.New(ctx)
+	ctx = clock.WithClock(ctx, clockMock)
+
+	s := memory.NewNetworkServiceEndpointRegistryServer(memory.WithTombstoneRetention(time.Minute))
+
+	_, err := s.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+	_, err = s.Unregister(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+
+	watch := func() (<-chan *registry.NetworkServiceEndpointResponse, context.CancelFunc) {
+		watchCtx, cancelWatch := context.WithCancel(ctx)
+		ch := make(chan *registry.NetworkServiceEndpointResponse, 10)
+		go func() {
+			_ = s.Find(&registry.NetworkServiceEndpointQuery{
+				NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{},
+				Watch:                  true,
+			}, streamchannel.NewNetworkServiceEndpointFindServer(watchCtx, ch))
+		}()
+		return ch, cancelWatch
+	}
+
+	ch, cancelWatch := watch()
+	resp, err := receiveNSER(ctx, ch)
+	require.NoError(t, err)
+	require.True(t, resp.GetDeleted())
+	require.Equal(t, "nse-1", resp.GetNetworkServiceEndpoint().GetName())
+	cancelWatch()
+
+	// The tombstone is not replayed once the retention window is over by the context clock
+	clockMock.Add(time.Minute)
+
+	ch, cancelWatch = watch()
+	defer cancelWatch()
+
+	_, err = s.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-2"})
+	require.NoError(t, err)
+
+	resp, err = receiveNSER(ctx, ch)
+	require.NoError(t, err)
+	require.False(t, resp.GetDeleted())
+	require.Equal(t, "nse-2", resp.GetNetworkServiceEndpoint().GetName())
+}
+
 func TestNetworkServiceEndpointRegistryServer_ShouldReceiveAllRegisters(t *testing.T) {
 	t.Cleanup(func() { goleak.VerifyNone(t) })
 
@@ -435,6 +815,39 @@ func createLabeledNSE3() *registry.NetworkServiceEndpoint {
 	}
 }
 
+// trailerStream is a grpc.ServerTransportStream capturing the trailer set by the server
+type trailerStream struct {
+	mu      sync.Mutex
+	trailer metadata.MD
+}
+
+func (s *trailerStream) Method() string {
+	return ""
+}
+
+func (s *trailerStream) SetHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *trailerStream) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *trailerStream) SetTrailer(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func (s *trailerStream) revision() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return findoptions.Revision(s.trailer)
+}
+
 func receiveNSER(ctx context.Context, ch <-chan *registry.NetworkServiceEndpointResponse) (*registry.NetworkServiceEndpointResponse, error) {
 	select {
 	case <-ctx.Done():
@@ -16,8 +16,15 @@
 
 package memory
 
+import "time"
+
 type configurable interface {
-	setEventChannelSize(int)
+	setBufferSize(int)
+	setHistorySize(int)
+}
+
+type tombstoneConfigurable interface {
+	setTombstoneRetention(time.Duration)
 }
 
 // Option is memory registry configuration option
@@ -31,9 +38,39 @@ func (f applierFunc) apply(c configurable) {
 	f(c)
 }
 
-// WithEventChannelSize sets specific size of event channels
-func WithEventChannelSize(l int) Option {
+// WithBufferSize sets max count of pending events per watcher. Events for the same entity are coalesced, so only the
+// latest of them is pending. The watcher exceeding the limit is disconnected with the resync error, see IsResyncRequired.
+// Default is 1000 pending entities.
+func WithBufferSize(l int) Option {
+	return applierFunc(func(c configurable) {
+		c.setBufferSize(l)
+	})
+}
+
+// WithHistorySize sets count of the latest events kept to resume watchers from the revision, see
+// findoptions.Options.FromRevision. Watchers resuming from the revision older than the history receive all matching
+// entities.
+func WithHistorySize(l int) Option {
 	return applierFunc(func(c configurable) {
-		c.setEventChannelSize(l)
+		c.setHistorySize(l)
 	})
 }
+
+// WithTombstoneRetention sets how long deleted NSE events are replayed to the new watchers, so the watchers
+// reconnecting after the deletion still see it with its reason, see findoptions.Options.DeleteReasons. 0 disables
+// tombstones.
+func WithTombstoneRetention(d time.Duration) Option {
+	return applierFunc(func(c configurable) {
+		if t, ok := c.(tombstoneConfigurable); ok {
+			t.setTombstoneRetention(d)
+		}
+	})
+}
+
+// WithEventChannelSize sets specific size of event channels
+//
+// Deprecated: use WithBufferSize instead. The event channel of the given size blocked the registry when it was full,
+// the buffer disconnects the lagging watcher instead, so small sizes suitable for the channel may be too small for it.
+func WithEventChannelSize(l int) Option {
+	return WithBufferSize(l)
+}
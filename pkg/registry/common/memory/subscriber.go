@@ -0,0 +1,132 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errResyncRequired is returned from Find(watch) to the watcher that can't keep up with the events. The watcher should
+// open a new Find(watch) stream to receive the current state of the registry.
+var errResyncRequired = status.Error(codes.Aborted, "watcher is lagging behind: resync is required")
+
+// IsResyncRequired returns true if err means that Find(watch) stream was closed by the memory registry because the
+// watcher was lagging behind, so the watcher has missed some events and should resync
+func IsResyncRequired(err error) bool {
+	s, ok := status.FromError(errors.Cause(err))
+	return ok && s.Code() == codes.Aborted && s.Message() == status.Convert(errResyncRequired).Message()
+}
+
+// subscriber is a bounded non-blocking event queue of a single watcher. Events are coalesced by the entity name so only
+// the latest event per name is kept. If the count of pending names exceeds the limit, subscriber becomes lagging and
+// stops accepting events.
+type subscriber[T any] struct {
+	id       string
+	limit    int
+	signalCh chan struct{}
+
+	mu        sync.Mutex
+	names     []string
+	pending   map[string]T
+	replayed  int
+	revision  uint64
+	lagging   bool
+	coalesced int64
+}
+
+func newSubscriber[T any](id string, limit int) *subscriber[T] {
+	return &subscriber[T]{
+		id:       id,
+		limit:    limit,
+		signalCh: make(chan struct{}, 1),
+		pending:  make(map[string]T),
+	}
+}
+
+// replay adds initial event that doesn't count against the limit
+func (s *subscriber[T]) replay(name string, event T, revision uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pending[name]; !ok {
+		s.replayed++
+	}
+	s.revision = revision
+	s.add(name, event)
+}
+
+// push adds event, it never blocks
+func (s *subscriber[T]) push(name string, event T, revision uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lagging {
+		return
+	}
+	if _, ok := s.pending[name]; !ok && len(s.pending) >= s.limit+s.replayed {
+		// The pending events are dropped as the watcher has to resync anyway, so the revision is not advanced
+		s.lagging = true
+		s.names, s.pending = nil, make(map[string]T)
+		s.signal()
+		return
+	}
+	s.revision = revision
+	s.add(name, event)
+}
+
+func (s *subscriber[T]) add(name string, event T) {
+	if _, ok := s.pending[name]; ok {
+		s.coalesced++
+	} else {
+		s.names = append(s.names, name)
+	}
+	s.pending[name] = event
+	s.signal()
+}
+
+func (s *subscriber[T]) signal() {
+	select {
+	case s.signalCh <- struct{}{}:
+	default:
+	}
+}
+
+// pop returns all pending events in the order of their first appearance and the revision of the latest of them, so
+// all the events up to the revision are delivered once the returned ones are sent
+func (s *subscriber[T]) pop() (events []T, revision uint64, lagging bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, name := range s.names {
+		events = append(events, s.pending[name])
+	}
+	s.names, s.pending, s.replayed = nil, make(map[string]T), 0
+
+	return events, s.revision, s.lagging
+}
+
+// stats returns count of pending events and count of coalesced events
+func (s *subscriber[T]) stats() (lag, coalesced int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.pending)), s.coalesced
+}
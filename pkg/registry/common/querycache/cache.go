@@ -24,25 +24,41 @@ import (
 	"time"
 
 	"github.com/edwarnicke/genericsync"
-	"github.com/networkservicemesh/api/pkg/api/registry"
 
 	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 )
 
-type cache struct {
+// watchRetryInterval is an interval between attempts to reopen the shared Find(watch) stream
+const watchRetryInterval = 100 * time.Millisecond
+
+type options struct {
 	expireTimeout time.Duration
-	entries       genericsync.Map[string, *cacheEntry]
+	sharedWatch   bool
+}
+
+type cache[T any] struct {
+	expireTimeout time.Duration
+	expire        bool
+	entries       genericsync.Map[string, *cacheEntry[T]]
 	clockTime     clock.Clock
+
+	// The shared Find(watch) stream state, Find results are stored only if they are not older than the stream events
+	watchMu   sync.Mutex
+	watching  bool
+	events    uint64
+	clearedAt uint64
+	eventAt   map[string]uint64
 }
 
-func newCache(ctx context.Context, opts ...Option) *cache {
-	c := &cache{
-		expireTimeout: time.Minute,
+func newCache[T any](ctx context.Context, o *options) *cache[T] {
+	c := &cache[T]{
+		expireTimeout: o.expireTimeout,
+		expire:        !o.sharedWatch,
 		clockTime:     clock.FromContext(ctx),
 	}
 
-	for _, opt := range opts {
-		opt(c)
+	if !c.expire {
+		return c
 	}
 
 	ticker := c.clockTime.Ticker(c.expireTimeout)
@@ -53,7 +69,7 @@ func newCache(ctx context.Context, opts ...Option) *cache {
 				ticker.Stop()
 				return
 			case <-ticker.C():
-				c.entries.Range(func(_ string, e *cacheEntry) bool {
+				c.entries.Range(func(_ string, e *cacheEntry[T]) bool {
 					e.lock.Lock()
 					defer e.lock.Unlock()
 
@@ -70,10 +86,10 @@ func newCache(ctx context.Context, opts ...Option) *cache {
 	return c
 }
 
-func (c *cache) LoadOrStore(key string, nse *registry.NetworkServiceEndpoint, cancel context.CancelFunc) (*cacheEntry, bool) {
+func (c *cache[T]) LoadOrStore(key string, value T, cancel context.CancelFunc) (*cacheEntry[T], bool) {
 	var once sync.Once
-	return c.entries.LoadOrStore(key, &cacheEntry{
-		nse:            nse,
+	return c.entries.LoadOrStore(key, &cacheEntry[T]{
+		value:          value,
 		expirationTime: c.clockTime.Now().Add(c.expireTimeout),
 		cleanup: func() {
 			once.Do(func() {
@@ -84,40 +100,120 @@ func (c *cache) LoadOrStore(key string, nse *registry.NetworkServiceEndpoint, ca
 	})
 }
 
-func (c *cache) Load(key string) (*registry.NetworkServiceEndpoint, bool) {
+func (c *cache[T]) Load(key string) (value T, ok bool) {
 	e, ok := c.entries.Load(key)
 	if !ok {
-		return nil, false
+		return value, false
 	}
 
 	e.lock.Lock()
 	defer e.lock.Unlock()
 
-	if c.clockTime.Until(e.expirationTime) < 0 {
+	if c.expire && c.clockTime.Until(e.expirationTime) < 0 {
 		e.cleanup()
-		return nil, false
+		return value, false
 	}
 
 	e.expirationTime = c.clockTime.Now().Add(c.expireTimeout)
 
-	return e.nse, true
+	return e.value, true
+}
+
+// Store stores the entry or updates value of the existing one
+func (c *cache[T]) Store(key string, value T) {
+	if e, loaded := c.LoadOrStore(key, value, func() {}); loaded {
+		e.Update(value)
+	}
+}
+
+// Delete removes the entry
+func (c *cache[T]) Delete(key string) {
+	if e, ok := c.entries.Load(key); ok {
+		e.Cleanup()
+	}
+}
+
+// Clear removes all entries
+func (c *cache[T]) Clear() {
+	c.entries.Range(func(_ string, e *cacheEntry[T]) bool {
+		e.Cleanup()
+		return true
+	})
+}
+
+// WatchStarted marks the shared Find(watch) stream open, so Find results can be stored
+func (c *cache[T]) WatchStarted() {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+
+	c.watching = true
+}
+
+// WatchStopped removes all entries, as the events are missed until the shared Find(watch) stream is reopened
+func (c *cache[T]) WatchStopped() {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+
+	c.watching = false
+	c.events++
+	c.clearedAt = c.events
+	c.eventAt = nil
+	c.Clear()
+}
+
+// Apply applies the event of the shared Find(watch) stream to the entry
+func (c *cache[T]) Apply(key string, value T, deleted bool) {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+
+	c.events++
+	if c.eventAt == nil {
+		c.eventAt = make(map[string]uint64)
+	}
+	c.eventAt[key] = c.events
+
+	if deleted {
+		c.Delete(key)
+		return
+	}
+	c.Store(key, value)
+}
+
+// Position returns the position in the shared Find(watch) stream to pass to StoreFound
+func (c *cache[T]) Position() uint64 {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+
+	return c.events
+}
+
+// StoreFound stores the value found by Find started at the position of the shared Find(watch) stream. The value is not
+// stored if the stream is not open or if it has newer events for the key, so the cache doesn't get deleted entities.
+func (c *cache[T]) StoreFound(key string, value T, position uint64) {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+
+	if !c.watching || c.clearedAt > position || c.eventAt[key] > position {
+		return
+	}
+	c.Store(key, value)
 }
 
-type cacheEntry struct {
-	nse            *registry.NetworkServiceEndpoint
+type cacheEntry[T any] struct {
+	value          T
 	expirationTime time.Time
 	lock           sync.Mutex
 	cleanup        func()
 }
 
-func (e *cacheEntry) Update(nse *registry.NetworkServiceEndpoint) {
+func (e *cacheEntry[T]) Update(value T) {
 	e.lock.Lock()
 	defer e.lock.Unlock()
 
-	e.nse = nse
+	e.value = value
 }
 
-func (e *cacheEntry) Cleanup() {
+func (e *cacheEntry[T]) Cleanup() {
 	e.lock.Lock()
 	defer e.lock.Unlock()
 
@@ -0,0 +1,183 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querycache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/streamchannel"
+)
+
+type queryCacheNSClient struct {
+	ctx         context.Context
+	cache       *cache[*registry.NetworkService]
+	sharedWatch bool
+	watchOnce   sync.Once
+}
+
+// NewNetworkServiceRegistryClient creates new querycache NS registry client that caches all resolved NSs
+func NewNetworkServiceRegistryClient(ctx context.Context, opts ...Option) registry.NetworkServiceRegistryClient {
+	o := newOptions(opts...)
+	return &queryCacheNSClient{
+		ctx:         ctx,
+		cache:       newCache[*registry.NetworkService](ctx, o),
+		sharedWatch: o.sharedWatch,
+	}
+}
+
+func (q *queryCacheNSClient) Register(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*registry.NetworkService, error) {
+	return next.NetworkServiceRegistryClient(ctx).Register(ctx, ns, opts...)
+}
+
+func (q *queryCacheNSClient) Find(ctx context.Context, query *registry.NetworkServiceQuery, opts ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
+	if query.Watch {
+		return next.NetworkServiceRegistryClient(ctx).Find(ctx, query, opts...)
+	}
+
+	if client, ok := q.findInCache(ctx, query.String()); ok {
+		return client, nil
+	}
+
+	var position uint64
+	if q.sharedWatch {
+		q.startSharedWatch(ctx, opts...)
+		position = q.cache.Position()
+	}
+
+	client, err := next.NetworkServiceRegistryClient(ctx).Find(ctx, query, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	nss := registry.ReadNetworkServiceList(client)
+
+	resultCh := make(chan *registry.NetworkServiceResponse, len(nss))
+	for _, ns := range nss {
+		resultCh <- &registry.NetworkServiceResponse{NetworkService: ns}
+		q.storeInCache(ctx, ns.Clone(), position, opts...)
+	}
+	close(resultCh)
+
+	return streamchannel.NewNetworkServiceFindClient(ctx, resultCh), nil
+}
+
+func (q *queryCacheNSClient) findInCache(ctx context.Context, key string) (registry.NetworkServiceRegistry_FindClient, bool) {
+	ns, ok := q.cache.Load(key)
+	if !ok {
+		return nil, false
+	}
+
+	resultCh := make(chan *registry.NetworkServiceResponse, 1)
+	resultCh <- &registry.NetworkServiceResponse{NetworkService: ns.Clone()}
+	close(resultCh)
+
+	return streamchannel.NewNetworkServiceFindClient(ctx, resultCh), true
+}
+
+func (q *queryCacheNSClient) storeInCache(ctx context.Context, ns *registry.NetworkService, position uint64, opts ...grpc.CallOption) {
+	if q.sharedWatch {
+		q.cache.StoreFound(nsNameQuery(ns.Name).String(), ns, position)
+		return
+	}
+
+	nsQuery := nsNameQuery(ns.Name)
+
+	key := nsQuery.String()
+
+	findCtx, cancel := context.WithCancel(q.ctx)
+
+	entry, loaded := q.cache.LoadOrStore(key, ns, cancel)
+	if loaded {
+		cancel()
+		return
+	}
+
+	go func() {
+		defer entry.Cleanup()
+
+		nsQuery.Watch = true
+
+		stream, err := next.NetworkServiceRegistryClient(ctx).Find(findCtx, nsQuery, opts...)
+		if err != nil {
+			return
+		}
+
+		for nsResp, err := stream.Recv(); err == nil; nsResp, err = stream.Recv() {
+			if nsResp.NetworkService.Name != nsQuery.NetworkService.Name {
+				continue
+			}
+			if nsResp.Deleted {
+				break
+			}
+
+			entry.Update(nsResp.NetworkService)
+		}
+	}()
+}
+
+// startSharedWatch starts a single Find(watch) stream for all NSs storing them into the cache. The stream is reopened
+// on failure until the client context is done.
+func (q *queryCacheNSClient) startSharedWatch(ctx context.Context, opts ...grpc.CallOption) {
+	q.watchOnce.Do(func() {
+		nextClient := next.NetworkServiceRegistryClient(ctx)
+		go func() {
+			for ; q.ctx.Err() == nil; q.waitRetry() {
+				stream, err := nextClient.Find(q.ctx, &registry.NetworkServiceQuery{
+					NetworkService: &registry.NetworkService{},
+					Watch:          true,
+				}, opts...)
+				if err != nil {
+					continue
+				}
+				q.cache.WatchStarted()
+
+				for nsResp, err := stream.Recv(); err == nil; nsResp, err = stream.Recv() {
+					key := nsNameQuery(nsResp.GetNetworkService().GetName()).String()
+					q.cache.Apply(key, nsResp.GetNetworkService(), nsResp.GetDeleted())
+				}
+
+				q.cache.WatchStopped()
+			}
+		}()
+	})
+}
+
+func (q *queryCacheNSClient) waitRetry() {
+	select {
+	case <-q.ctx.Done():
+	case <-q.cache.clockTime.After(watchRetryInterval):
+	}
+}
+
+func (q *queryCacheNSClient) Unregister(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.NetworkServiceRegistryClient(ctx).Unregister(ctx, ns, opts...)
+}
+
+func nsNameQuery(name string) *registry.NetworkServiceQuery {
+	return &registry.NetworkServiceQuery{
+		NetworkService: &registry.NetworkService{
+			Name: name,
+		},
+	}
+}
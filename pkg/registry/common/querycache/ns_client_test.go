@@ -0,0 +1,291 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querycache_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/querycache"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/streamchannel"
+)
+
+const (
+	payload1 = "IP"
+	payload2 = "ETHERNET"
+)
+
+func testNSQuery(nsName string) *registry.NetworkServiceQuery {
+	return &registry.NetworkServiceQuery{
+		NetworkService: &registry.NetworkService{
+			Name: nsName,
+		},
+	}
+}
+
+func Test_QueryCacheNSClient_ShouldCacheNSs(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mem := newDeletingNSServer()
+
+	failureClient := new(failureNSClient)
+	c := next.NewNetworkServiceRegistryClient(
+		querycache.NewNetworkServiceRegistryClient(ctx, querycache.WithExpireTimeout(expireTimeout)),
+		failureClient,
+		adapters.NetworkServiceServerToClient(mem),
+	)
+
+	reg, err := mem.Register(ctx, &registry.NetworkService{
+		Name:    name,
+		Payload: payload1,
+	})
+	require.NoError(t, err)
+
+	// 1. Find from memory
+	atomic.StoreInt32(&failureClient.shouldFail, 0)
+
+	stream, err := c.Find(ctx, testNSQuery(name))
+	require.NoError(t, err)
+
+	nsResp, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, payload1, nsResp.NetworkService.Payload)
+
+	// 2. Find from cache
+	atomic.StoreInt32(&failureClient.shouldFail, 1)
+
+	require.Eventually(t, func() bool {
+		if stream, err = c.Find(ctx, testNSQuery(name)); err != nil {
+			return false
+		}
+		if nsResp, err = stream.Recv(); err != nil {
+			return false
+		}
+		return payload1 == nsResp.NetworkService.Payload
+	}, testWait, testTick)
+
+	// 3. Update NS in memory
+	reg.Payload = payload2
+
+	reg, err = mem.Register(ctx, reg)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		if stream, err = c.Find(ctx, testNSQuery(name)); err != nil {
+			return false
+		}
+		if nsResp, err = stream.Recv(); err != nil {
+			return false
+		}
+		return payload2 == nsResp.NetworkService.Payload
+	}, testWait, testTick)
+
+	// 4. Delete NS from memory
+	_, err = mem.Unregister(ctx, reg)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		_, err = c.Find(ctx, testNSQuery(name))
+		return err != nil
+	}, testWait, testTick)
+}
+
+func Test_QueryCacheNSClient_SharedWatch(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mem := newDeletingNSServer()
+
+	failureClient := new(failureNSClient)
+	c := next.NewNetworkServiceRegistryClient(
+		querycache.NewNetworkServiceRegistryClient(ctx, querycache.WithSharedWatch()),
+		failureClient,
+		adapters.NetworkServiceServerToClient(mem),
+	)
+
+	for _, nsName := range []string{"ns-1", "ns-2"} {
+		_, err := mem.Register(ctx, &registry.NetworkService{Name: nsName, Payload: payload1})
+		require.NoError(t, err)
+	}
+
+	// 1. The first Find starts the shared stream
+	stream, err := c.Find(ctx, testNSQuery("ns-1"))
+	require.NoError(t, err)
+	require.Len(t, registry.ReadNetworkServiceList(stream), 1)
+
+	// 2. All NSs are cached from the shared stream
+	atomic.StoreInt32(&failureClient.shouldFail, 1)
+
+	require.Eventually(t, func() bool {
+		if stream, err = c.Find(ctx, testNSQuery("ns-2")); err != nil {
+			return false
+		}
+		return len(registry.ReadNetworkServiceList(stream)) == 1
+	}, testWait, testTick)
+
+	// 3. NS is invalidated by the deleted event
+	_, err = mem.Unregister(ctx, &registry.NetworkService{Name: "ns-2"})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		_, err = c.Find(ctx, testNSQuery("ns-2"))
+		return err != nil
+	}, testWait, testTick)
+
+	stream, err = c.Find(ctx, testNSQuery("ns-1"))
+	require.NoError(t, err)
+	require.Len(t, registry.ReadNetworkServiceList(stream), 1)
+}
+
+func Test_QueryCacheNSClient_SharedWatchStoresFound(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mem := memory.NewNetworkServiceRegistryServer()
+
+	failureClient := new(failureNSClient)
+	c := next.NewNetworkServiceRegistryClient(
+		querycache.NewNetworkServiceRegistryClient(ctx, querycache.WithSharedWatch()),
+		new(silentWatchNSClient),
+		failureClient,
+		adapters.NetworkServiceServerToClient(mem),
+	)
+
+	_, err := mem.Register(ctx, &registry.NetworkService{Name: name, Payload: payload1})
+	require.NoError(t, err)
+
+	// The shared stream sends nothing, so NS is cached from the Find result
+	require.Eventually(t, func() bool {
+		atomic.StoreInt32(&failureClient.shouldFail, 0)
+		if _, err = c.Find(ctx, testNSQuery(name)); err != nil {
+			return false
+		}
+
+		atomic.StoreInt32(&failureClient.shouldFail, 1)
+		stream, err := c.Find(ctx, testNSQuery(name))
+		if err != nil {
+			return false
+		}
+		return len(registry.ReadNetworkServiceList(stream)) == 1
+	}, testWait, testTick)
+}
+
+// silentWatchNSClient returns Find(watch) streams sending nothing until the context is done
+type silentWatchNSClient struct{}
+
+func (c *silentWatchNSClient) Register(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*registry.NetworkService, error) {
+	return next.NetworkServiceRegistryClient(ctx).Register(ctx, ns, opts...)
+}
+
+func (c *silentWatchNSClient) Find(ctx context.Context, query *registry.NetworkServiceQuery, opts ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
+	if !query.Watch {
+		return next.NetworkServiceRegistryClient(ctx).Find(ctx, query, opts...)
+	}
+	ch := make(chan *registry.NetworkServiceResponse)
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return streamchannel.NewNetworkServiceFindClient(ctx, ch), nil
+}
+
+func (c *silentWatchNSClient) Unregister(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.NetworkServiceRegistryClient(ctx).Unregister(ctx, ns, opts...)
+}
+
+// deletingNSServer is a memory registry sending deleted events to the watchers on Unregister as the k8s registry does
+type deletingNSServer struct {
+	registry.NetworkServiceRegistryServer
+
+	mu       sync.Mutex
+	watchers map[registry.NetworkServiceRegistry_FindServer]struct{}
+}
+
+func newDeletingNSServer() *deletingNSServer {
+	return &deletingNSServer{
+		NetworkServiceRegistryServer: memory.NewNetworkServiceRegistryServer(),
+		watchers:                     make(map[registry.NetworkServiceRegistry_FindServer]struct{}),
+	}
+}
+
+func (s *deletingNSServer) Find(query *registry.NetworkServiceQuery, server registry.NetworkServiceRegistry_FindServer) error {
+	if query.Watch {
+		s.mu.Lock()
+		s.watchers[server] = struct{}{}
+		s.mu.Unlock()
+
+		defer func() {
+			s.mu.Lock()
+			delete(s.watchers, server)
+			s.mu.Unlock()
+		}()
+	}
+	return s.NetworkServiceRegistryServer.Find(query, server)
+}
+
+func (s *deletingNSServer) Unregister(ctx context.Context, ns *registry.NetworkService) (*empty.Empty, error) {
+	resp, err := s.NetworkServiceRegistryServer.Unregister(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for watcher := range s.watchers {
+		_ = watcher.Send(&registry.NetworkServiceResponse{NetworkService: ns, Deleted: true})
+	}
+	return resp, nil
+}
+
+type failureNSClient struct {
+	shouldFail int32
+}
+
+func (c *failureNSClient) Register(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*registry.NetworkService, error) {
+	return next.NetworkServiceRegistryClient(ctx).Register(ctx, ns, opts...)
+}
+
+func (c *failureNSClient) Find(ctx context.Context, query *registry.NetworkServiceQuery, opts ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
+	if atomic.LoadInt32(&c.shouldFail) == 1 && !query.Watch {
+		return nil, errors.New("find error")
+	}
+	return next.NetworkServiceRegistryClient(ctx).Find(ctx, query, opts...)
+}
+
+func (c *failureNSClient) Unregister(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.NetworkServiceRegistryClient(ctx).Unregister(ctx, ns, opts...)
+}
@@ -19,6 +19,7 @@ package querycache
 
 import (
 	"context"
+	"sync"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"google.golang.org/grpc"
@@ -30,15 +31,19 @@ import (
 )
 
 type queryCacheNSEClient struct {
-	ctx   context.Context
-	cache *cache
+	ctx         context.Context
+	cache       *cache[*registry.NetworkServiceEndpoint]
+	sharedWatch bool
+	watchOnce   sync.Once
 }
 
 // NewClient creates new querycache NSE registry client that caches all resolved NSEs
 func NewClient(ctx context.Context, opts ...Option) registry.NetworkServiceEndpointRegistryClient {
+	o := newOptions(opts...)
 	return &queryCacheNSEClient{
-		ctx:   ctx,
-		cache: newCache(ctx, opts...),
+		ctx:         ctx,
+		cache:       newCache[*registry.NetworkServiceEndpoint](ctx, o),
+		sharedWatch: o.sharedWatch,
 	}
 }
 
@@ -55,6 +60,12 @@ func (q *queryCacheNSEClient) Find(ctx context.Context, query *registry.NetworkS
 		return client, nil
 	}
 
+	var position uint64
+	if q.sharedWatch {
+		q.startSharedWatch(ctx, opts...)
+		position = q.cache.Position()
+	}
+
 	client, err := next.NetworkServiceEndpointRegistryClient(ctx).Find(ctx, query, opts...)
 	if err != nil {
 		return nil, err
@@ -65,7 +76,7 @@ func (q *queryCacheNSEClient) Find(ctx context.Context, query *registry.NetworkS
 	resultCh := make(chan *registry.NetworkServiceEndpointResponse, len(nses))
 	for _, nse := range nses {
 		resultCh <- &registry.NetworkServiceEndpointResponse{NetworkServiceEndpoint: nse}
-		q.storeInCache(ctx, nse.Clone(), opts...)
+		q.storeInCache(ctx, nse.Clone(), position, opts...)
 	}
 	close(resultCh)
 
@@ -85,13 +96,14 @@ func (q *queryCacheNSEClient) findInCache(ctx context.Context, key string) (regi
 	return streamchannel.NewNetworkServiceEndpointFindClient(ctx, resultCh), true
 }
 
-func (q *queryCacheNSEClient) storeInCache(ctx context.Context, nse *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) {
-	nseQuery := &registry.NetworkServiceEndpointQuery{
-		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{
-			Name: nse.Name,
-		},
+func (q *queryCacheNSEClient) storeInCache(ctx context.Context, nse *registry.NetworkServiceEndpoint, position uint64, opts ...grpc.CallOption) {
+	if q.sharedWatch {
+		q.cache.StoreFound(nseNameQuery(nse.Name).String(), nse, position)
+		return
 	}
 
+	nseQuery := nseNameQuery(nse.Name)
+
 	key := nseQuery.String()
 
 	findCtx, cancel := context.WithCancel(q.ctx)
@@ -125,6 +137,48 @@ func (q *queryCacheNSEClient) storeInCache(ctx context.Context, nse *registry.Ne
 	}()
 }
 
+// startSharedWatch starts a single Find(watch) stream for all NSEs storing them into the cache. The stream is reopened
+// on failure until the client context is done.
+func (q *queryCacheNSEClient) startSharedWatch(ctx context.Context, opts ...grpc.CallOption) {
+	q.watchOnce.Do(func() {
+		nextClient := next.NetworkServiceEndpointRegistryClient(ctx)
+		go func() {
+			for ; q.ctx.Err() == nil; q.waitRetry() {
+				stream, err := nextClient.Find(q.ctx, &registry.NetworkServiceEndpointQuery{
+					NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{},
+					Watch:                  true,
+				}, opts...)
+				if err != nil {
+					continue
+				}
+				q.cache.WatchStarted()
+
+				for nseResp, err := stream.Recv(); err == nil; nseResp, err = stream.Recv() {
+					key := nseNameQuery(nseResp.GetNetworkServiceEndpoint().GetName()).String()
+					q.cache.Apply(key, nseResp.GetNetworkServiceEndpoint(), nseResp.GetDeleted())
+				}
+
+				q.cache.WatchStopped()
+			}
+		}()
+	})
+}
+
+func (q *queryCacheNSEClient) waitRetry() {
+	select {
+	case <-q.ctx.Done():
+	case <-q.cache.clockTime.After(watchRetryInterval):
+	}
+}
+
 func (q *queryCacheNSEClient) Unregister(ctx context.Context, in *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*empty.Empty, error) {
 	return next.NetworkServiceEndpointRegistryClient(ctx).Unregister(ctx, in, opts...)
 }
+
+func nseNameQuery(name string) *registry.NetworkServiceEndpointQuery {
+	return &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{
+			Name: name,
+		},
+	}
+}
@@ -190,6 +190,82 @@ func Test_QueryCacheClient_ShouldCleanUpOnTimeout(t *testing.T) {
 	require.Errorf(t, err, "find error")
 }
 
+func Test_QueryCacheClient_SharedWatch(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.New(ctx)
+	ctx = clock.WithClock(ctx, clockMock)
+
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+
+	failureClient := new(failureNSEClient)
+	c := next.NewNetworkServiceEndpointRegistryClient(
+		querycache.NewClient(ctx, querycache.WithExpireTimeout(expireTimeout), querycache.WithSharedWatch()),
+		failureClient,
+		adapters.NetworkServiceEndpointServerToClient(mem),
+	)
+
+	reg, err := mem.Register(ctx, &registry.NetworkServiceEndpoint{
+		Name: name,
+		Url:  url1,
+	})
+	require.NoError(t, err)
+
+	// 1. Find from memory starts the shared stream
+	stream, err := c.Find(ctx, testNSEQuery(name))
+	require.NoError(t, err)
+
+	nseResp, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, url1, nseResp.NetworkServiceEndpoint.Url)
+
+	// 2. Find from cache, entries don't expire
+	atomic.StoreInt32(&failureClient.shouldFail, 1)
+
+	require.Eventually(t, func() bool {
+		if stream, err = c.Find(ctx, testNSEQuery(name)); err != nil {
+			return false
+		}
+		_, err = stream.Recv()
+		return err == nil
+	}, testWait, testTick)
+
+	clockMock.Add(2 * expireTimeout)
+
+	stream, err = c.Find(ctx, testNSEQuery(name))
+	require.NoError(t, err)
+	nseResp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, url1, nseResp.NetworkServiceEndpoint.Url)
+
+	// 3. Update and delete NSE in memory
+	reg.Url = url2
+
+	reg, err = mem.Register(ctx, reg)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		if stream, err = c.Find(ctx, testNSEQuery(name)); err != nil {
+			return false
+		}
+		if nseResp, err = stream.Recv(); err != nil {
+			return false
+		}
+		return url2 == nseResp.NetworkServiceEndpoint.Url
+	}, testWait, testTick)
+
+	_, err = mem.Unregister(ctx, reg)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		_, err = c.Find(ctx, testNSEQuery(name))
+		return err != nil
+	}, testWait, testTick)
+}
+
 type failureNSEClient struct {
 	shouldFail int32
 }
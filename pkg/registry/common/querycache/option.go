@@ -19,11 +19,30 @@ package querycache
 import "time"
 
 // Option is an option for cache
-type Option func(c *cache)
+type Option func(o *options)
 
 // WithExpireTimeout sets cache expire timeout
 func WithExpireTimeout(expireTimeout time.Duration) Option {
-	return func(c *cache) {
-		c.expireTimeout = expireTimeout
+	return func(o *options) {
+		o.expireTimeout = expireTimeout
 	}
 }
+
+// WithSharedWatch makes cache hold a single Find(watch) stream for all entries instead of a stream per entry. Entries
+// are updated and invalidated by the registry events of this stream and don't expire by timeout, so the registry should
+// send deleted events for the cached entities. If the stream breaks, all entries are invalidated.
+func WithSharedWatch() Option {
+	return func(o *options) {
+		o.sharedWatch = true
+	}
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		expireTimeout: time.Minute,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
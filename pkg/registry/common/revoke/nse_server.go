@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revoke
+
+import (
+	"context"
+
+	"github.com/edwarnicke/genericsync"
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/begin"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/grpcmetadata"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/tombstone"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
+)
+
+type registration struct {
+	tokens  []string
+	factory begin.EventFactory
+}
+
+type revokeNSEServer struct {
+	chainCtx      context.Context
+	list          *revocation.List
+	registrations genericsync.Map[string, *registration]
+}
+
+// NewNetworkServiceEndpointRegistryServer creates a new NetworkServiceEndpointRegistryServer chain element that
+// unregisters the endpoints with tombstone.Revoked reason once the tokens on the left side of their registration path
+// are revoked in the list. It should be placed after the begin chain element.
+func NewNetworkServiceEndpointRegistryServer(ctx context.Context, list *revocation.List) registry.NetworkServiceEndpointRegistryServer {
+	s := &revokeNSEServer{
+		chainCtx: ctx,
+		list:     list,
+	}
+
+	unsubscribe := list.Subscribe(s.revoke)
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+	}()
+
+	return s
+}
+
+func (s *revokeNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
+	if err != nil {
+		return nil, err
+	}
+
+	path := grpcmetadata.PathFromContext(ctx)
+	r := &registration{
+		factory: begin.FromContext(ctx),
+	}
+	if len(path.PathSegments) > 0 {
+		for _, segment := range path.PathSegments[:path.Index+1] {
+			r.tokens = append(r.tokens, segment.Token)
+		}
+	}
+	s.registrations.Store(resp.GetName(), r)
+
+	return resp, nil
+}
+
+func (s *revokeNSEServer) revoke() {
+	s.registrations.Range(func(name string, r *registration) bool {
+		if err := s.list.CheckTokens(r.tokens...); err != nil {
+			log.FromContext(s.chainCtx).WithField("revokeNSEServer", "revoke").Warnf("unregistering %s: %s", name, err.Error())
+			s.registrations.Delete(name)
+			r.factory.Unregister(begin.ExtendContext(tombstone.WithReason(context.Background(), tombstone.Revoked)))
+		}
+		return true
+	})
+}
+
+func (s *revokeNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	return next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, server)
+}
+
+func (s *revokeNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
+	s.registrations.Delete(nse.GetName())
+	return next.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, nse)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revoke_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/begin"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/grpcmetadata"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/revoke"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/tombstone"
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
+)
+
+func withToken(ctx context.Context, t *testing.T, sub string) context.Context {
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": sub}).SignedString([]byte("key"))
+	require.NoError(t, err)
+	return grpcmetadata.PathWithContext(ctx, &grpcmetadata.Path{
+		PathSegments: []*grpcmetadata.PathSegment{{Token: tok}},
+	})
+}
+
+func TestRevokeNSEServer_ShouldUnregisterRevokedNSE(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	list := revocation.NewList(nil)
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+	s := next.NewNetworkServiceEndpointRegistryServer(
+		begin.NewNetworkServiceEndpointRegistryServer(),
+		revoke.NewNetworkServiceEndpointRegistryServer(ctx, list),
+		mem,
+	)
+
+	_, err := s.Register(withToken(ctx, t, "spiffe://test.com/nse-1"), &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+	_, err = s.Register(withToken(ctx, t, "spiffe://test.com/nse-2"), &registry.NetworkServiceEndpoint{Name: "nse-2"})
+	require.NoError(t, err)
+
+	var trailer metadata.MD
+	stream, err := adapters.NetworkServiceEndpointServerToClient(mem).Find(
+		findoptions.WithOptions(ctx, &findoptions.Options{DeleteReasons: true}),
+		&registry.NetworkServiceEndpointQuery{
+			NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+			Watch:                  true,
+		},
+		grpc.Trailer(&trailer),
+	)
+	require.NoError(t, err)
+	ch := registry.ReadNetworkServiceEndpointChannel(stream)
+	require.False(t, (<-ch).GetDeleted())
+	require.False(t, (<-ch).GetDeleted())
+
+	list.Update(&revocation.Entries{SpiffeIDs: []string{"spiffe://test.com/nse-1"}})
+
+	select {
+	case <-ctx.Done():
+		require.FailNow(t, "no deleted event")
+	case resp := <-ch:
+		require.True(t, resp.GetDeleted())
+		require.Equal(t, "nse-1", resp.GetNetworkServiceEndpoint().GetName())
+	}
+
+	// The stream is closed with the reason in the trailer
+	_, ok := <-ch
+	require.False(t, ok)
+	require.Equal(t, tombstone.Revoked, tombstone.TrailerReason(trailer, "nse-1"))
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sweep
+
+import (
+	"context"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/edwarnicke/genericsync"
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/begin"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/tombstone"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type sweeper struct {
+	url    atomic.Pointer[url.URL]
+	cancel context.CancelFunc
+}
+
+type sweepNSEServer struct {
+	ctx context.Context
+	*options
+	sweepers genericsync.Map[string, *sweeper]
+}
+
+// NewNetworkServiceEndpointRegistryServer creates a new NetworkServiceEndpointRegistryServer chain element that
+// periodically probes URLs of the registered endpoints and unregisters the endpoints failed several consecutive probes
+// with tombstone.Unreachable reason. It should be placed after the begin chain element.
+func NewNetworkServiceEndpointRegistryServer(ctx context.Context, opts ...Option) registry.NetworkServiceEndpointRegistryServer {
+	o := &options{
+		interval:         defaultInterval,
+		failureThreshold: defaultFailureThreshold,
+		probeTimeout:     defaultProbeTimeout,
+		prober:           DialProber,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &sweepNSEServer{
+		ctx:     ctx,
+		options: o,
+	}
+}
+
+func (s *sweepNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(resp.GetUrl())
+	if err != nil || resp.GetUrl() == "" {
+		if sw, loaded := s.sweepers.LoadAndDelete(resp.GetName()); loaded {
+			sw.cancel()
+		}
+		return resp, nil
+	}
+
+	// Refresh keeps the probing running, so the consecutive failures are not reset by the endpoint refreshes
+	if sw, ok := s.sweepers.Load(resp.GetName()); ok {
+		sw.url.Store(u)
+		return resp, nil
+	}
+
+	sweepCtx, cancel := context.WithCancel(s.ctx)
+	sw := &sweeper{cancel: cancel}
+	sw.url.Store(u)
+	s.sweepers.Store(resp.GetName(), sw)
+
+	ticker := clock.FromContext(ctx).Ticker(s.interval)
+	go s.sweep(sweepCtx, ctx, begin.FromContext(ctx), sw, ticker)
+
+	return resp, nil
+}
+
+func (s *sweepNSEServer) sweep(sweepCtx, ctx context.Context, factory begin.EventFactory, sw *sweeper, ticker clock.Ticker) {
+	defer ticker.Stop()
+
+	timeClock := clock.FromContext(ctx)
+	logger := log.FromContext(ctx).WithField("sweepNSEServer", "sweep")
+
+	failures := 0
+	for {
+		select {
+		case <-sweepCtx.Done():
+			return
+		case <-ticker.C():
+		}
+
+		probeCtx, cancel := timeClock.WithTimeout(sweepCtx, s.probeTimeout)
+		err := s.prober(probeCtx, sw.url.Load())
+		cancel()
+
+		if err == nil {
+			failures = 0
+			continue
+		}
+		if sweepCtx.Err() != nil {
+			return
+		}
+
+		failures++
+		logger.Warnf("probe %d/%d failed: %s", failures, s.failureThreshold, err.Error())
+		if failures >= s.failureThreshold {
+			factory.Unregister(begin.CancelContext(sweepCtx), begin.ExtendContext(tombstone.WithReason(ctx, tombstone.Unreachable)))
+			return
+		}
+	}
+}
+
+func (s *sweepNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	return next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, server)
+}
+
+func (s *sweepNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
+	if sw, loaded := s.sweepers.LoadAndDelete(nse.GetName()); loaded {
+		sw.cancel()
+	}
+	return next.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, nse)
+}
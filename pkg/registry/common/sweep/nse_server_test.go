@@ -0,0 +1,165 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sweep_test
+
+import (
+	"context"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/begin"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/sweep"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/tombstone"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+)
+
+const (
+	interval = time.Second
+	nseName  = "nse"
+	nseURL   = "tcp://127.0.0.1:5000"
+)
+
+// watch returns the channel of the events requesting the deletion reasons, the trailer is received once it is closed
+func watch(ctx context.Context, t *testing.T, mem registry.NetworkServiceEndpointRegistryServer) (<-chan *registry.NetworkServiceEndpointResponse, *metadata.MD) {
+	trailer := new(metadata.MD)
+	stream, err := adapters.NetworkServiceEndpointServerToClient(mem).Find(
+		findoptions.WithOptions(ctx, &findoptions.Options{DeleteReasons: true}),
+		&registry.NetworkServiceEndpointQuery{
+			NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+			Watch:                  true,
+		},
+		grpc.Trailer(trailer),
+	)
+	require.NoError(t, err)
+	return registry.ReadNetworkServiceEndpointChannel(stream), trailer
+}
+
+func TestSweepNSEServer_ShouldUnregisterUnreachableNSE(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	clockMock := clockmock.New(ctx)
+	ctx = clock.WithClock(ctx, clockMock)
+
+	var probes int32
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+	s := next.NewNetworkServiceEndpointRegistryServer(
+		begin.NewNetworkServiceEndpointRegistryServer(),
+		sweep.NewNetworkServiceEndpointRegistryServer(ctx,
+			sweep.WithInterval(interval),
+			sweep.WithFailureThreshold(3),
+			sweep.WithProber(func(_ context.Context, u *url.URL) error {
+				require.Equal(t, nseURL, u.String())
+				atomic.AddInt32(&probes, 1)
+				return errors.New("connection refused")
+			}),
+		),
+		mem,
+	)
+
+	_, err := s.Register(ctx, &registry.NetworkServiceEndpoint{Name: nseName, Url: nseURL})
+	require.NoError(t, err)
+
+	ch, trailer := watch(ctx, t, mem)
+	require.False(t, (<-ch).GetDeleted())
+
+	for i := int32(1); i < 3; i++ {
+		clockMock.Add(interval)
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&probes) == i }, time.Second, time.Millisecond)
+	}
+	require.Empty(t, ch)
+
+	clockMock.Add(interval)
+	select {
+	case <-ctx.Done():
+		require.FailNow(t, "no deleted event")
+	case resp := <-ch:
+		require.True(t, resp.GetDeleted())
+	}
+	_, ok := <-ch
+	require.False(t, ok)
+	require.Equal(t, tombstone.Unreachable, tombstone.TrailerReason(*trailer, nseName))
+}
+
+func TestSweepNSEServer_ShouldResetFailuresOnSuccess(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	clockMock := clockmock.New(ctx)
+	ctx = clock.WithClock(ctx, clockMock)
+
+	var probes int32
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+	s := next.NewNetworkServiceEndpointRegistryServer(
+		begin.NewNetworkServiceEndpointRegistryServer(),
+		sweep.NewNetworkServiceEndpointRegistryServer(ctx,
+			sweep.WithInterval(interval),
+			sweep.WithFailureThreshold(2),
+			sweep.WithProber(func(_ context.Context, _ *url.URL) error {
+				// Every second probe succeeds
+				if atomic.AddInt32(&probes, 1)%2 == 0 {
+					return nil
+				}
+				return errors.New("connection refused")
+			}),
+		),
+		mem,
+	)
+
+	_, err := s.Register(ctx, &registry.NetworkServiceEndpoint{Name: nseName, Url: nseURL})
+	require.NoError(t, err)
+
+	ch, _ := watch(ctx, t, mem)
+	require.False(t, (<-ch).GetDeleted())
+
+	for i := int32(1); i <= 6; i++ {
+		clockMock.Add(interval)
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&probes) == i }, time.Second, time.Millisecond)
+	}
+	require.Empty(t, ch)
+
+	_, err = s.Unregister(ctx, &registry.NetworkServiceEndpoint{Name: nseName})
+	require.NoError(t, err)
+
+	resp := <-ch
+	require.True(t, resp.GetDeleted())
+
+	// Graceful deletion has no reason, so the stream is not closed
+	_, err = s.Register(ctx, &registry.NetworkServiceEndpoint{Name: nseName, Url: nseURL})
+	require.NoError(t, err)
+	resp = <-ch
+	require.False(t, resp.GetDeleted())
+}
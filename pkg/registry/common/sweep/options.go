@@ -0,0 +1,85 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sweep
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+)
+
+const (
+	defaultInterval         = 10 * time.Second
+	defaultFailureThreshold = 3
+	defaultProbeTimeout     = time.Second
+)
+
+// Prober checks that the endpoint URL is alive
+type Prober func(ctx context.Context, u *url.URL) error
+
+type options struct {
+	interval         time.Duration
+	failureThreshold int
+	probeTimeout     time.Duration
+	prober           Prober
+}
+
+// Option is an option pattern for sweep chain element
+type Option func(o *options)
+
+// WithInterval sets interval between the probes of each endpoint
+func WithInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.interval = interval
+	}
+}
+
+// WithFailureThreshold sets count of consecutive failed probes after which the endpoint is unregistered
+func WithFailureThreshold(threshold int) Option {
+	return func(o *options) {
+		o.failureThreshold = threshold
+	}
+}
+
+// WithProbeTimeout sets timeout of a single probe
+func WithProbeTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.probeTimeout = timeout
+	}
+}
+
+// WithProber sets the prober. By default the endpoint URL is simply dialed.
+func WithProber(prober Prober) Option {
+	return func(o *options) {
+		o.prober = prober
+	}
+}
+
+// DialProber is the default Prober, it checks that the endpoint URL accepts connections
+func DialProber(ctx context.Context, u *url.URL) error {
+	network, addr := grpcutils.TargetToNetAddr(grpcutils.URLToTarget(u))
+	conn, err := new(net.Dialer).DialContext(ctx, network, addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to dial %s", u)
+	}
+	return conn.Close()
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchhub
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
+)
+
+// topicKey returns key of the shared stream for the query and options to open it with. The stream is opened without
+// FromRevision, so every watcher receives the whole current state, as the registry does for the unknown revisions.
+// Revisions are always requested to pass them to the watchers on the stream close.
+func topicKey(query proto.Message, options *findoptions.Options) (string, *findoptions.Options, error) {
+	options = &findoptions.Options{
+		LabelSelector:  options.LabelSelector,
+		WatchRevisions: true,
+	}
+
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(query)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to marshal the query")
+	}
+	return string(b) + "/" + options.LabelSelector, options, nil
+}
+
+// trailers returns the trailers requested by the watcher with grpc.Trailer call options
+func trailers(opts []grpc.CallOption) []*metadata.MD {
+	var result []*metadata.MD
+	for _, opt := range opts {
+		if trailer, ok := opt.(grpc.TrailerCallOption); ok {
+			result = append(result, trailer.TrailerAddr)
+		}
+	}
+	return result
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package watchhub provides registry client chain elements that share one registry watch stream between all watchers
+// of the same query. Watchers joining the already opened stream receive the current state of the matched entities
+// first, so many connections monitoring the same network service or endpoint hold a single registry stream. The shared
+// stream is opened with the chain context, values and call options of the watchers are not passed to it.
+package watchhub
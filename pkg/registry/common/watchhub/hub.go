@@ -0,0 +1,248 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchhub
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// subscriberBufferSize is the count of events a subscriber can fall behind the stream before it is disconnected
+const subscriberBufferSize = 64
+
+type stream[T any] interface {
+	Recv() (T, error)
+}
+
+type subscriber[T any] struct {
+	ch   chan T
+	err  error
+	done chan struct{}
+	// trailers are set to the trailer of the shared stream once it is closed, see grpc.Trailer
+	trailers []*metadata.MD
+}
+
+func newSubscriber[T any](size int, trailers []*metadata.MD) *subscriber[T] {
+	return &subscriber[T]{
+		ch:       make(chan T, size+subscriberBufferSize),
+		done:     make(chan struct{}),
+		trailers: trailers,
+	}
+}
+
+// close is called under the hub lock once the subscriber is removed from its topic
+func (s *subscriber[T]) close(err error) {
+	s.err = err
+	close(s.ch)
+	close(s.done)
+}
+
+func (s *subscriber[T]) recv() (T, error) {
+	item, ok := <-s.ch
+	if !ok {
+		return item, s.err
+	}
+	return item, nil
+}
+
+// setTrailer passes the trailer of the shared stream to the subscriber, so it can resume the watch from the revision
+// received by the stream, see findoptions.Revision
+func (s *subscriber[T]) setTrailer(trailer metadata.MD) {
+	for _, addr := range s.trailers {
+		*addr = trailer.Copy()
+	}
+}
+
+// topic is a shared watch stream. items keeps the current state of the matched entities by name to send it to the
+// subscribers joining the opened stream.
+type topic[T any] struct {
+	// ready is closed once the stream is opened, openErr is set if it has failed
+	ready   chan struct{}
+	openErr error
+	cancel  context.CancelFunc
+	// trailer is set by the stream on close, it is passed to the subscribers
+	trailer metadata.MD
+	// err is set once the stream is closed, subscribers is nil since then
+	err         error
+	subscribers map[*subscriber[T]]struct{}
+	items       map[string]T
+}
+
+type hub[T any] struct {
+	ctx     context.Context
+	name    func(T) string
+	deleted func(T) bool
+	clone   func(T) T
+
+	mu     sync.Mutex
+	topics map[string]*topic[T]
+}
+
+func newHub[T any](ctx context.Context, name func(T) string, deleted func(T) bool, clone func(T) T) *hub[T] {
+	return &hub[T]{
+		ctx:     ctx,
+		name:    name,
+		deleted: deleted,
+		clone:   clone,
+		topics:  make(map[string]*topic[T]),
+	}
+}
+
+// subscribe joins the topic or opens a new one. The stream is opened with the hub context, so it doesn't carry values
+// of any subscriber. Subscription ends on ctx done or on the stream error, which is passed to all subscribers of the
+// topic along with the stream trailer.
+func (h *hub[T]) subscribe(ctx context.Context, key string, trailers []*metadata.MD, open func(ctx context.Context, opts ...grpc.CallOption) (stream[T], error)) (*subscriber[T], error) {
+	t, err := h.topic(ctx, key, open)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := newSubscriber[T](len(t.items), trailers)
+	if t.subscribers == nil {
+		sub.setTrailer(t.trailer)
+		sub.close(t.err)
+		return sub, nil
+	}
+	for _, item := range t.items {
+		sub.ch <- h.clone(item)
+	}
+	t.subscribers[sub] = struct{}{}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			h.unsubscribe(key, t, sub, errors.Wrap(ctx.Err(), "watch is canceled"))
+		case <-sub.done:
+		}
+	}()
+
+	return sub, nil
+}
+
+// topic returns the opened topic for the key. The stream is opened out of the hub lock, the subscribers of the topic
+// being opened wait for it.
+func (h *hub[T]) topic(ctx context.Context, key string, open func(ctx context.Context, opts ...grpc.CallOption) (stream[T], error)) (*topic[T], error) {
+	h.mu.Lock()
+	t, ok := h.topics[key]
+	if ok {
+		h.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "watch is canceled")
+		case <-t.ready:
+		}
+		if t.openErr != nil {
+			return nil, t.openErr
+		}
+		return t, nil
+	}
+
+	streamCtx, cancel := context.WithCancel(h.ctx)
+	t = &topic[T]{
+		ready:       make(chan struct{}),
+		cancel:      cancel,
+		subscribers: make(map[*subscriber[T]]struct{}),
+		items:       make(map[string]T),
+	}
+	h.topics[key] = t
+	h.mu.Unlock()
+
+	s, err := open(streamCtx, grpc.Trailer(&t.trailer))
+	if err != nil {
+		h.mu.Lock()
+		if h.topics[key] == t {
+			delete(h.topics, key)
+		}
+		h.mu.Unlock()
+
+		cancel()
+		t.openErr = err
+		close(t.ready)
+		return nil, err
+	}
+	close(t.ready)
+
+	go h.receive(key, t, s)
+
+	return t, nil
+}
+
+func (h *hub[T]) unsubscribe(key string, t *topic[T], sub *subscriber[T], err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := t.subscribers[sub]; !ok {
+		return
+	}
+	delete(t.subscribers, sub)
+	sub.close(err)
+
+	if len(t.subscribers) == 0 && h.topics[key] == t {
+		delete(h.topics, key)
+		t.cancel()
+	}
+}
+
+func (h *hub[T]) receive(key string, t *topic[T], s stream[T]) {
+	for {
+		item, err := s.Recv()
+
+		h.mu.Lock()
+		if err != nil {
+			if h.topics[key] == t {
+				delete(h.topics, key)
+			}
+			for sub := range t.subscribers {
+				sub.setTrailer(t.trailer)
+				sub.close(err)
+			}
+			t.err = err
+			t.subscribers = nil
+			h.mu.Unlock()
+
+			t.cancel()
+			return
+		}
+
+		if h.deleted(item) {
+			delete(t.items, h.name(item))
+		} else {
+			t.items[h.name(item)] = item
+		}
+		for sub := range t.subscribers {
+			select {
+			case sub.ch <- h.clone(item):
+			default:
+				delete(t.subscribers, sub)
+				sub.close(errors.New("watcher is too slow"))
+			}
+		}
+		if len(t.subscribers) == 0 && h.topics[key] == t {
+			delete(h.topics, key)
+			t.cancel()
+		}
+		h.mu.Unlock()
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchhub
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
+)
+
+type watchHubNSClient struct {
+	hub *hub[*registry.NetworkServiceResponse]
+}
+
+// NewNetworkServiceRegistryClient creates a new NetworkServiceRegistryClient chain element that shares one watch
+// stream between all watch Finds with the same query. Stream error is passed to all its watchers along with the stream
+// trailer, so they can resume from the revision. Resumed watches are shared too, FromRevision is ignored as the joining
+// watcher receives the whole current state, so the deletions it has missed are not replayed. Call options of the
+// watchers other than grpc.Trailer are dropped.
+//   - ctx - context for the lifecycle management of the shared streams
+func NewNetworkServiceRegistryClient(ctx context.Context) registry.NetworkServiceRegistryClient {
+	return &watchHubNSClient{
+		hub: newHub(ctx,
+			func(resp *registry.NetworkServiceResponse) string { return resp.GetNetworkService().GetName() },
+			func(resp *registry.NetworkServiceResponse) bool { return resp.GetDeleted() },
+			func(resp *registry.NetworkServiceResponse) *registry.NetworkServiceResponse {
+				return proto.Clone(resp).(*registry.NetworkServiceResponse)
+			},
+		),
+	}
+}
+
+func (c *watchHubNSClient) Register(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*registry.NetworkService, error) {
+	return next.NetworkServiceRegistryClient(ctx).Register(ctx, ns, opts...)
+}
+
+func (c *watchHubNSClient) Find(ctx context.Context, query *registry.NetworkServiceQuery, opts ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
+	if !query.GetWatch() {
+		return next.NetworkServiceRegistryClient(ctx).Find(ctx, query, opts...)
+	}
+
+	options, err := findoptions.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, options, err := topicKey(query, options)
+	if err != nil {
+		return nil, err
+	}
+
+	nextClient := next.NetworkServiceRegistryClient(ctx)
+	sub, err := c.hub.subscribe(ctx, key, trailers(opts), func(streamCtx context.Context, streamOpts ...grpc.CallOption) (stream[*registry.NetworkServiceResponse], error) {
+		return nextClient.Find(findoptions.WithOptions(streamCtx, options), proto.Clone(query).(*registry.NetworkServiceQuery), streamOpts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &watchHubNSFindClient{ctx: ctx, sub: sub}, nil
+}
+
+func (c *watchHubNSClient) Unregister(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.NetworkServiceRegistryClient(ctx).Unregister(ctx, ns, opts...)
+}
+
+type watchHubNSFindClient struct {
+	grpc.ClientStream
+	ctx context.Context
+	sub *subscriber[*registry.NetworkServiceResponse]
+}
+
+func (c *watchHubNSFindClient) Recv() (*registry.NetworkServiceResponse, error) {
+	return c.sub.recv()
+}
+
+func (c *watchHubNSFindClient) Context() context.Context {
+	return c.ctx
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchhub_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/watchhub"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/count"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
+)
+
+func nsWatchQuery(name string) *registry.NetworkServiceQuery {
+	return &registry.NetworkServiceQuery{
+		NetworkService: &registry.NetworkService{Name: name},
+		Watch:          true,
+	}
+}
+
+func TestWatchHubNSClient_SharesStream(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	mem := memory.NewNetworkServiceRegistryServer()
+	_, err := mem.Register(ctx, &registry.NetworkService{Name: "ns-1"})
+	require.NoError(t, err)
+
+	counter := new(count.CallCounter)
+	client := chain.NewNetworkServiceRegistryClient(
+		watchhub.NewNetworkServiceRegistryClient(ctx),
+		count.NewNetworkServiceRegistryClient(counter),
+		adapters.NetworkServiceServerToClient(mem),
+	)
+
+	var streams []registry.NetworkServiceRegistry_FindClient
+	for i := 0; i < 2; i++ {
+		stream, findErr := client.Find(ctx, nsWatchQuery("ns-1"))
+		require.NoError(t, findErr)
+		resp, recvErr := stream.Recv()
+		require.NoError(t, recvErr)
+		require.Equal(t, "ns-1", resp.GetNetworkService().GetName())
+		streams = append(streams, stream)
+	}
+	require.Equal(t, 1, counter.Finds())
+
+	_, err = mem.Register(ctx, &registry.NetworkService{Name: "ns-1", Payload: "IP"})
+	require.NoError(t, err)
+	for _, stream := range streams {
+		resp, recvErr := stream.Recv()
+		require.NoError(t, recvErr)
+		require.Equal(t, "IP", resp.GetNetworkService().GetPayload())
+	}
+
+	// Watches resumed from the revision are shared too, they receive the current state
+	stream, err := client.Find(findoptions.WithOptions(ctx, &findoptions.Options{FromRevision: 1}), nsWatchQuery("ns-1"))
+	require.NoError(t, err)
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "IP", resp.GetNetworkService().GetPayload())
+	require.Equal(t, 1, counter.Finds())
+}
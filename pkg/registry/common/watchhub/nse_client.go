@@ -0,0 +1,106 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchhub
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
+)
+
+type watchHubNSEClient struct {
+	hub *hub[*registry.NetworkServiceEndpointResponse]
+}
+
+// NewNetworkServiceEndpointRegistryClient creates a new NetworkServiceEndpointRegistryClient chain element that shares
+// one watch stream between all watch Finds with the same query. Stream error is passed to all its watchers along with
+// the stream trailer, so they can resume from the revision. Resumed watches are shared too, FromRevision is ignored as
+// the joining watcher receives the whole current state, so the deletions it has missed are not replayed. Call options
+// of the watchers other than grpc.Trailer are dropped.
+//   - ctx - context for the lifecycle management of the shared streams
+func NewNetworkServiceEndpointRegistryClient(ctx context.Context) registry.NetworkServiceEndpointRegistryClient {
+	return &watchHubNSEClient{
+		hub: newHub(ctx,
+			func(resp *registry.NetworkServiceEndpointResponse) string {
+				return resp.GetNetworkServiceEndpoint().GetName()
+			},
+			func(resp *registry.NetworkServiceEndpointResponse) bool { return resp.GetDeleted() },
+			func(resp *registry.NetworkServiceEndpointResponse) *registry.NetworkServiceEndpointResponse {
+				return proto.Clone(resp).(*registry.NetworkServiceEndpointResponse)
+			},
+		),
+	}
+}
+
+func (c *watchHubNSEClient) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*registry.NetworkServiceEndpoint, error) {
+	return next.NetworkServiceEndpointRegistryClient(ctx).Register(ctx, nse, opts...)
+}
+
+func (c *watchHubNSEClient) Find(ctx context.Context, query *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	if !query.GetWatch() {
+		return next.NetworkServiceEndpointRegistryClient(ctx).Find(ctx, query, opts...)
+	}
+
+	options, err := findoptions.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Watchers requesting the deletion reasons are not shared, as their streams are closed on the deletions with the
+	// reasons and are resumed from the revision
+	if options.DeleteReasons {
+		return next.NetworkServiceEndpointRegistryClient(ctx).Find(ctx, query, opts...)
+	}
+
+	key, options, err := topicKey(query, options)
+	if err != nil {
+		return nil, err
+	}
+
+	nextClient := next.NetworkServiceEndpointRegistryClient(ctx)
+	sub, err := c.hub.subscribe(ctx, key, trailers(opts), func(streamCtx context.Context, streamOpts ...grpc.CallOption) (stream[*registry.NetworkServiceEndpointResponse], error) {
+		return nextClient.Find(findoptions.WithOptions(streamCtx, options), proto.Clone(query).(*registry.NetworkServiceEndpointQuery), streamOpts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &watchHubNSEFindClient{ctx: ctx, sub: sub}, nil
+}
+
+func (c *watchHubNSEClient) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.NetworkServiceEndpointRegistryClient(ctx).Unregister(ctx, nse, opts...)
+}
+
+type watchHubNSEFindClient struct {
+	grpc.ClientStream
+	ctx context.Context
+	sub *subscriber[*registry.NetworkServiceEndpointResponse]
+}
+
+func (c *watchHubNSEFindClient) Recv() (*registry.NetworkServiceEndpointResponse, error) {
+	return c.sub.recv()
+}
+
+func (c *watchHubNSEFindClient) Context() context.Context {
+	return c.ctx
+}
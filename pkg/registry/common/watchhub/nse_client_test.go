@@ -0,0 +1,303 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchhub_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/watchhub"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/streamchannel"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/count"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
+)
+
+func watchQuery(name string) *registry.NetworkServiceEndpointQuery {
+	return &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{Name: name},
+		Watch:                  true,
+	}
+}
+
+func recvName(t *testing.T, stream registry.NetworkServiceEndpointRegistry_FindClient) string {
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	return resp.GetNetworkServiceEndpoint().GetName()
+}
+
+func TestWatchHubNSEClient_SharesStream(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+	_, err := mem.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+
+	counter := new(count.CallCounter)
+	client := chain.NewNetworkServiceEndpointRegistryClient(
+		watchhub.NewNetworkServiceEndpointRegistryClient(ctx),
+		count.NewNetworkServiceEndpointRegistryClient(counter),
+		adapters.NetworkServiceEndpointServerToClient(mem),
+	)
+
+	watchCtx1, cancelWatch1 := context.WithCancel(ctx)
+	stream1, err := client.Find(watchCtx1, watchQuery("nse-1"))
+	require.NoError(t, err)
+	require.Equal(t, "nse-1", recvName(t, stream1))
+
+	watchCtx2, cancelWatch2 := context.WithCancel(ctx)
+	stream2, err := client.Find(watchCtx2, watchQuery("nse-1"))
+	require.NoError(t, err)
+	require.Equal(t, "nse-1", recvName(t, stream2))
+	require.Equal(t, 1, counter.Finds())
+
+	_, err = mem.Unregister(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+	for _, stream := range []registry.NetworkServiceEndpointRegistry_FindClient{stream1, stream2} {
+		resp, recvErr := stream.Recv()
+		require.NoError(t, recvErr)
+		require.True(t, resp.GetDeleted())
+	}
+
+	// Non-watch Finds and other queries are not shared
+	_, err = client.Find(ctx, &registry.NetworkServiceEndpointQuery{NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{Name: "nse-1"}})
+	require.NoError(t, err)
+	require.Equal(t, 2, counter.Finds())
+
+	// The stream is closed with the last watcher and reopened by the next one
+	cancelWatch1()
+	_, err = stream1.Recv()
+	require.Error(t, err)
+	cancelWatch2()
+	_, err = stream2.Recv()
+	require.Error(t, err)
+
+	_, err = mem.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+	stream3, err := client.Find(ctx, watchQuery("nse-1"))
+	require.NoError(t, err)
+	require.Equal(t, "nse-1", recvName(t, stream3))
+	require.Equal(t, 3, counter.Finds())
+}
+
+type channelNSEClient struct {
+	ch      chan *registry.NetworkServiceEndpointResponse
+	trailer metadata.MD
+}
+
+func (c *channelNSEClient) Register(_ context.Context, nse *registry.NetworkServiceEndpoint, _ ...grpc.CallOption) (*registry.NetworkServiceEndpoint, error) {
+	return nse, nil
+}
+
+func (c *channelNSEClient) Find(ctx context.Context, _ *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	for _, opt := range opts {
+		if trailer, ok := opt.(grpc.TrailerCallOption); ok {
+			*trailer.TrailerAddr = c.trailer
+		}
+	}
+	return streamchannel.NewNetworkServiceEndpointFindClient(ctx, c.ch), nil
+}
+
+func (c *channelNSEClient) Unregister(_ context.Context, _ *registry.NetworkServiceEndpoint, _ ...grpc.CallOption) (*empty.Empty, error) {
+	return new(empty.Empty), nil
+}
+
+func TestWatchHubNSEClient_StreamError(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	upstream := &channelNSEClient{
+		ch:      make(chan *registry.NetworkServiceEndpointResponse, 10),
+		trailer: metadata.Pairs("nsm-find-revision", "5"),
+	}
+	client := chain.NewNetworkServiceEndpointRegistryClient(
+		watchhub.NewNetworkServiceEndpointRegistryClient(ctx),
+		upstream,
+	)
+
+	upstream.ch <- &registry.NetworkServiceEndpointResponse{NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{Name: "nse-1"}}
+	upstream.ch <- &registry.NetworkServiceEndpointResponse{NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{Name: "nse-10"}}
+
+	var trailer metadata.MD
+	stream1, err := client.Find(ctx, watchQuery("nse-1"), grpc.Trailer(&trailer))
+	require.NoError(t, err)
+	require.Equal(t, "nse-1", recvName(t, stream1))
+	require.Equal(t, "nse-10", recvName(t, stream1))
+
+	// Late watcher receives the current state
+	stream2, err := client.Find(ctx, watchQuery("nse-1"))
+	require.NoError(t, err)
+	names := []string{recvName(t, stream2), recvName(t, stream2)}
+	require.ElementsMatch(t, []string{"nse-1", "nse-10"}, names)
+
+	// Stream error is passed to all watchers with the revision to resume from
+	close(upstream.ch)
+	_, err = stream1.Recv()
+	require.Error(t, err)
+	_, err = stream2.Recv()
+	require.Error(t, err)
+	require.Equal(t, uint64(5), findoptions.Revision(trailer))
+}
+
+// reconnectingNSEClient opens a new stream on every Find, the streams are closed by closing their channels
+type reconnectingNSEClient struct {
+	streams chan chan *registry.NetworkServiceEndpointResponse
+}
+
+func (c *reconnectingNSEClient) Register(_ context.Context, nse *registry.NetworkServiceEndpoint, _ ...grpc.CallOption) (*registry.NetworkServiceEndpoint, error) {
+	return nse, nil
+}
+
+func (c *reconnectingNSEClient) Find(ctx context.Context, _ *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	for _, opt := range opts {
+		if trailer, ok := opt.(grpc.TrailerCallOption); ok {
+			*trailer.TrailerAddr = metadata.Pairs("nsm-find-revision", "5")
+		}
+	}
+	ch := make(chan *registry.NetworkServiceEndpointResponse, 1)
+	ch <- &registry.NetworkServiceEndpointResponse{NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{Name: "nse-1"}}
+	c.streams <- ch
+	return streamchannel.NewNetworkServiceEndpointFindClient(ctx, ch), nil
+}
+
+func (c *reconnectingNSEClient) Unregister(_ context.Context, _ *registry.NetworkServiceEndpoint, _ ...grpc.CallOption) (*empty.Empty, error) {
+	return new(empty.Empty), nil
+}
+
+func TestWatchHubNSEClient_SharesResumedStream(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	const watchers = 5
+
+	counter := new(count.CallCounter)
+	upstream := &reconnectingNSEClient{streams: make(chan chan *registry.NetworkServiceEndpointResponse, 2)}
+	client := chain.NewNetworkServiceEndpointRegistryClient(
+		watchhub.NewNetworkServiceEndpointRegistryClient(ctx),
+		count.NewNetworkServiceEndpointRegistryClient(counter),
+		upstream,
+	)
+
+	trailers := make([]metadata.MD, watchers)
+	streams := make([]registry.NetworkServiceEndpointRegistry_FindClient, watchers)
+	for i := 0; i < watchers; i++ {
+		stream, err := client.Find(ctx, watchQuery("nse-1"), grpc.Trailer(&trailers[i]))
+		require.NoError(t, err)
+		require.Equal(t, "nse-1", recvName(t, stream))
+		streams[i] = stream
+	}
+	require.Equal(t, 1, counter.Finds())
+
+	// All watchers reconnect after the stream close, the resumed watches share one stream
+	close(<-upstream.streams)
+	for i := 0; i < watchers; i++ {
+		_, err := streams[i].Recv()
+		require.Error(t, err)
+		revision := findoptions.Revision(trailers[i])
+		require.Equal(t, uint64(5), revision)
+
+		streams[i], err = client.Find(findoptions.WithOptions(ctx, &findoptions.Options{FromRevision: revision}), watchQuery("nse-1"))
+		require.NoError(t, err)
+		require.Equal(t, "nse-1", recvName(t, streams[i]))
+	}
+	require.Equal(t, 2, counter.Finds())
+
+	close(<-upstream.streams)
+	for _, stream := range streams {
+		_, err := stream.Recv()
+		require.Error(t, err)
+	}
+}
+
+type watcherKey struct{}
+
+type blockingNSEClient struct {
+	ch      chan *registry.NetworkServiceEndpointResponse
+	release chan struct{}
+	ctxs    chan context.Context
+}
+
+func (c *blockingNSEClient) Register(_ context.Context, nse *registry.NetworkServiceEndpoint, _ ...grpc.CallOption) (*registry.NetworkServiceEndpoint, error) {
+	return nse, nil
+}
+
+func (c *blockingNSEClient) Find(ctx context.Context, query *registry.NetworkServiceEndpointQuery, _ ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	c.ctxs <- ctx
+	if query.GetNetworkServiceEndpoint().GetName() == "nse-1" {
+		<-c.release
+	}
+	return streamchannel.NewNetworkServiceEndpointFindClient(ctx, c.ch), nil
+}
+
+func (c *blockingNSEClient) Unregister(_ context.Context, _ *registry.NetworkServiceEndpoint, _ ...grpc.CallOption) (*empty.Empty, error) {
+	return new(empty.Empty), nil
+}
+
+func TestWatchHubNSEClient_OpensStreamOutOfLock(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	upstream := &blockingNSEClient{
+		ch:      make(chan *registry.NetworkServiceEndpointResponse),
+		release: make(chan struct{}),
+		ctxs:    make(chan context.Context, 2),
+	}
+	defer close(upstream.ch)
+	client := chain.NewNetworkServiceEndpointRegistryClient(
+		watchhub.NewNetworkServiceEndpointRegistryClient(ctx),
+		upstream,
+	)
+
+	watchCtx := context.WithValue(ctx, watcherKey{}, "watcher-1")
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.Find(watchCtx, watchQuery("nse-1"))
+		errCh <- err
+	}()
+	streamCtx := <-upstream.ctxs
+
+	// Another query is not blocked by the stream being opened
+	_, err := client.Find(watchCtx, watchQuery("nse-2"))
+	require.NoError(t, err)
+
+	close(upstream.release)
+	require.NoError(t, <-errCh)
+
+	// The shared streams don't carry values of the watchers
+	require.Nil(t, streamCtx.Value(watcherKey{}))
+	require.Nil(t, (<-upstream.ctxs).Value(watcherKey{}))
+}
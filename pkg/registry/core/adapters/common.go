@@ -16,4 +16,62 @@
 
 package adapters
 
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
 const channelSize = 100
+
+// trailerStream collects the trailer set by the in-process Find server, see withTrailers
+type trailerStream struct {
+	mu      sync.Mutex
+	trailer metadata.MD
+}
+
+func (s *trailerStream) Method() string {
+	return ""
+}
+
+func (s *trailerStream) SetHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *trailerStream) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *trailerStream) SetTrailer(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+// withTrailers returns the context for the in-process Find server receiving the trailer the way grpc server does, the
+// returned func passes the trailer to the grpc.Trailer call options once the server Find is done
+func withTrailers(ctx context.Context, opts []grpc.CallOption) (context.Context, func()) {
+	var trailers []*metadata.MD
+	for _, opt := range opts {
+		if trailer, ok := opt.(grpc.TrailerCallOption); ok {
+			trailers = append(trailers, trailer.TrailerAddr)
+		}
+	}
+	if len(trailers) == 0 {
+		return ctx, func() {}
+	}
+
+	stream := new(trailerStream)
+	return grpc.NewContextWithServerTransportStream(ctx, stream), func() {
+		stream.mu.Lock()
+		defer stream.mu.Unlock()
+
+		for _, trailer := range trailers {
+			*trailer = stream.trailer.Copy()
+		}
+	}
+}
@@ -33,7 +33,7 @@ func (c *callNextNSServer) Register(ctx context.Context, in *registry.NetworkSer
 }
 
 func (c *callNextNSServer) Find(ctx context.Context, in *registry.NetworkServiceQuery, opts ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
-	return nsFindServerToClient(ctx, c.server, in)
+	return nsFindServerToClient(ctx, c.server, in, opts...)
 }
 
 func (c *callNextNSServer) Unregister(ctx context.Context, in *registry.NetworkService, opts ...grpc.CallOption) (*empty.Empty, error) {
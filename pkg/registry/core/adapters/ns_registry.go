@@ -79,12 +79,12 @@ func (n *networkServiceRegistryClient) Register(ctx context.Context, in *registr
 	).Register(ctx, in)
 }
 
-func (n *networkServiceRegistryClient) Find(ctx context.Context, in *registry.NetworkServiceQuery, _ ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
+func (n *networkServiceRegistryClient) Find(ctx context.Context, in *registry.NetworkServiceQuery, opts ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
 	s := next.NewNetworkServiceRegistryServer(
 		n.server,
 		&callNextNSClient{client: next.NetworkServiceRegistryClient(ctx)},
 	)
-	return nsFindServerToClient(ctx, s, in)
+	return nsFindServerToClient(ctx, s, in, opts...)
 }
 
 func (n *networkServiceRegistryClient) Unregister(ctx context.Context, in *registry.NetworkService, _ ...grpc.CallOption) (*empty.Empty, error) {
@@ -121,17 +121,21 @@ func nsFindClientToServer(client registry.NetworkServiceRegistry_FindClient, ser
 	return nil
 }
 
-func nsFindServerToClient(ctx context.Context, server registry.NetworkServiceRegistryServer, in *registry.NetworkServiceQuery) (registry.NetworkServiceRegistry_FindClient, error) {
+func nsFindServerToClient(ctx context.Context, server registry.NetworkServiceRegistryServer, in *registry.NetworkServiceQuery, opts ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
 	ch := make(chan *registry.NetworkServiceResponse, channelSize)
-	s := streamchannel.NewNetworkServiceFindServer(ctx, ch)
+	serverCtx, setTrailers := withTrailers(ctx, opts)
+	s := streamchannel.NewNetworkServiceFindServer(serverCtx, ch)
 	if in != nil && in.Watch {
 		go func() {
 			defer close(ch)
 			_ = server.Find(in, s)
+			setTrailers()
 		}()
 	} else {
 		defer close(ch)
-		if err := server.Find(in, s); err != nil {
+		err := server.Find(in, s)
+		setTrailers()
+		if err != nil {
 			return nil, errors.Wrap(err, "NetworkServiceRegistry find server failed to find a query")
 		}
 	}
@@ -32,8 +32,8 @@ func (c *callNextNSEServer) Register(ctx context.Context, in *registry.NetworkSe
 	return c.server.Register(ctx, in)
 }
 
-func (c *callNextNSEServer) Find(ctx context.Context, in *registry.NetworkServiceEndpointQuery, _ ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
-	return nseFindServerToClient(ctx, c.server, in)
+func (c *callNextNSEServer) Find(ctx context.Context, in *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	return nseFindServerToClient(ctx, c.server, in, opts...)
 }
 
 func (c *callNextNSEServer) Unregister(ctx context.Context, in *registry.NetworkServiceEndpoint, _ ...grpc.CallOption) (*empty.Empty, error) {
@@ -84,7 +84,7 @@ func (n *networkServiceEndpointRegistryClient) Find(ctx context.Context, in *reg
 		n.server,
 		&callNextNSEClient{client: next.NetworkServiceEndpointRegistryClient(ctx)},
 	)
-	return nseFindServerToClient(ctx, s, in)
+	return nseFindServerToClient(ctx, s, in, opts...)
 }
 
 func (n *networkServiceEndpointRegistryClient) Unregister(ctx context.Context, in *registry.NetworkServiceEndpoint, _ ...grpc.CallOption) (*empty.Empty, error) {
@@ -121,17 +121,21 @@ func nseFindClientToServer(client registry.NetworkServiceEndpointRegistry_FindCl
 	return nil
 }
 
-func nseFindServerToClient(ctx context.Context, server registry.NetworkServiceEndpointRegistryServer, in *registry.NetworkServiceEndpointQuery) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+func nseFindServerToClient(ctx context.Context, server registry.NetworkServiceEndpointRegistryServer, in *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
 	ch := make(chan *registry.NetworkServiceEndpointResponse, channelSize)
-	s := streamchannel.NewNetworkServiceEndpointFindServer(ctx, ch)
+	serverCtx, setTrailers := withTrailers(ctx, opts)
+	s := streamchannel.NewNetworkServiceEndpointFindServer(serverCtx, ch)
 	if in != nil && in.Watch {
 		go func() {
 			defer close(ch)
 			_ = server.Find(in, s)
+			setTrailers()
 		}()
 	} else {
 		defer close(ch)
-		if err := server.Find(in, s); err != nil {
+		err := server.Find(in, s)
+		setTrailers()
+		if err != nil {
 			return nil, errors.Wrap(err, "NetworkServiceEndpointRegistry find server failed to find a query")
 		}
 	}
@@ -22,6 +22,8 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/networkservicemesh/api/pkg/api/registry"
 	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/profile"
 )
 
 // NetworkServiceRegistryClientWrapper - function that wraps a registry server
@@ -31,7 +33,9 @@ type NetworkServiceRegistryClientWrapper func(client registry.NetworkServiceRegi
 type NetworkServiceRegistryClientChainer func(clients ...registry.NetworkServiceRegistryClient) registry.NetworkServiceRegistryClient
 
 type nextNetworkServiceRegistryClient struct {
-	clients    []registry.NetworkServiceRegistryClient
+	clients []registry.NetworkServiceRegistryClient
+	// elements are not wrapped, they only name the elements in the profiles and are skipped by introspect
+	elements   []registry.NetworkServiceRegistryClient `introspect:"-"`
 	index      int
 	nextParent registry.NetworkServiceRegistryClient
 }
@@ -41,6 +45,7 @@ func NewWrappedNetworkServiceRegistryClient(wrapper NetworkServiceRegistryClient
 	rv := &nextNetworkServiceRegistryClient{clients: make([]registry.NetworkServiceRegistryClient, 0, len(clients))}
 	for _, c := range clients {
 		rv.clients = append(rv.clients, wrapper(c))
+		rv.elements = append(rv.elements, c)
 	}
 	return rv
 }
@@ -54,16 +59,26 @@ func NewNetworkServiceRegistryClient(clients ...registry.NetworkServiceRegistryC
 
 func (n *nextNetworkServiceRegistryClient) Register(ctx context.Context, in *registry.NetworkService, opts ...grpc.CallOption) (*registry.NetworkService, error) {
 	client, ctx := n.getClientAndContext(ctx)
+	ctx, done := n.measure(ctx, "Register")
+	defer done()
 	return client.Register(ctx, in, opts...)
 }
 
 func (n *nextNetworkServiceRegistryClient) Find(ctx context.Context, in *registry.NetworkServiceQuery, opts ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
 	client, ctx := n.getClientAndContext(ctx)
+	// Watch Finds last as long as their streams, so only the lookups are measured
+	if !in.GetWatch() {
+		var done func()
+		ctx, done = n.measure(ctx, "Find")
+		defer done()
+	}
 	return client.Find(ctx, in, opts...)
 }
 
 func (n *nextNetworkServiceRegistryClient) Unregister(ctx context.Context, in *registry.NetworkService, opts ...grpc.CallOption) (*empty.Empty, error) {
 	client, ctx := n.getClientAndContext(ctx)
+	ctx, done := n.measure(ctx, "Unregister")
+	defer done()
 	return client.Unregister(ctx, in, opts...)
 }
 
@@ -76,7 +91,19 @@ func (n *nextNetworkServiceRegistryClient) getClientAndContext(ctx context.Conte
 		}
 	}
 	if n.index+1 < len(n.clients) {
-		return n.clients[n.index], withNextNSRegistryClient(ctx, &nextNetworkServiceRegistryClient{nextParent: nextParent, clients: n.clients, index: n.index + 1})
+		return n.clients[n.index], withNextNSRegistryClient(ctx, &nextNetworkServiceRegistryClient{nextParent: nextParent, clients: n.clients, elements: n.elements, index: n.index + 1})
 	}
 	return n.clients[n.index], withNextNSRegistryClient(ctx, nextParent)
 }
+
+// measure starts measuring the call of the current element, see profile.Start
+func (n *nextNetworkServiceRegistryClient) measure(ctx context.Context, method string) (context.Context, func()) {
+	if !profile.IsEnabled() || n.index >= len(n.elements) {
+		return ctx, func() {}
+	}
+	// Nested chains are not elements, their elements are measured
+	if _, ok := n.elements[n.index].(*nextNetworkServiceRegistryClient); ok {
+		return ctx, func() {}
+	}
+	return profile.Start(ctx, n.elements[n.index], method)
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/core/streamcontext"
+	"github.com/networkservicemesh/sdk/pkg/tools/profile"
 
 	"github.com/golang/protobuf/ptypes/empty"
 
@@ -33,7 +34,9 @@ type NetworkServiceRegistryServerWrapper func(server registry.NetworkServiceRegi
 type NetworkServiceRegistryServerChainer func(servers ...registry.NetworkServiceRegistryServer) registry.NetworkServiceRegistryServer
 
 type nextNetworkServiceRegistryServer struct {
-	servers    []registry.NetworkServiceRegistryServer
+	servers []registry.NetworkServiceRegistryServer
+	// elements are not wrapped, they only name the elements in the profiles and are skipped by introspect
+	elements   []registry.NetworkServiceRegistryServer `introspect:"-"`
 	index      int
 	nextParent registry.NetworkServiceRegistryServer
 }
@@ -43,6 +46,7 @@ func NewWrappedNetworkServiceRegistryServer(wrapper NetworkServiceRegistryServer
 	rv := &nextNetworkServiceRegistryServer{servers: make([]registry.NetworkServiceRegistryServer, 0, len(servers))}
 	for _, s := range servers {
 		rv.servers = append(rv.servers, wrapper(s))
+		rv.elements = append(rv.elements, s)
 	}
 	return rv
 }
@@ -56,16 +60,26 @@ func NewNetworkServiceRegistryServer(servers ...registry.NetworkServiceRegistryS
 
 func (n *nextNetworkServiceRegistryServer) Register(ctx context.Context, request *registry.NetworkService) (*registry.NetworkService, error) {
 	server, ctx := n.getServerAndContext(ctx)
+	ctx, done := n.measure(ctx, "Register")
+	defer done()
 	return server.Register(ctx, request)
 }
 
 func (n *nextNetworkServiceRegistryServer) Find(query *registry.NetworkServiceQuery, s registry.NetworkServiceRegistry_FindServer) error {
 	server, ctx := n.getServerAndContext(s.Context())
+	// Watch Finds last as long as their streams, so only the lookups are measured
+	if !query.GetWatch() {
+		var done func()
+		ctx, done = n.measure(ctx, "Find")
+		defer done()
+	}
 	return server.Find(query, streamcontext.NetworkServiceRegistryFindServer(ctx, s))
 }
 
 func (n *nextNetworkServiceRegistryServer) Unregister(ctx context.Context, request *registry.NetworkService) (*empty.Empty, error) {
 	server, ctx := n.getServerAndContext(ctx)
+	ctx, done := n.measure(ctx, "Unregister")
+	defer done()
 	return server.Unregister(ctx, request)
 }
 
@@ -78,7 +92,19 @@ func (n *nextNetworkServiceRegistryServer) getServerAndContext(ctx context.Conte
 		}
 	}
 	if n.index+1 < len(n.servers) {
-		return n.servers[n.index], withNextNSRegistryServer(ctx, &nextNetworkServiceRegistryServer{nextParent: nextParent, servers: n.servers, index: n.index + 1})
+		return n.servers[n.index], withNextNSRegistryServer(ctx, &nextNetworkServiceRegistryServer{nextParent: nextParent, servers: n.servers, elements: n.elements, index: n.index + 1})
 	}
 	return n.servers[n.index], withNextNSRegistryServer(ctx, nextParent)
 }
+
+// measure starts measuring the call of the current element, see profile.Start
+func (n *nextNetworkServiceRegistryServer) measure(ctx context.Context, method string) (context.Context, func()) {
+	if !profile.IsEnabled() || n.index >= len(n.elements) {
+		return ctx, func() {}
+	}
+	// Nested chains are not elements, their elements are measured
+	if _, ok := n.elements[n.index].(*nextNetworkServiceRegistryServer); ok {
+		return ctx, func() {}
+	}
+	return profile.Start(ctx, n.elements[n.index], method)
+}
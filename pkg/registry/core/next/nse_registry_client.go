@@ -22,6 +22,8 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/networkservicemesh/api/pkg/api/registry"
 	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/profile"
 )
 
 // NetworkServiceEndpointRegistryClientWrapper - function that wraps a registry server
@@ -31,7 +33,9 @@ type NetworkServiceEndpointRegistryClientWrapper func(client registry.NetworkSer
 type NetworkServiceEndpointRegistryClientChainer func(clients ...registry.NetworkServiceEndpointRegistryClient) registry.NetworkServiceEndpointRegistryClient
 
 type nextNetworkServiceEndpointRegistryClient struct {
-	clients    []registry.NetworkServiceEndpointRegistryClient
+	clients []registry.NetworkServiceEndpointRegistryClient
+	// elements are not wrapped, they only name the elements in the profiles and are skipped by introspect
+	elements   []registry.NetworkServiceEndpointRegistryClient `introspect:"-"`
 	index      int
 	nextParent registry.NetworkServiceEndpointRegistryClient
 }
@@ -41,6 +45,7 @@ func NewWrappedNetworkServiceEndpointRegistryClient(wrapper NetworkServiceEndpoi
 	rv := &nextNetworkServiceEndpointRegistryClient{clients: make([]registry.NetworkServiceEndpointRegistryClient, 0, len(clients))}
 	for _, c := range clients {
 		rv.clients = append(rv.clients, wrapper(c))
+		rv.elements = append(rv.elements, c)
 	}
 	return rv
 }
@@ -54,16 +59,26 @@ func NewNetworkServiceEndpointRegistryClient(clients ...registry.NetworkServiceE
 
 func (n *nextNetworkServiceEndpointRegistryClient) Register(ctx context.Context, in *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*registry.NetworkServiceEndpoint, error) {
 	client, ctx := n.getClientAndContext(ctx)
+	ctx, done := n.measure(ctx, "Register")
+	defer done()
 	return client.Register(ctx, in, opts...)
 }
 
 func (n *nextNetworkServiceEndpointRegistryClient) Find(ctx context.Context, in *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
 	client, ctx := n.getClientAndContext(ctx)
+	// Watch Finds last as long as their streams, so only the lookups are measured
+	if !in.GetWatch() {
+		var done func()
+		ctx, done = n.measure(ctx, "Find")
+		defer done()
+	}
 	return client.Find(ctx, in, opts...)
 }
 
 func (n *nextNetworkServiceEndpointRegistryClient) Unregister(ctx context.Context, in *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*empty.Empty, error) {
 	client, ctx := n.getClientAndContext(ctx)
+	ctx, done := n.measure(ctx, "Unregister")
+	defer done()
 	return client.Unregister(ctx, in, opts...)
 }
 
@@ -76,7 +91,19 @@ func (n *nextNetworkServiceEndpointRegistryClient) getClientAndContext(ctx conte
 		}
 	}
 	if n.index+1 < len(n.clients) {
-		return n.clients[n.index], withNextNSERegistryClient(ctx, &nextNetworkServiceEndpointRegistryClient{nextParent: nextParent, clients: n.clients, index: n.index + 1})
+		return n.clients[n.index], withNextNSERegistryClient(ctx, &nextNetworkServiceEndpointRegistryClient{nextParent: nextParent, clients: n.clients, elements: n.elements, index: n.index + 1})
 	}
 	return n.clients[n.index], withNextNSERegistryClient(ctx, nextParent)
 }
+
+// measure starts measuring the call of the current element, see profile.Start
+func (n *nextNetworkServiceEndpointRegistryClient) measure(ctx context.Context, method string) (context.Context, func()) {
+	if !profile.IsEnabled() || n.index >= len(n.elements) {
+		return ctx, func() {}
+	}
+	// Nested chains are not elements, their elements are measured
+	if _, ok := n.elements[n.index].(*nextNetworkServiceEndpointRegistryClient); ok {
+		return ctx, func() {}
+	}
+	return profile.Start(ctx, n.elements[n.index], method)
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/core/streamcontext"
+	"github.com/networkservicemesh/sdk/pkg/tools/profile"
 
 	"github.com/golang/protobuf/ptypes/empty"
 
@@ -33,7 +34,9 @@ type NetworkServiceEndpointRegistryServerWrapper func(server registry.NetworkSer
 type NetworkServiceEndpointRegistryServerChainer func(servers ...registry.NetworkServiceEndpointRegistryServer) registry.NetworkServiceEndpointRegistryServer
 
 type nextNetworkServiceEndpointRegistryServer struct {
-	servers    []registry.NetworkServiceEndpointRegistryServer
+	servers []registry.NetworkServiceEndpointRegistryServer
+	// elements are not wrapped, they only name the elements in the profiles and are skipped by introspect
+	elements   []registry.NetworkServiceEndpointRegistryServer `introspect:"-"`
 	index      int
 	nextParent registry.NetworkServiceEndpointRegistryServer
 }
@@ -43,6 +46,7 @@ func NewWrappedNetworkServiceEndpointRegistryServer(wrapper NetworkServiceEndpoi
 	rv := &nextNetworkServiceEndpointRegistryServer{servers: make([]registry.NetworkServiceEndpointRegistryServer, 0, len(servers))}
 	for _, s := range servers {
 		rv.servers = append(rv.servers, wrapper(s))
+		rv.elements = append(rv.elements, s)
 	}
 	return rv
 }
@@ -56,16 +60,26 @@ func NewNetworkServiceEndpointRegistryServer(servers ...registry.NetworkServiceE
 
 func (n *nextNetworkServiceEndpointRegistryServer) Register(ctx context.Context, request *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
 	server, ctx := n.getServerAndContext(ctx)
+	ctx, done := n.measure(ctx, "Register")
+	defer done()
 	return server.Register(ctx, request)
 }
 
 func (n *nextNetworkServiceEndpointRegistryServer) Find(query *registry.NetworkServiceEndpointQuery, s registry.NetworkServiceEndpointRegistry_FindServer) error {
 	server, ctx := n.getServerAndContext(s.Context())
+	// Watch Finds last as long as their streams, so only the lookups are measured
+	if !query.GetWatch() {
+		var done func()
+		ctx, done = n.measure(ctx, "Find")
+		defer done()
+	}
 	return server.Find(query, streamcontext.NetworkServiceEndpointRegistryFindServer(ctx, s))
 }
 
 func (n *nextNetworkServiceEndpointRegistryServer) Unregister(ctx context.Context, request *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
 	server, ctx := n.getServerAndContext(ctx)
+	ctx, done := n.measure(ctx, "Unregister")
+	defer done()
 	return server.Unregister(ctx, request)
 }
 
@@ -78,7 +92,19 @@ func (n *nextNetworkServiceEndpointRegistryServer) getServerAndContext(ctx conte
 		}
 	}
 	if n.index+1 < len(n.servers) {
-		return n.servers[n.index], withNextNSERegistryServer(ctx, &nextNetworkServiceEndpointRegistryServer{nextParent: nextParent, servers: n.servers, index: n.index + 1})
+		return n.servers[n.index], withNextNSERegistryServer(ctx, &nextNetworkServiceEndpointRegistryServer{nextParent: nextParent, servers: n.servers, elements: n.elements, index: n.index + 1})
 	}
 	return n.servers[n.index], withNextNSERegistryServer(ctx, nextParent)
 }
+
+// measure starts measuring the call of the current element, see profile.Start
+func (n *nextNetworkServiceEndpointRegistryServer) measure(ctx context.Context, method string) (context.Context, func()) {
+	if !profile.IsEnabled() || n.index >= len(n.elements) {
+		return ctx, func() {}
+	}
+	// Nested chains are not elements, their elements are measured
+	if _, ok := n.elements[n.index].(*nextNetworkServiceEndpointRegistryServer); ok {
+		return ctx, func() {}
+	}
+	return profile.Start(ctx, n.elements[n.index], method)
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package findoptions provides additional registry Find query options that are passed via context and grpc metadata:
+// label selectors, pagination of non-watch queries and resumption of watch queries from the revision.
+package findoptions
+
+import (
+	"context"
+	"encoding/base64"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
+)
+
+const (
+	selectorKey      = "nsm-find-selector"
+	pageSizeKey      = "nsm-find-page-size"
+	continueTokenKey = "nsm-find-continue"
+	revisionsKey     = "nsm-find-revisions"
+	fromRevisionKey  = "nsm-find-from-revision"
+	deleteReasonsKey = "nsm-find-delete-reasons"
+	nextTokenKey     = "nsm-find-next"
+)
+
+type optionsKey struct{}
+
+// Options - additional Find query options
+type Options struct {
+	// LabelSelector - label selector expression applied to network service labels, see matchutils.ParseSelector
+	LabelSelector string
+	// PageSize - max count of entities returned by non-watch Find, 0 means unlimited
+	PageSize int
+	// ContinueToken - token of the page to start from, empty means the first page
+	ContinueToken string
+	// NextToken - set by the registry, token of the page following the returned one, empty if it is the last page.
+	// Remote clients receive it in the grpc trailer, see NextToken.
+	NextToken string
+	// WatchRevisions - requests the revision of the watch stream in the grpc trailer, see Revision
+	WatchRevisions bool
+	// FromRevision - the last revision received by the watcher. Only the events after it are replayed if the registry
+	// still has them, otherwise all matching entities are sent. Implies WatchRevisions.
+	FromRevision uint64
+	// DeleteReasons - requests the reasons of the deleted events in the grpc trailer of the watch stream, see
+	// tombstone.TrailerReason. The registry closes the stream once it has sent the deleted event with the reason, so
+	// the watcher receives the reason with no delay. The watcher resumes from the revision, otherwise the deletions
+	// retained by the registry close the new stream again. Implies WatchRevisions.
+	DeleteReasons bool
+}
+
+// WithOptions puts options into the context. Options are also appended to the outgoing grpc metadata so remote
+// registries receive them.
+func WithOptions(ctx context.Context, options *Options) context.Context {
+	if options == nil {
+		return ctx
+	}
+	var kv []string
+	if options.LabelSelector != "" {
+		kv = append(kv, selectorKey, options.LabelSelector)
+	}
+	if options.PageSize > 0 {
+		kv = append(kv, pageSizeKey, strconv.Itoa(options.PageSize))
+	}
+	if options.ContinueToken != "" {
+		kv = append(kv, continueTokenKey, options.ContinueToken)
+	}
+	if options.WatchRevisions || options.FromRevision > 0 || options.DeleteReasons {
+		kv = append(kv, revisionsKey, "true")
+	}
+	if options.FromRevision > 0 {
+		kv = append(kv, fromRevisionKey, strconv.FormatUint(options.FromRevision, 10))
+	}
+	if options.DeleteReasons {
+		kv = append(kv, deleteReasonsKey, "true")
+	}
+	if len(kv) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, kv...)
+	}
+	return context.WithValue(ctx, optionsKey{}, options)
+}
+
+// FromContext returns options stored in the context or received in the incoming grpc metadata.
+// If there are no options, returns empty Options.
+func FromContext(ctx context.Context) (*Options, error) {
+	if value, ok := ctx.Value(optionsKey{}).(*Options); ok {
+		return value, nil
+	}
+	options := new(Options)
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return options, nil
+	}
+	options.LabelSelector = last(md.Get(selectorKey))
+	options.ContinueToken = last(md.Get(continueTokenKey))
+	if pageSize := last(md.Get(pageSizeKey)); pageSize != "" {
+		size, err := strconv.Atoi(pageSize)
+		if err != nil || size < 0 {
+			return nil, errors.Errorf("invalid page size: %q", pageSize)
+		}
+		options.PageSize = size
+	}
+	options.WatchRevisions = last(md.Get(revisionsKey)) == "true"
+	if fromRevision := last(md.Get(fromRevisionKey)); fromRevision != "" {
+		revision, err := strconv.ParseUint(fromRevision, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid revision: %q", fromRevision)
+		}
+		options.FromRevision = revision
+		options.WatchRevisions = true
+	}
+	if last(md.Get(deleteReasonsKey)) == "true" {
+		options.DeleteReasons = true
+		options.WatchRevisions = true
+	}
+	return options, nil
+}
+
+// Clone clones Options
+func (o *Options) Clone() *Options {
+	if o == nil {
+		return new(Options)
+	}
+	result := *o
+	return &result
+}
+
+// Selector parses LabelSelector
+func (o *Options) Selector() (*matchutils.Selector, error) {
+	return matchutils.ParseSelector(o.LabelSelector)
+}
+
+// After returns the name of the last entity of the previous page encoded into ContinueToken
+func (o *Options) After() (string, error) {
+	if o.ContinueToken == "" {
+		return "", nil
+	}
+	name, err := base64.RawURLEncoding.DecodeString(o.ContinueToken)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid continue token: %q", o.ContinueToken)
+	}
+	return string(name), nil
+}
+
+// ContinueToken returns token to request the page following the entity with the given name
+func ContinueToken(name string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(name))
+}
+
+// Page sorts entities by name and returns the page requested by options with the token of the following page.
+// The token is empty if there are no more entities.
+func Page[T interface{ GetName() string }](entities []T, options *Options) (page []T, nextToken string, err error) {
+	if options.PageSize == 0 && options.ContinueToken == "" {
+		return entities, "", nil
+	}
+
+	after, err := options.After()
+	if err != nil {
+		return nil, "", err
+	}
+
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].GetName() < entities[j].GetName()
+	})
+	start := sort.Search(len(entities), func(i int) bool {
+		return entities[i].GetName() > after
+	})
+	page = entities[start:]
+
+	if options.PageSize > 0 && len(page) > options.PageSize {
+		page = page[:options.PageSize]
+		nextToken = ContinueToken(page[len(page)-1].GetName())
+	}
+	return page, nextToken, nil
+}
+
+// SetNextToken passes the token of the next page to the client: it is set to Options stored in the context for
+// in-process clients and to the grpc trailer for remote ones
+func SetNextToken(ctx context.Context, token string) {
+	if options, ok := ctx.Value(optionsKey{}).(*Options); ok {
+		options.NextToken = token
+	}
+	if token != "" {
+		// ctx is not a grpc server stream context for in-process Find, trailer is not needed there
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(nextTokenKey, token))
+	}
+}
+
+// NextToken returns the token of the next page from the trailer received with grpc.Trailer call option
+func NextToken(trailer metadata.MD) string {
+	return last(trailer.Get(nextTokenKey))
+}
+
+func last(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package findoptions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/findoptions"
+)
+
+func TestOptions_ThroughGRPCMetadata(t *testing.T) {
+	token := findoptions.ContinueToken("nse-1")
+
+	clientCtx := findoptions.WithOptions(context.Background(), &findoptions.Options{
+		LabelSelector: "app in (a,b)",
+		PageSize:      10,
+		ContinueToken: token,
+	})
+	md, ok := metadata.FromOutgoingContext(clientCtx)
+	require.True(t, ok)
+
+	options, err := findoptions.FromContext(metadata.NewIncomingContext(context.Background(), md))
+	require.NoError(t, err)
+	require.Equal(t, "app in (a,b)", options.LabelSelector)
+	require.Equal(t, 10, options.PageSize)
+	require.False(t, options.WatchRevisions)
+
+	after, err := options.After()
+	require.NoError(t, err)
+	require.Equal(t, "nse-1", after)
+}
+
+func TestOptions_FromRevision(t *testing.T) {
+	clientCtx := findoptions.WithOptions(context.Background(), &findoptions.Options{FromRevision: 5})
+	md, _ := metadata.FromOutgoingContext(clientCtx)
+
+	options, err := findoptions.FromContext(metadata.NewIncomingContext(context.Background(), md))
+	require.NoError(t, err)
+	require.True(t, options.WatchRevisions)
+	require.Equal(t, uint64(5), options.FromRevision)
+}
+
+func TestOptions_InvalidPageSize(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("nsm-find-page-size", "-1"))
+
+	_, err := findoptions.FromContext(ctx)
+	require.Error(t, err)
+}
+
+func TestRevision(t *testing.T) {
+	require.Zero(t, findoptions.Revision(nil))
+	require.Zero(t, findoptions.Revision(metadata.Pairs("nsm-find-revision", "invalid")))
+	require.Equal(t, uint64(42), findoptions.Revision(metadata.Pairs("nsm-find-revision", "42")))
+}
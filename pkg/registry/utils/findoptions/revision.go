@@ -0,0 +1,47 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package findoptions
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// revisionKey is the grpc metadata key of the registry revision. The registry API has no field for it, so the watch
+// stream carries it in the trailer: the revision of the last batch of events sent in full. A stream broken before the
+// trailer is received has no revision, and the watcher should resume it from the revision received earlier.
+const revisionKey = "nsm-find-revision"
+
+// SetRevision sets the revision of the last batch of events sent in full to the grpc trailer of the Find stream. It is
+// set only if WatchRevisions is requested.
+func SetRevision(ctx context.Context, revision uint64) {
+	// ctx is not a grpc server stream context for in-process Find, revisions are not passed there
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(revisionKey, strconv.FormatUint(revision, 10)))
+}
+
+// Revision returns the revision from the trailer received with grpc.Trailer call option, all the events up to it are
+// received by the watcher. Returns 0 if there is no revision.
+func Revision(trailer metadata.MD) uint64 {
+	revision, err := strconv.ParseUint(last(trailer.Get(revisionKey)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
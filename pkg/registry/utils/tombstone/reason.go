@@ -0,0 +1,125 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tombstone provides reasons of the registry entities deletion. The reason is passed through the Unregister
+// context and is sent to the watchers in the Find stream trailer, so the watchers can tell a crash from a graceful
+// exit.
+package tombstone
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Reason - reason of the registry entity deletion
+type Reason uint64
+
+const (
+	// Unregistered - entity was gracefully unregistered by its owner
+	Unregistered Reason = iota
+	// Expired - entity was not refreshed before its expiration time
+	Expired
+	// Evicted - entity was removed by the registry administrator
+	Evicted
+	// Unreachable - entity URL failed the liveness probes
+	Unreachable
+	// Revoked - credentials of the entity owner were revoked
+	Revoked
+)
+
+func (r Reason) String() string {
+	switch r {
+	case Unregistered:
+		return "unregistered"
+	case Expired:
+		return "expired"
+	case Evicted:
+		return "evicted"
+	case Unreachable:
+		return "unreachable"
+	case Revoked:
+		return "revoked"
+	}
+	return "unknown"
+}
+
+// Forced returns true if the entity was removed against the will of its owner and shouldn't be used anymore even if
+// it is still running
+func (r Reason) Forced() bool {
+	return r == Evicted || r == Unreachable || r == Revoked
+}
+
+// evictedKey is the grpc metadata key marking Unregister as the administrator eviction
+const evictedKey = "nsm-tombstone-evicted"
+
+type reasonKey struct{}
+
+// WithReason returns context with the deletion reason to be passed to Unregister
+func WithReason(ctx context.Context, reason Reason) context.Context {
+	return context.WithValue(ctx, reasonKey{}, reason)
+}
+
+// WithEviction returns context for the registry client Unregister evicting the entity. The eviction is also passed in
+// the outgoing grpc metadata, so the remote registry deletes the entity with Evicted reason.
+func WithEviction(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(WithReason(ctx, Evicted), evictedKey, "true")
+}
+
+// ReasonFromContext returns the deletion reason from the Unregister context or Evicted if the eviction was received in
+// the incoming grpc metadata, Unregistered by default
+func ReasonFromContext(ctx context.Context) Reason {
+	if reason, ok := ctx.Value(reasonKey{}).(Reason); ok {
+		return reason
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(evictedKey)) > 0 {
+		return Evicted
+	}
+	return Unregistered
+}
+
+// trailerReasonKey is the grpc metadata key of the deletion reasons. The registry API has no field for them, so the
+// watch stream carries them in the trailer as "<name>=<reason>" values, see findoptions.Options.DeleteReasons.
+const trailerReasonKey = "nsm-tombstone-reason"
+
+// SetTrailerReason adds the deletion reason of the entity to the grpc trailer of the Find stream. Unregistered is not
+// added, it is the reason of the deleted events with no reason in the trailer.
+func SetTrailerReason(ctx context.Context, name string, reason Reason) {
+	if reason == Unregistered {
+		return
+	}
+	// ctx is not a grpc server stream context for in-process Find without grpc.Trailer call option, see adapters
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(trailerReasonKey, name+"="+strconv.FormatUint(uint64(reason), 10)))
+}
+
+// TrailerReason returns the deletion reason of the entity from the trailer received with grpc.Trailer call option,
+// Unregistered if there is no reason for it
+func TrailerReason(trailer metadata.MD, name string) Reason {
+	var result = Unregistered
+	for _, value := range trailer.Get(trailerReasonKey) {
+		i := strings.LastIndex(value, "=")
+		if i < 0 || value[:i] != name {
+			continue
+		}
+		if reason, err := strconv.ParseUint(value[i+1:], 10, 64); err == nil {
+			result = Reason(reason)
+		}
+	}
+	return result
+}
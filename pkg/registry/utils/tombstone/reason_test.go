@@ -0,0 +1,92 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tombstone_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/tombstone"
+)
+
+type trailerStream struct {
+	trailer metadata.MD
+}
+
+func (s *trailerStream) Method() string {
+	return ""
+}
+
+func (s *trailerStream) SetHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *trailerStream) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *trailerStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestTrailerReason(t *testing.T) {
+	stream := new(trailerStream)
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+
+	tombstone.SetTrailerReason(ctx, "nse-1", tombstone.Expired)
+	tombstone.SetTrailerReason(ctx, "nse=2", tombstone.Revoked)
+	tombstone.SetTrailerReason(ctx, "nse-1", tombstone.Unreachable)
+	require.Equal(t, tombstone.Unreachable, tombstone.TrailerReason(stream.trailer, "nse-1"))
+	require.Equal(t, tombstone.Revoked, tombstone.TrailerReason(stream.trailer, "nse=2"))
+	require.Equal(t, tombstone.Unregistered, tombstone.TrailerReason(stream.trailer, "nse-3"))
+
+	// Graceful deletions have no reason in the trailer
+	tombstone.SetTrailerReason(ctx, "nse-3", tombstone.Unregistered)
+	require.Len(t, stream.trailer.Get("nsm-tombstone-reason"), 3)
+	require.Equal(t, tombstone.Unregistered, tombstone.TrailerReason(nil, "nse-1"))
+}
+
+func TestReasonFromContext(t *testing.T) {
+	ctx := context.Background()
+	require.Equal(t, tombstone.Unregistered, tombstone.ReasonFromContext(ctx))
+	require.Equal(t, tombstone.Expired, tombstone.ReasonFromContext(tombstone.WithReason(ctx, tombstone.Expired)))
+
+	evictCtx := tombstone.WithEviction(ctx)
+	require.Equal(t, tombstone.Evicted, tombstone.ReasonFromContext(evictCtx))
+
+	// The registry server receives the eviction in the incoming metadata
+	md, ok := metadata.FromOutgoingContext(evictCtx)
+	require.True(t, ok)
+	require.Equal(t, tombstone.Evicted, tombstone.ReasonFromContext(metadata.NewIncomingContext(ctx, md)))
+}
+
+func TestReason_Forced(t *testing.T) {
+	for reason, forced := range map[tombstone.Reason]bool{
+		tombstone.Unregistered: false,
+		tombstone.Expired:      false,
+		tombstone.Evicted:      true,
+		tombstone.Unreachable:  true,
+		tombstone.Revoked:      true,
+	} {
+		require.Equal(t, forced, reason.Forced(), reason.String())
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package introspect walks running chains and returns the tree of their elements.
+//
+// Chains are opaque nested structures, so the elements are found with reflection: every field of an element and the
+// slices, arrays and maps with string keys holding elements are searched for other elements. It covers next chains,
+// switchcase branches, connect sub-chains and mechanisms maps without any support from the elements themselves. Other
+// slices and maps keep the runtime state of the elements and are not read. Tracing wrappers added by chain are skipped,
+// scalar fields of the elements are reported as options, strings only for the known names like "name" or "url".
+// Fields tagged `introspect:"-"` are skipped.
+//
+// The tree is rendered as text or Graphviz DOT, NewHandler serves it for debugging and Types helps to assert the
+// shape of a chain in unit tests:
+//
+//	require.Equal(t, []string{"*begin.beginServer", "*metadata.metadataServer"}, introspect.Walk(server).Types())
+package introspect
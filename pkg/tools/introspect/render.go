@@ -0,0 +1,125 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package introspect
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Types returns the types of the elements in the order of the tree, chains are flattened to their elements
+func (n *Node) Types() []string {
+	var result []string
+	n.visit(func(node *Node) {
+		if !node.Chain {
+			result = append(result, node.Type)
+		}
+	})
+	return result
+}
+
+// Find returns the first node of the type in the order of the tree, or nil
+func (n *Node) Find(typ string) *Node {
+	var result *Node
+	n.visit(func(node *Node) {
+		if result == nil && node.Type == typ {
+			result = node
+		}
+	})
+	return result
+}
+
+// String renders the tree as indented text
+func (n *Node) String() string {
+	if n == nil {
+		return ""
+	}
+	var sb strings.Builder
+	n.writeText(&sb, "", "")
+	return sb.String()
+}
+
+// DOT renders the tree as Graphviz DOT digraph
+func (n *Node) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph chain {\n\tnode [shape=box];\n")
+	if n != nil {
+		var id int
+		n.writeDOT(&sb, &id)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (n *Node) visit(f func(*Node)) {
+	if n == nil {
+		return
+	}
+	f(n)
+	for _, child := range n.Children {
+		child.visit(f)
+	}
+}
+
+func (n *Node) label() string {
+	var sb strings.Builder
+	sb.WriteString(n.Type)
+	if len(n.Options) != 0 {
+		keys := make([]string, 0, len(n.Options))
+		for key := range n.Options {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		options := make([]string, 0, len(keys))
+		for _, key := range keys {
+			options = append(options, key+"="+n.Options[key])
+		}
+		sb.WriteString(" {" + strings.Join(options, ", ") + "}")
+	}
+	if n.Repeated {
+		sb.WriteString(" (repeated)")
+	}
+	return sb.String()
+}
+
+func (n *Node) writeText(sb *strings.Builder, prefix, childPrefix string) {
+	sb.WriteString(prefix)
+	if n.Name != "" {
+		sb.WriteString(n.Name + ": ")
+	}
+	sb.WriteString(n.label() + "\n")
+	for i, child := range n.Children {
+		if i == len(n.Children)-1 {
+			child.writeText(sb, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			child.writeText(sb, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+func (n *Node) writeDOT(sb *strings.Builder, id *int) int {
+	nodeID := *id
+	*id++
+	_, _ = fmt.Fprintf(sb, "\tn%d [label=%s];\n", nodeID, strconv.Quote(n.label()))
+	for _, child := range n.Children {
+		childID := child.writeDOT(sb, id)
+		_, _ = fmt.Fprintf(sb, "\tn%d -> n%d [label=%s];\n", nodeID, childID, strconv.Quote(child.Name))
+	}
+	return nodeID
+}
@@ -0,0 +1,331 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package introspect
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+)
+
+// Node is a chain element in the tree
+type Node struct {
+	// Name is the path of the element in its parent, e.g. "cases[0].Server"
+	Name string `json:"name,omitempty"`
+	// Type is the Go type of the element, e.g. "*begin.beginServer"
+	Type string `json:"type"`
+	// Options are non-zero scalar fields of the element, string fields are reported only if they are in stringOptions
+	Options map[string]string `json:"options,omitempty"`
+	// Repeated is true if the element is already present in the tree, its children are not repeated
+	Repeated bool `json:"repeated,omitempty"`
+	// Chain is true if the element only passes requests through its children, e.g. "*next.nextServer"
+	Chain    bool    `json:"chain,omitempty"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+// maxDepth limits the search of the elements inside the fields which are not elements
+const maxDepth = 8
+
+var (
+	elementTypes = []reflect.Type{
+		reflect.TypeOf((*networkservice.NetworkServiceServer)(nil)).Elem(),
+		reflect.TypeOf((*networkservice.NetworkServiceClient)(nil)).Elem(),
+		reflect.TypeOf((*registry.NetworkServiceRegistryServer)(nil)).Elem(),
+		reflect.TypeOf((*registry.NetworkServiceRegistryClient)(nil)).Elem(),
+		reflect.TypeOf((*registry.NetworkServiceEndpointRegistryServer)(nil)).Elem(),
+		reflect.TypeOf((*registry.NetworkServiceEndpointRegistryClient)(nil)).Elem(),
+	}
+	contextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	// wrapperPackages are skipped in the tree, the wrapped element is reported instead
+	wrapperPackages = []string{"/core/trace", "/core/trace/traceverbose", "/core/trace/traceconcise"}
+	// chainPackages are flattened by Types
+	chainPackages = []string{"/core/next"}
+	// prunedPackages keep runtime state rather than the chain structure
+	prunedPackages = []string{"sync", "sync/atomic", "google.golang.org/grpc", "github.com/sirupsen/logrus"}
+	// stringOptions are the names of the string fields reported as options, other strings may keep tokens or secrets
+	stringOptions = []string{"name", "url", "nsmgrURL", "forwarderServiceName", "networkService", "payload"}
+)
+
+// Walk returns the tree of the chain elements starting from element
+func Walk(element interface{}) *Node {
+	w := &walker{visited: make(map[uintptr]bool)}
+	v := reflect.ValueOf(element)
+	if !v.IsValid() {
+		return nil
+	}
+	return w.element("", v)
+}
+
+type walker struct {
+	visited map[uintptr]bool
+}
+
+func (w *walker) element(name string, v reflect.Value) *Node {
+	v = unwrap(dynamic(v))
+
+	node := &Node{Name: name, Type: v.Type().String(), Chain: inPackages(v.Type(), chainPackages)}
+	// Pointers to zero sized values may be equal, so they are not tracked
+	if v.Kind() == reflect.Pointer && v.Type().Elem().Size() != 0 {
+		if w.visited[v.Pointer()] {
+			node.Repeated = true
+			return node
+		}
+		w.visited[v.Pointer()] = true
+	}
+
+	s := v
+	if s.Kind() == reflect.Pointer {
+		s = s.Elem()
+	}
+	if s.Kind() != reflect.Struct {
+		return node
+	}
+	for i := 0; i < s.NumField(); i++ {
+		field := s.Type().Field(i)
+		if field.Tag.Get("introspect") == "-" {
+			continue
+		}
+		if value, ok := scalar(field.Name, s.Field(i)); ok {
+			if node.Options == nil {
+				node.Options = make(map[string]string)
+			}
+			node.Options[field.Name] = value
+			continue
+		}
+		w.children(node, field.Name, s.Field(i), 0)
+	}
+
+	// chain.New* functions put the chain of wrapped elements into another chain
+	if node.Chain && len(node.Children) == 1 && node.Children[0].Chain {
+		node.Children[0].Name = node.Name
+		return node.Children[0]
+	}
+	return node
+}
+
+// children adds the elements found in v to the node children
+func (w *walker) children(node *Node, path string, v reflect.Value, depth int) {
+	if depth > maxDepth || !v.IsValid() || pruned(v.Type()) {
+		return
+	}
+	if isElement(v) {
+		node.Children = append(node.Children, w.element(path, v))
+		return
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if !v.IsNil() {
+			w.children(node, path, v.Elem(), depth+1)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			w.children(node, path+"."+v.Type().Field(i).Name, v.Field(i), depth+1)
+		}
+	case reflect.Slice, reflect.Array:
+		if !holdsElements(v.Type().Elem()) {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			w.children(node, fmt.Sprintf("%s[%d]", path, i), v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || !holdsElements(v.Type().Elem()) {
+			return
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			w.children(node, fmt.Sprintf("%s[%s]", path, key.String()), v.MapIndex(key), depth+1)
+		}
+	default:
+	}
+}
+
+// unwrap returns the element wrapped by the tracing wrappers
+func unwrap(v reflect.Value) reflect.Value {
+	for inPackages(v.Type(), wrapperPackages) {
+		wrapped, ok := firstElement(v, 0)
+		if !ok {
+			return v
+		}
+		// Wrappers put the wrapped element into their own chains, these chains start with a wrapper
+		if inPackages(wrapped.Type(), chainPackages) {
+			if first, ok := firstElement(wrapped, 0); ok && inPackages(first.Type(), wrapperPackages) {
+				wrapped = first
+			}
+		}
+		v = wrapped
+	}
+	return v
+}
+
+// firstElement returns the first element found inside v
+func firstElement(v reflect.Value, depth int) (reflect.Value, bool) {
+	if depth > maxDepth || !v.IsValid() || pruned(v.Type()) {
+		return reflect.Value{}, false
+	}
+	if depth > 0 && isElement(v) {
+		return dynamic(v), true
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if !v.IsNil() {
+			return firstElement(v.Elem(), depth+1)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if found, ok := firstElement(v.Field(i), depth+1); ok {
+				return found, true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if !holdsElements(v.Type().Elem()) {
+			break
+		}
+		for i := 0; i < v.Len(); i++ {
+			if found, ok := firstElement(v.Index(i), depth+1); ok {
+				return found, true
+			}
+		}
+	default:
+	}
+	return reflect.Value{}, false
+}
+
+// dynamic returns the value stored in the interface
+func dynamic(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+func isElement(v reflect.Value) bool {
+	v = dynamic(v)
+	if !v.IsValid() || v.Kind() == reflect.Interface || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return false
+	}
+	return isElementType(v.Type())
+}
+
+func isElementType(t reflect.Type) bool {
+	for _, elementType := range elementTypes {
+		if t.Implements(elementType) {
+			return true
+		}
+	}
+	return false
+}
+
+// holdsElements returns true if the values of the type are elements or structs with the element fields. Slices and
+// maps of other types keep the runtime state of the elements, they are changed concurrently and are not walked.
+func holdsElements(t reflect.Type) bool {
+	if isElementType(t) {
+		return true
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if isElementType(t.Field(i).Type) {
+			return true
+		}
+	}
+	return false
+}
+
+func pruned(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return true
+	case reflect.Interface:
+		return t.Implements(contextType)
+	default:
+	}
+	if t.Implements(contextType) {
+		return true
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for _, p := range prunedPackages {
+		if t.PkgPath() == p || strings.HasPrefix(t.PkgPath(), p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func inPackages(t reflect.Type, suffixes []string) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(t.PkgPath(), suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// scalar returns the string representation of non-zero scalar value of the field. Only scalar values are read, other
+// fields may be changed concurrently.
+func scalar(name string, v reflect.Value) (string, bool) {
+	var value string
+	switch v.Kind() {
+	case reflect.String:
+		if !contains(stringOptions, name) {
+			return "", false
+		}
+		value = strconv.Quote(v.String())
+	case reflect.Bool:
+		value = strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = strconv.FormatInt(v.Int(), 10)
+		if v.Type() == durationType {
+			value = time.Duration(v.Int()).String()
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		value = strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	default:
+		return "", false
+	}
+	return value, !v.IsZero()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
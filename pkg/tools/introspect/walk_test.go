@@ -0,0 +1,190 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package introspect_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/begin"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/switchcase"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/introspect"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+func testServer() networkservice.NetworkServiceServer {
+	shared := metadata.NewServer()
+	return chain.NewNetworkServiceServer(
+		updatepath.NewServer("nsc"),
+		begin.NewServer(),
+		shared,
+		switchcase.NewServer(
+			&switchcase.ServerCase{
+				Condition: switchcase.Default,
+				Server:    chain.NewNetworkServiceServer(shared),
+			},
+		),
+		mechanisms.NewServer(map[string]networkservice.NetworkServiceServer{
+			kernel.MECHANISM: begin.NewServer(),
+		}),
+	)
+}
+
+func TestWalk(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	for _, enabled := range []bool{false, true} {
+		log.EnableTracing(enabled)
+
+		root := introspect.Walk(testServer())
+
+		require.Equal(t, "*next.nextServer", root.Type)
+		require.Equal(t, []string{
+			"*updatepath.updatePathServer",
+			"*begin.beginServer",
+			"*metadata.metadataServer",
+			"*switchcase.switchServer",
+			"*metadata.metadataServer",
+			"*mechanisms.mechanismsServer",
+			"*begin.beginServer",
+		}, root.Types())
+
+		updatePath := root.Find("*updatepath.updatePathServer")
+		require.NotNil(t, updatePath)
+		require.Equal(t, `"nsc"`, updatePath.Options["name"])
+
+		switchCase := root.Find("*switchcase.switchServer")
+		require.NotNil(t, switchCase)
+		require.Len(t, switchCase.Children, 1)
+		require.Equal(t, "cases[0].Server", switchCase.Children[0].Name)
+		require.True(t, switchCase.Children[0].Children[0].Repeated)
+
+		mechanismsServer := root.Find("*mechanisms.mechanismsServer")
+		require.NotNil(t, mechanismsServer)
+		require.Len(t, mechanismsServer.Children, 1)
+		require.Equal(t, "mechanisms[KERNEL]", mechanismsServer.Children[0].Name)
+
+		require.Nil(t, root.Find("*trace.beginTraceServer"))
+	}
+	log.EnableTracing(false)
+}
+
+// stateServer keeps the runtime state changed concurrently with the walk
+type stateServer struct {
+	token string
+	mu    sync.Mutex
+	conns map[string]*networkservice.Connection
+	ids   []string
+
+	networkservice.NetworkServiceServer
+}
+
+func TestWalk_SkipsState(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	server := &stateServer{
+		token:                "secret",
+		conns:                make(map[string]*networkservice.Connection),
+		NetworkServiceServer: begin.NewServer(),
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			server.mu.Lock()
+			server.conns[strconv.Itoa(i)] = &networkservice.Connection{Id: strconv.Itoa(i)}
+			server.ids = append(server.ids, strconv.Itoa(i))
+			server.mu.Unlock()
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		root := introspect.Walk(server)
+		require.Equal(t, []string{"*introspect_test.stateServer", "*begin.beginServer"}, root.Types())
+		require.Empty(t, root.Options)
+	}
+	<-done
+}
+
+func TestNode_Render(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	root := introspect.Walk(testServer())
+
+	text := root.String()
+	require.True(t, strings.HasPrefix(text, "*next.nextServer\n"))
+	require.Contains(t, text, "├── servers[0]: *updatepath.updatePathServer {name=\"nsc\"}")
+	require.Contains(t, text, "│   └── cases[0].Server: *next.nextServer")
+	require.Contains(t, text, "*metadata.metadataServer (repeated)")
+	require.Contains(t, text, "└── servers[4]: *mechanisms.mechanismsServer")
+
+	dot := root.DOT()
+	require.True(t, strings.HasPrefix(dot, "digraph"))
+	require.Contains(t, dot, "*mechanisms.mechanismsServer")
+	require.Contains(t, dot, "mechanisms[KERNEL]")
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	server := httptest.NewServer(introspect.NewHandler(testServer()))
+	defer server.Close()
+
+	client := &http.Client{Timeout: time.Second}
+	get := func(format string) (*http.Response, string) {
+		resp, err := client.Get(server.URL + "?format=" + format)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	resp, body := get("text")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, introspect.Walk(testServer()).String(), body)
+
+	resp, body = get("dot")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.True(t, strings.HasPrefix(body, "digraph"))
+
+	resp, body = get("json")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	node := new(introspect.Node)
+	require.NoError(t, json.Unmarshal([]byte(body), node))
+	require.Equal(t, introspect.Walk(testServer()).Types(), node.Types())
+
+	resp, _ = get("xml")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sloglogger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+
+	"github.com/edwarnicke/genericsync"
+)
+
+const idKey = "id"
+
+var packages genericsync.Map[uintptr, string]
+
+// handler filters the records by the levels of their packages and connections and samples them
+type handler struct {
+	next    slog.Handler
+	levels  *Levels
+	sampler *sampler
+	id      string
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.levels.Minimum()
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.levels.Level(packageOf(r.PC), h.id) {
+		return nil
+	}
+	if h.sampler != nil && !h.sampler.sample(&r) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	rv := *h
+	rv.next = h.next.WithAttrs(attrs)
+	for _, attr := range attrs {
+		if attr.Key == idKey {
+			rv.id = attr.Value.String()
+		}
+	}
+	return &rv
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	rv := *h
+	rv.next = h.next.WithGroup(name)
+	return &rv
+}
+
+// packageOf returns the import path of the package of the function with the pc
+func packageOf(pc uintptr) string {
+	if pkg, ok := packages.Load(pc); ok {
+		return pkg
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	pkg := packageOfFunc(frame.Function)
+	packages.Store(pc, pkg)
+	return pkg
+}
+
+// packageOfFunc returns the import path of the package from the full function name, e.g.
+// "github.com/networkservicemesh/sdk/pkg/networkservice/common/begin.(*beginServer).Request"
+func packageOfFunc(name string) string {
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sloglogger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// LevelTrace is the level of log.Logger Trace messages
+	LevelTrace = slog.LevelDebug - 4
+	// LevelFatal is the level of log.Logger Fatal messages
+	LevelFatal = slog.LevelError + 4
+)
+
+var levelNames = map[slog.Level]string{
+	LevelTrace:      "TRACE",
+	slog.LevelDebug: "DEBUG",
+	slog.LevelInfo:  "INFO",
+	slog.LevelWarn:  "WARN",
+	slog.LevelError: "ERROR",
+	LevelFatal:      "FATAL",
+}
+
+// ParseLevel parses the level name: trace, debug, info, warn, error or fatal
+func ParseLevel(name string) (slog.Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return 0, errors.Errorf("unknown log level: %s", name)
+}
+
+func levelName(level slog.Level) string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+	return level.String()
+}
+
+// Levels are the log levels adjustable at runtime: the default level, the levels of the packages and the levels of the
+// connections. The connection level has the priority over the package level, the package level applies to the nested
+// packages too.
+type Levels struct {
+	mu          sync.RWMutex
+	defaultLvl  slog.Level
+	packages    map[string]slog.Level
+	connections map[string]slog.Level
+	minimum     atomic.Int64
+}
+
+// NewLevels returns new Levels with the default level
+func NewLevels(defaultLevel slog.Level) *Levels {
+	l := &Levels{
+		defaultLvl:  defaultLevel,
+		packages:    make(map[string]slog.Level),
+		connections: make(map[string]slog.Level),
+	}
+	l.minimum.Store(int64(defaultLevel))
+	return l
+}
+
+// SetDefault sets the default level
+func (l *Levels) SetDefault(level slog.Level) {
+	l.update(func() { l.defaultLvl = level })
+}
+
+// SetPackage sets the level of the package with the given import path and its nested packages
+func (l *Levels) SetPackage(pkg string, level slog.Level) {
+	l.update(func() { l.packages[pkg] = level })
+}
+
+// DeletePackage deletes the level of the package
+func (l *Levels) DeletePackage(pkg string) {
+	l.update(func() { delete(l.packages, pkg) })
+}
+
+// SetConnection sets the level of the messages logged with the connection "id" field
+// The level is kept until DeleteConnection, loglevels.NewServer deletes it when the connection is closed.
+func (l *Levels) SetConnection(id string, level slog.Level) {
+	l.update(func() { l.connections[id] = level })
+}
+
+// DeleteConnection deletes the level of the connection
+func (l *Levels) DeleteConnection(id string) {
+	l.update(func() { delete(l.connections, id) })
+}
+
+// Level returns the level of the messages logged from the package with the connection id, id may be empty
+func (l *Levels) Level(pkg, id string) slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if level, ok := l.connections[id]; ok && id != "" {
+		return level
+	}
+	level, match := l.defaultLvl, ""
+	for prefix, prefixLevel := range l.packages {
+		if (pkg == prefix || strings.HasPrefix(pkg, prefix+"/")) && len(prefix) > len(match) {
+			level, match = prefixLevel, prefix
+		}
+	}
+	return level
+}
+
+// Minimum returns the lowest of the levels
+func (l *Levels) Minimum() slog.Level {
+	return slog.Level(l.minimum.Load())
+}
+
+func (l *Levels) update(f func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f()
+
+	minimum := l.defaultLvl
+	for _, levels := range []map[string]slog.Level{l.packages, l.connections} {
+		for _, level := range levels {
+			if level < minimum {
+				minimum = level
+			}
+		}
+	}
+	l.minimum.Store(int64(minimum))
+}
+
+type levelsJSON struct {
+	Default     string            `json:"default"`
+	Packages    map[string]string `json:"packages,omitempty"`
+	Connections map[string]string `json:"connections,omitempty"`
+}
+
+// ServeHTTP is the admin API of the levels:
+//
+//	GET                                 returns the levels as JSON
+//	PUT ?level=debug                    sets the default level
+//	PUT ?level=debug&package=<path>     sets the package level
+//	PUT ?level=debug&id=<connection id> sets the connection level
+//	DELETE ?package=<path>              deletes the package level
+//	DELETE ?id=<connection id>          deletes the connection level
+func (l *Levels) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pkg, id := query.Get("package"), query.Get("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(l.json())
+	case http.MethodPut, http.MethodPost:
+		level, err := ParseLevel(query.Get("level"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch {
+		case id != "":
+			l.SetConnection(id, level)
+		case pkg != "":
+			l.SetPackage(pkg, level)
+		default:
+			l.SetDefault(level)
+		}
+	case http.MethodDelete:
+		switch {
+		case id != "":
+			l.DeleteConnection(id)
+		case pkg != "":
+			l.DeletePackage(pkg)
+		default:
+			http.Error(w, "package or id is expected", http.StatusBadRequest)
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (l *Levels) json() *levelsJSON {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	rv := &levelsJSON{
+		Default:     levelName(l.defaultLvl),
+		Packages:    make(map[string]string, len(l.packages)),
+		Connections: make(map[string]string, len(l.connections)),
+	}
+	for pkg, level := range l.packages {
+		rv.Packages[pkg] = levelName(level)
+	}
+	for id, level := range l.connections {
+		rv.Connections[id] = levelName(level)
+	}
+	return rv
+}
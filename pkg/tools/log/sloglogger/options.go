@@ -0,0 +1,68 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sloglogger
+
+import (
+	"io"
+	"log/slog"
+	"time"
+)
+
+// Option changes default settings of the Logger
+type Option func(*options)
+
+type options struct {
+	output  io.Writer
+	handler slog.Handler
+	levels  *Levels
+	sampler *sampler
+}
+
+// WithOutput sets where the JSON messages are written. Default is os.Stderr.
+func WithOutput(output io.Writer) Option {
+	return func(o *options) {
+		o.output = output
+	}
+}
+
+// WithHandler sets the handler of the messages instead of the JSON handler
+func WithHandler(handler slog.Handler) Option {
+	return func(o *options) {
+		o.handler = handler
+	}
+}
+
+// WithLevels sets the levels of the messages. Default is the info level for all messages.
+func WithLevels(levels *Levels) Option {
+	return func(o *options) {
+		o.levels = levels
+	}
+}
+
+// WithSampling limits the repetitive messages: only the first messages logged from the same place with the same
+// level during the tick are passed, and then every thereafter-th of them. thereafter = 0 drops all the rest.
+// By default messages are not sampled.
+func WithSampling(first, thereafter int, tick time.Duration) Option {
+	return func(o *options) {
+		o.sampler = &sampler{
+			first:      first,
+			thereafter: thereafter,
+			tick:       tick,
+			counters:   make(map[sampleKey]*sampleCounter),
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sloglogger
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type sampleKey struct {
+	pc    uintptr
+	level slog.Level
+}
+
+type sampleCounter struct {
+	resetTime time.Time
+	count     int
+}
+
+// sampler passes the first messages logged from the same place with the same level during the tick, and then every
+// thereafter-th of them
+type sampler struct {
+	first, thereafter int
+	tick              time.Duration
+
+	mu       sync.Mutex
+	counters map[sampleKey]*sampleCounter
+}
+
+func (s *sampler) sample(r *slog.Record) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := sampleKey{pc: r.PC, level: r.Level}
+	counter, ok := s.counters[key]
+	if !ok {
+		counter = new(sampleCounter)
+		s.counters[key] = counter
+	}
+	if !r.Time.Before(counter.resetTime) {
+		counter.resetTime = r.Time.Add(s.tick)
+		counter.count = 0
+	}
+	counter.count++
+
+	if counter.count <= s.first {
+		return true
+	}
+	return s.thereafter > 0 && (counter.count-s.first)%s.thereafter == 0
+}
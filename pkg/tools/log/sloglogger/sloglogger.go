@@ -0,0 +1,172 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sloglogger provides log.Logger based on slog.Handler with JSON output. The levels of the messages are set
+// per package and per connection at runtime with Levels, repetitive messages are sampled.
+package sloglogger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// logPackage is the prefix of the packages skipped when looking for the caller of the Logger
+const logPackage = "github.com/networkservicemesh/sdk/pkg/tools/log"
+
+type slogLogger struct {
+	ctx     context.Context
+	handler slog.Handler
+}
+
+// New returns a new log.Logger. The connection of the messages is set with WithField("id", connectionID).
+// ctx is passed to the handler and its clock sets the time of the messages.
+func New(ctx context.Context, opts ...Option) log.Logger {
+	o := &options{
+		output: os.Stderr,
+		levels: NewLevels(slog.LevelInfo),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.handler == nil {
+		o.handler = slog.NewJSONHandler(o.output, &slog.HandlerOptions{
+			Level: LevelTrace,
+			ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+				if attr.Key == slog.LevelKey && len(groups) == 0 {
+					attr.Value = slog.StringValue(levelName(attr.Value.Any().(slog.Level)))
+				}
+				return attr
+			},
+		})
+	}
+	return &slogLogger{
+		ctx: ctx,
+		handler: &handler{
+			next:    o.handler,
+			levels:  o.levels,
+			sampler: o.sampler,
+		},
+	}
+}
+
+func (s *slogLogger) Info(v ...interface{}) {
+	s.log(slog.LevelInfo, "", v)
+}
+
+func (s *slogLogger) Infof(format string, v ...interface{}) {
+	s.log(slog.LevelInfo, format, v)
+}
+
+func (s *slogLogger) Warn(v ...interface{}) {
+	s.log(slog.LevelWarn, "", v)
+}
+
+func (s *slogLogger) Warnf(format string, v ...interface{}) {
+	s.log(slog.LevelWarn, format, v)
+}
+
+func (s *slogLogger) Error(v ...interface{}) {
+	s.log(slog.LevelError, "", v)
+}
+
+func (s *slogLogger) Errorf(format string, v ...interface{}) {
+	s.log(slog.LevelError, format, v)
+}
+
+func (s *slogLogger) Fatal(v ...interface{}) {
+	s.log(LevelFatal, "", v)
+	os.Exit(1)
+}
+
+func (s *slogLogger) Fatalf(format string, v ...interface{}) {
+	s.log(LevelFatal, format, v)
+	os.Exit(1)
+}
+
+func (s *slogLogger) Debug(v ...interface{}) {
+	s.log(slog.LevelDebug, "", v)
+}
+
+func (s *slogLogger) Debugf(format string, v ...interface{}) {
+	s.log(slog.LevelDebug, format, v)
+}
+
+func (s *slogLogger) Trace(v ...interface{}) {
+	s.log(LevelTrace, "", v)
+}
+
+func (s *slogLogger) Tracef(format string, v ...interface{}) {
+	s.log(LevelTrace, format, v)
+}
+
+func (s *slogLogger) Object(k, v interface{}) {
+	msg := ""
+	cc, err := json.Marshal(v)
+	if err == nil {
+		msg = string(cc)
+	} else {
+		msg = fmt.Sprint(v)
+	}
+	s.log(slog.LevelInfo, "%v=%s", []interface{}{k, msg})
+}
+
+func (s *slogLogger) WithField(key, value interface{}) log.Logger {
+	return &slogLogger{
+		ctx:     s.ctx,
+		handler: s.handler.WithAttrs([]slog.Attr{slog.Any(fmt.Sprint(key), value)}),
+	}
+}
+
+func (s *slogLogger) log(level slog.Level, format string, v []interface{}) {
+	if !s.handler.Enabled(s.ctx, level) {
+		return
+	}
+
+	var msg string
+	if format == "" {
+		msg = fmt.Sprint(v...)
+	} else {
+		msg = fmt.Sprintf(format, v...)
+	}
+	r := slog.NewRecord(clock.FromContext(s.ctx).Now(), level, msg, caller())
+	_ = s.handler.Handle(s.ctx, r)
+}
+
+// caller returns pc of the first caller outside of the log packages
+func caller() uintptr {
+	var pcs [16]uintptr
+	// Skip runtime.Callers, caller, log and the Logger method
+	n := runtime.Callers(4, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !more || !isLogPackage(packageOfFunc(frame.Function)) {
+			return frame.PC
+		}
+	}
+}
+
+func isLogPackage(pkg string) bool {
+	return pkg == logPackage || (strings.HasPrefix(pkg, logPackage+"/") && !strings.HasSuffix(pkg, "_test"))
+}
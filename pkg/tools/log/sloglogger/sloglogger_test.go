@@ -0,0 +1,207 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sloglogger_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/log/sloglogger"
+)
+
+const testPackage = "github.com/networkservicemesh/sdk/pkg/tools/log/sloglogger_test"
+
+type output struct {
+	mu sync.Mutex
+	sb strings.Builder
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.sb.Write(p)
+}
+
+func (o *output) messages(t *testing.T) []map[string]interface{} {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var rv []map[string]interface{}
+	for scanner := bufio.NewScanner(strings.NewReader(o.sb.String())); scanner.Scan(); {
+		var message map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &message))
+		rv = append(rv, message)
+	}
+	return rv
+}
+
+func TestLogger_JSON(t *testing.T) {
+	out := new(output)
+	logger := sloglogger.New(context.Background(), sloglogger.WithOutput(out)).WithField("id", "conn-1")
+
+	logger.Infof("hello %d", 1)
+	logger.Debug("debug is disabled")
+	logger.Object("key", map[string]int{"value": 2})
+	log.Combine(logger).Warn("warn", "ing")
+
+	messages := out.messages(t)
+	require.Len(t, messages, 3)
+
+	require.Equal(t, "INFO", messages[0]["level"])
+	require.Equal(t, "hello 1", messages[0]["msg"])
+	require.Equal(t, "conn-1", messages[0]["id"])
+
+	require.Equal(t, `key={"value":2}`, messages[1]["msg"])
+
+	require.Equal(t, "WARN", messages[2]["level"])
+	require.Equal(t, "warning", messages[2]["msg"])
+}
+
+func TestLogger_Levels(t *testing.T) {
+	out := new(output)
+	levels := sloglogger.NewLevels(slog.LevelWarn)
+	logger := sloglogger.New(context.Background(), sloglogger.WithOutput(out), sloglogger.WithLevels(levels))
+
+	logger.Info("default")
+
+	levels.SetPackage("github.com/networkservicemesh/sdk/pkg/tools/other", sloglogger.LevelTrace)
+	logger.Info("other package")
+
+	levels.SetPackage(testPackage, slog.LevelDebug)
+	logger.Debug("package")
+	// Log packages are skipped when the caller package is looked for
+	log.Combine(logger).Debug("combined")
+	logger.Trace("package trace")
+
+	levels.SetConnection("conn-1", sloglogger.LevelTrace)
+	logger.WithField("id", "conn-1").Trace("connection")
+	logger.WithField("id", "conn-2").Trace("other connection")
+
+	levels.SetConnection("conn-2", slog.LevelError)
+	logger.WithField("id", "conn-2").Warn("connection overrides package")
+
+	levels.DeletePackage(testPackage)
+	levels.DeleteConnection("conn-1")
+	logger.Info("deleted")
+	logger.WithField("id", "conn-1").Trace("deleted")
+
+	var msgs []string
+	for _, message := range out.messages(t) {
+		msgs = append(msgs, message["msg"].(string))
+	}
+	require.Equal(t, []string{"package", "combined", "connection"}, msgs)
+	require.Equal(t, "TRACE", out.messages(t)[2]["level"])
+}
+
+func TestLogger_Sampling(t *testing.T) {
+	out := new(output)
+	logger := sloglogger.New(context.Background(), sloglogger.WithOutput(out), sloglogger.WithSampling(2, 3, time.Hour))
+
+	for i := 0; i < 10; i++ {
+		logger.Infof("repetitive %d", i)
+	}
+	logger.Info("other")
+
+	var msgs []string
+	for _, message := range out.messages(t) {
+		msgs = append(msgs, message["msg"].(string))
+	}
+	require.Equal(t, []string{"repetitive 0", "repetitive 1", "repetitive 4", "repetitive 7", "other"}, msgs)
+}
+
+func TestLogger_Clock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.New(ctx)
+	ctx = clock.WithClock(ctx, clockMock)
+
+	out := new(output)
+	logger := sloglogger.New(ctx, sloglogger.WithOutput(out), sloglogger.WithSampling(1, 0, time.Hour))
+
+	// The sampling tick follows the clock too
+	for i := 0; i < 3; i++ {
+		if i == 2 {
+			clockMock.Add(time.Hour)
+		}
+		logger.Info("repetitive")
+	}
+
+	messages := out.messages(t)
+	require.Len(t, messages, 2)
+	require.Equal(t, clockMock.Now().Add(-time.Hour).Format(time.RFC3339Nano), messages[0]["time"])
+	require.Equal(t, clockMock.Now().Format(time.RFC3339Nano), messages[1]["time"])
+}
+
+func TestLevels_ServeHTTP(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	levels := sloglogger.NewLevels(slog.LevelInfo)
+	server := httptest.NewServer(levels)
+	defer server.Close()
+
+	client := &http.Client{Timeout: time.Second}
+	do := func(method, query string) (int, string) {
+		req, err := http.NewRequest(method, server.URL+query, http.NoBody)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	code, _ := do(http.MethodPut, "?level=debug&package=a/b")
+	require.Equal(t, http.StatusOK, code)
+	code, _ = do(http.MethodPut, "?level=trace&id=conn-1")
+	require.Equal(t, http.StatusOK, code)
+	code, _ = do(http.MethodPut, "?level=warn")
+	require.Equal(t, http.StatusOK, code)
+	code, _ = do(http.MethodPut, "?level=verbose")
+	require.Equal(t, http.StatusBadRequest, code)
+
+	require.Equal(t, slog.LevelDebug, levels.Level("a/b/c", ""))
+	require.Equal(t, slog.LevelWarn, levels.Level("a/bc", ""))
+	require.Equal(t, sloglogger.LevelTrace, levels.Level("a/b", "conn-1"))
+	require.Equal(t, sloglogger.LevelTrace, levels.Minimum())
+
+	code, body := do(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, code)
+	require.JSONEq(t, `{"default":"WARN","packages":{"a/b":"DEBUG"},"connections":{"conn-1":"TRACE"}}`, body)
+
+	code, _ = do(http.MethodDelete, "?id=conn-1")
+	require.Equal(t, http.StatusOK, code)
+	code, _ = do(http.MethodDelete, "")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, slog.LevelDebug, levels.Minimum())
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchutils
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+)
+
+type operator int
+
+const (
+	opExists operator = iota
+	opDoesNotExist
+	opEquals
+	opNotEquals
+	opIn
+	opNotIn
+)
+
+type requirement struct {
+	key    string
+	op     operator
+	values []string
+}
+
+func (r *requirement) matches(labels map[string]string) bool {
+	value, ok := labels[r.key]
+	switch r.op {
+	case opExists:
+		return ok
+	case opDoesNotExist:
+		return !ok
+	case opEquals, opIn:
+		return ok && r.has(value)
+	case opNotEquals, opNotIn:
+		return !ok || !r.has(value)
+	}
+	return false
+}
+
+func (r *requirement) has(value string) bool {
+	for _, v := range r.values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Selector is a parsed label selector expression. An empty Selector matches everything.
+//
+// Supported requirements, separated by commas:
+//   - key            - label exists
+//   - !key           - label doesn't exist
+//   - key=value      - label equals value (key==value is accepted too)
+//   - key!=value     - label doesn't exist or doesn't equal value
+//   - key in (a,b)   - label equals one of the values
+//   - key notin (a,b) - label doesn't exist or equals none of the values
+type Selector struct {
+	requirements []*requirement
+}
+
+// ParseSelector parses label selector expression
+func ParseSelector(s string) (*Selector, error) {
+	parts, err := splitRequirements(s)
+	if err != nil {
+		return nil, err
+	}
+	selector := &Selector{}
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		r, err := parseRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		selector.requirements = append(selector.requirements, r)
+	}
+	return selector, nil
+}
+
+// Empty returns true if selector has no requirements
+func (s *Selector) Empty() bool {
+	return s == nil || len(s.requirements) == 0
+}
+
+// Matches returns true if labels satisfy all selector requirements
+func (s *Selector) Matches(labels map[string]string) bool {
+	if s == nil {
+		return true
+	}
+	for _, r := range s.requirements {
+		if !r.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesNetworkServiceEndpoint returns true if labels of at least one network service of the nse satisfy the selector.
+// If serviceNames are passed, only labels of these network services are checked.
+func (s *Selector) MatchesNetworkServiceEndpoint(nse *registry.NetworkServiceEndpoint, serviceNames ...string) bool {
+	if s.Empty() {
+		return true
+	}
+	if len(serviceNames) == 0 {
+		serviceNames = nse.GetNetworkServiceNames()
+		for name := range nse.GetNetworkServiceLabels() {
+			serviceNames = append(serviceNames, name)
+		}
+	}
+	if len(serviceNames) == 0 {
+		return s.Matches(nil)
+	}
+	for _, name := range serviceNames {
+		if s.Matches(nse.GetNetworkServiceLabels()[name].GetLabels()) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns canonical form of the selector
+func (s *Selector) String() string {
+	if s == nil {
+		return ""
+	}
+	var parts []string
+	for _, r := range s.requirements {
+		switch r.op {
+		case opExists:
+			parts = append(parts, r.key)
+		case opDoesNotExist:
+			parts = append(parts, "!"+r.key)
+		case opEquals:
+			parts = append(parts, r.key+"="+r.values[0])
+		case opNotEquals:
+			parts = append(parts, r.key+"!="+r.values[0])
+		case opIn:
+			parts = append(parts, r.key+" in ("+strings.Join(r.values, ",")+")")
+		case opNotIn:
+			parts = append(parts, r.key+" notin ("+strings.Join(r.values, ",")+")")
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func splitRequirements(s string) ([]string, error) {
+	var result []string
+	var depth, start int
+	for i, c := range s {
+		switch c {
+		case '(':
+			if depth++; depth > 1 {
+				return nil, errors.Errorf("nested parentheses in selector: %q", s)
+			}
+		case ')':
+			if depth--; depth < 0 {
+				return nil, errors.Errorf("unbalanced parentheses in selector: %q", s)
+			}
+		case ',':
+			if depth == 0 {
+				result = append(result, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, errors.Errorf("unbalanced parentheses in selector: %q", s)
+	}
+	return append(result, s[start:]), nil
+}
+
+func parseRequirement(s string) (*requirement, error) {
+	if strings.HasPrefix(s, "!") {
+		key := strings.TrimSpace(s[1:])
+		if err := validateKey(key); err != nil {
+			return nil, err
+		}
+		return &requirement{key: key, op: opDoesNotExist}, nil
+	}
+	if i := strings.Index(s, "!="); i >= 0 {
+		return newRequirement(s[:i], opNotEquals, s[i+2:])
+	}
+	if i := strings.Index(s, "=="); i >= 0 {
+		return newRequirement(s[:i], opEquals, s[i+2:])
+	}
+	if i := strings.Index(s, "="); i >= 0 {
+		return newRequirement(s[:i], opEquals, s[i+1:])
+	}
+	if fields := strings.Fields(s); len(fields) > 1 {
+		rest := strings.TrimSpace(strings.TrimPrefix(s, fields[0]))
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, fields[1]))
+		switch fields[1] {
+		case "in":
+			return newSetRequirement(fields[0], opIn, rest)
+		case "notin":
+			return newSetRequirement(fields[0], opNotIn, rest)
+		}
+		return nil, errors.Errorf("invalid selector requirement: %q", s)
+	}
+	if err := validateKey(s); err != nil {
+		return nil, err
+	}
+	return &requirement{key: s, op: opExists}, nil
+}
+
+func newRequirement(key string, op operator, value string) (*requirement, error) {
+	key = strings.TrimSpace(key)
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	return &requirement{key: key, op: op, values: []string{strings.TrimSpace(value)}}, nil
+}
+
+func newSetRequirement(key string, op operator, set string) (*requirement, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(set, "(") || !strings.HasSuffix(set, ")") {
+		return nil, errors.Errorf("invalid set of values for key %q: %q", key, set)
+	}
+	var values []string
+	for _, v := range strings.Split(set[1:len(set)-1], ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return nil, errors.Errorf("empty set of values for key %q", key)
+	}
+	sort.Strings(values)
+	return &requirement{key: key, op: op, values: values}, nil
+}
+
+func validateKey(key string) error {
+	if key == "" || strings.ContainsAny(key, " \t!=(),") {
+		return errors.Errorf("invalid selector key: %q", key)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchutils_test
+
+import (
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
+)
+
+func TestSelector_Matches(t *testing.T) {
+	labels := map[string]string{
+		"app":  "firewall",
+		"zone": "a",
+	}
+
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{selector: "", want: true},
+		{selector: "app", want: true},
+		{selector: "!app", want: false},
+		{selector: "!tier", want: true},
+		{selector: "app=firewall", want: true},
+		{selector: "app==firewall", want: true},
+		{selector: "app!=firewall", want: false},
+		{selector: "tier!=backend", want: true},
+		{selector: "zone in (a, b)", want: true},
+		{selector: "zone in (b,c)", want: false},
+		{selector: "zone notin (b,c)", want: true},
+		{selector: "tier notin (b)", want: true},
+		{selector: "app=firewall,zone in (a,b),!tier", want: true},
+		{selector: "app=firewall, zone notin (a)", want: false},
+	}
+
+	for _, tc := range tests {
+		selector, err := matchutils.ParseSelector(tc.selector)
+		require.NoError(t, err, tc.selector)
+		require.Equal(t, tc.want, selector.Matches(labels), tc.selector)
+	}
+}
+
+func TestSelector_ParseInvalid(t *testing.T) {
+	for _, s := range []string{
+		"zone in a,b",
+		"zone in ()",
+		"zone between (a,b)",
+		"=value",
+		"!",
+		"zone in (a,b",
+		"zone in a,b)",
+		"zone in ((a),b)",
+		"app = x), zone in (a",
+	} {
+		_, err := matchutils.ParseSelector(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestSelector_String(t *testing.T) {
+	selector, err := matchutils.ParseSelector("app = firewall, zone in (b, a), !tier")
+	require.NoError(t, err)
+	require.Equal(t, "app=firewall,zone in (a,b),!tier", selector.String())
+}
+
+func TestSelector_MatchesNetworkServiceEndpoint(t *testing.T) {
+	nse := &registry.NetworkServiceEndpoint{
+		Name:                "nse",
+		NetworkServiceNames: []string{"ns-1", "ns-2"},
+		NetworkServiceLabels: map[string]*registry.NetworkServiceLabels{
+			"ns-1": {Labels: map[string]string{"app": "firewall"}},
+			"ns-2": {Labels: map[string]string{"app": "vpn"}},
+		},
+	}
+
+	selector, err := matchutils.ParseSelector("app=vpn")
+	require.NoError(t, err)
+
+	require.True(t, selector.MatchesNetworkServiceEndpoint(nse))
+	require.True(t, selector.MatchesNetworkServiceEndpoint(nse, "ns-2"))
+	require.False(t, selector.MatchesNetworkServiceEndpoint(nse, "ns-1"))
+	require.False(t, selector.MatchesNetworkServiceEndpoint(&registry.NetworkServiceEndpoint{Name: "nse"}))
+}
@@ -19,10 +19,13 @@ package authorize
 import (
 	"github.com/edwarnicke/genericsync"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
 )
 
 type options struct {
 	policies              policiesList
+	revocationList        *revocation.List
 	spiffeIDConnectionMap *genericsync.Map[spiffeid.ID, *genericsync.Map[string, struct{}]]
 }
 
@@ -41,6 +44,14 @@ func WithPolicies(p ...Policy) Option {
 	}
 }
 
+// WithRevocationList sets list of revoked SPIFFE IDs. Monitor streams of the revoked peers are rejected and the
+// already opened streams are closed once the peer is revoked.
+func WithRevocationList(list *revocation.List) Option {
+	return func(o *options) {
+		o.revocationList = list
+	}
+}
+
 // WithSpiffeIDConnectionMap sets map to keep spiffeIDConnectionMap to authorize connections with MonitorServer
 func WithSpiffeIDConnectionMap(s *genericsync.Map[spiffeid.ID, *genericsync.Map[string, struct{}]]) Option {
 	return func(o *options) {
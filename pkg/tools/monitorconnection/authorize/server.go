@@ -18,16 +18,21 @@
 package authorize
 
 import (
+	"context"
+
 	"github.com/edwarnicke/genericsync"
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 
 	"github.com/networkservicemesh/sdk/pkg/tools/monitorconnection/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
 	"github.com/networkservicemesh/sdk/pkg/tools/spire"
 )
 
 type authorizeMonitorConnectionsServer struct {
 	policies              policiesList
+	revocationList        *revocation.List
 	spiffeIDConnectionMap *genericsync.Map[spiffeid.ID, *genericsync.Map[string, struct{}]]
 }
 
@@ -42,6 +47,7 @@ func NewMonitorConnectionServer(opts ...Option) networkservice.MonitorConnection
 	}
 	var s = &authorizeMonitorConnectionsServer{
 		policies:              o.policies,
+		revocationList:        o.revocationList,
 		spiffeIDConnectionMap: o.spiffeIDConnectionMap,
 	}
 	return s
@@ -86,5 +92,43 @@ func (a *authorizeMonitorConnectionsServer) MonitorConnections(in *networkservic
 		return err
 	}
 
-	return next.MonitorConnectionServer(ctx).MonitorConnections(in, srv)
+	if a.revocationList == nil {
+		return next.MonitorConnectionServer(ctx).MonitorConnections(in, srv)
+	}
+	return a.monitorRevocable(spiffeID, in, srv)
+}
+
+// monitorRevocable closes the monitor stream once the peer SPIFFE ID is revoked
+func (a *authorizeMonitorConnectionsServer) monitorRevocable(spiffeID spiffeid.ID, in *networkservice.MonitorScopeSelector, srv networkservice.MonitorConnection_MonitorConnectionsServer) error {
+	if err := a.revocationList.CheckSpiffeID(spiffeID); err != nil {
+		return errors.Wrap(err, "monitor: peer is revoked")
+	}
+
+	ctx, cancel := context.WithCancel(srv.Context())
+	defer cancel()
+
+	unsubscribe := a.revocationList.Subscribe(func() {
+		if a.revocationList.CheckSpiffeID(spiffeID) != nil {
+			cancel()
+		}
+	})
+	defer unsubscribe()
+
+	err := next.MonitorConnectionServer(ctx).MonitorConnections(in, &revocableMonitorConnectionsServer{
+		MonitorConnection_MonitorConnectionsServer: srv,
+		ctx: ctx,
+	})
+	if revokedErr := a.revocationList.CheckSpiffeID(spiffeID); revokedErr != nil {
+		return errors.Wrap(revokedErr, "monitor: peer is revoked")
+	}
+	return err
+}
+
+type revocableMonitorConnectionsServer struct {
+	networkservice.MonitorConnection_MonitorConnectionsServer
+	ctx context.Context
+}
+
+func (s *revocableMonitorConnectionsServer) Context() context.Context {
+	return s.ctx
 }
@@ -35,7 +35,9 @@ import (
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 
 	"github.com/networkservicemesh/sdk/pkg/tools/monitorconnection/authorize"
+	"github.com/networkservicemesh/sdk/pkg/tools/monitorconnection/next"
 	"github.com/networkservicemesh/sdk/pkg/tools/opa"
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 
@@ -246,3 +248,45 @@ func TestAuthorize_ShouldCorrectlyWorkWithHeal(t *testing.T) {
 		selector, &testEmptyMCMCServer{context: ctx})
 	require.NoError(t, err)
 }
+
+type waitMonitorConnectionServer struct{}
+
+func (s *waitMonitorConnectionServer) MonitorConnections(_ *networkservice.MonitorScopeSelector, srv networkservice.MonitorConnection_MonitorConnectionsServer) error {
+	<-srv.Context().Done()
+	return nil
+}
+
+func TestAuthorize_RevocationList(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	peerCtx, err := getContextWithTLSCert()
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(peerCtx, time.Second)
+	defer cancel()
+
+	list := revocation.NewList(nil)
+	server := next.NewMonitorConnectionServer(
+		authorize.NewMonitorConnectionServer(authorize.Any(), authorize.WithRevocationList(list)),
+		new(waitMonitorConnectionServer),
+	)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.MonitorConnections(new(networkservice.MonitorScopeSelector), &testEmptyMCMCServer{context: ctx})
+	}()
+
+	// Revoked peer stream is closed
+	list.Update(&revocation.Entries{SpiffeIDs: []string{spiffeID2}})
+	require.Never(t, func() bool { return len(errCh) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+
+	list.Update(&revocation.Entries{SpiffeIDs: []string{spiffeID1}})
+	select {
+	case <-ctx.Done():
+		require.FailNow(t, "monitor stream is not closed")
+	case err = <-errCh:
+		require.ErrorIs(t, err, revocation.ErrRevoked)
+	}
+
+	// Revoked peer can't open a new stream
+	err = server.MonitorConnections(new(networkservice.MonitorScopeSelector), &testEmptyMCMCServer{context: ctx})
+	require.ErrorIs(t, err, revocation.ErrRevoked)
+}
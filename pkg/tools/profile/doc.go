@@ -0,0 +1,31 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package profile measures the time spent by the chain elements.
+//
+// Profiling is disabled by default and is enabled with Enable. When enabled, next chains measure every element call:
+// total time is the time of the whole call and self time is the total time without the time spent in the rest of the
+// chain. Both are recorded to OpenTelemetry histograms named after the element method, e.g.
+// "sdk/pkg/networkservice/common/begin/beginServer.Request.total" and ".self".
+//
+// A single call can be profiled in detail with WithProfile:
+//
+//	ctx, p := profile.WithProfile(ctx)
+//	conn, err := server.Request(ctx, request)
+//	fmt.Print(p)
+//
+// The Profile is printed in the folded stacks format accepted by flamegraph.pl and speedscope.
+package profile
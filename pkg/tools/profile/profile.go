@@ -0,0 +1,198 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/edwarnicke/genericsync"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/typeutils"
+)
+
+// skippedPackages contain the elements used by the tracing wrappers, they are not profiled
+var skippedPackages = []string{
+	"/core/trace/traceverbose",
+	"/core/trace/traceconcise",
+}
+
+var (
+	isEnabled int32
+
+	names      genericsync.Map[nameKey, string]
+	histograms genericsync.Map[string, metric.Float64Histogram]
+)
+
+type nameKey struct {
+	typ    reflect.Type
+	method string
+}
+
+type frameKey struct{}
+
+type profileKey struct{}
+
+type frame struct {
+	parent *frame
+	start  time.Time
+	stack  string
+	// nested is the time in nanoseconds spent in the nested frames
+	nested int64
+	done   int32
+}
+
+// Profile is the self time of the elements collected for a single call
+type Profile struct {
+	mu      sync.Mutex
+	samples map[string]time.Duration
+}
+
+// IsEnabled - checks if profiling is enabled
+func IsEnabled() bool {
+	return atomic.LoadInt32(&isEnabled) != 0
+}
+
+// Enable - enables/disables profiling
+func Enable(enable bool) {
+	if enable {
+		atomic.StoreInt32(&isEnabled, 1)
+		return
+	}
+	atomic.StoreInt32(&isEnabled, 0)
+}
+
+// WithProfile returns a context collecting the self time of the elements called with it into the returned Profile
+func WithProfile(ctx context.Context) (context.Context, *Profile) {
+	p := &Profile{samples: make(map[string]time.Duration)}
+	return context.WithValue(ctx, profileKey{}, p), p
+}
+
+// Start starts measuring the element method call and returns the context for the call and the function ending it.
+// Calls to the elements with this context are measured as nested calls.
+func Start(ctx context.Context, element interface{}, method string) (context.Context, func()) {
+	if !IsEnabled() {
+		return ctx, func() {}
+	}
+	name := funcName(element, method)
+	if name == "" {
+		return ctx, func() {}
+	}
+
+	f := &frame{stack: name}
+	p, _ := ctx.Value(profileKey{}).(*Profile)
+	if parent, ok := ctx.Value(frameKey{}).(*frame); ok {
+		if atomic.LoadInt32(&parent.done) == 0 {
+			f.parent = parent
+			f.stack = parent.stack + ";" + name
+		} else {
+			// Context of the ended call is reused, e.g. by the refreshes started by begin, so the call is not profiled
+			// as the part of it
+			p = nil
+		}
+	}
+
+	clk := clock.FromContext(ctx)
+	f.start = clk.Now()
+	return context.WithValue(ctx, frameKey{}, f), func() {
+		total := clk.Since(f.start)
+		self := total - time.Duration(atomic.LoadInt64(&f.nested))
+		atomic.StoreInt32(&f.done, 1)
+		if f.parent != nil {
+			atomic.AddInt64(&f.parent.nested, int64(total))
+		}
+
+		record(ctx, name+".total", total)
+		record(ctx, name+".self", self)
+		if p != nil {
+			p.add(f.stack, self)
+		}
+	}
+}
+
+// String returns the profile in the folded stacks format: one "element;nested element;... self time" line per
+// stack, the time is in nanoseconds
+func (p *Profile) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stacks := make([]string, 0, len(p.samples))
+	for stack := range p.samples {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+
+	var sb strings.Builder
+	for _, stack := range stacks {
+		_, _ = fmt.Fprintf(&sb, "%s %d\n", stack, p.samples[stack].Nanoseconds())
+	}
+	return sb.String()
+}
+
+func (p *Profile) add(stack string, self time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.samples[stack] += self
+}
+
+func funcName(element interface{}, method string) string {
+	key := nameKey{typ: reflect.TypeOf(element), method: method}
+	if name, ok := names.Load(key); ok {
+		return name
+	}
+	var name string
+	if !skipped(key.typ) {
+		name = typeutils.GetFuncName(element, method)
+	}
+	names.Store(key, name)
+	return name
+}
+
+func skipped(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for _, suffix := range skippedPackages {
+		if strings.HasSuffix(t.PkgPath(), suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func record(ctx context.Context, name string, d time.Duration) {
+	histogram, ok := histograms.Load(name)
+	if !ok {
+		var err error
+		histogram, err = otel.Meter("").Float64Histogram(name, metric.WithUnit("s"))
+		if err != nil {
+			return
+		}
+		histogram, _ = histograms.LoadOrStore(name, histogram)
+	}
+	histogram.Record(ctx, d.Seconds())
+}
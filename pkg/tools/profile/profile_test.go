@@ -0,0 +1,226 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	registrychain "github.com/networkservicemesh/sdk/pkg/registry/core/chain"
+	registrynext "github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/streamchannel"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/profile"
+)
+
+const (
+	firstRequest  = "sdk/pkg/tools/profile_test/firstServer.Request"
+	secondRequest = "sdk/pkg/tools/profile_test/secondServer.Request"
+)
+
+type sleep struct {
+	clk           *clockmock.Mock
+	before, after time.Duration
+	ctx           context.Context
+}
+
+func (s *sleep) request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.ctx = ctx
+	s.clk.Add(s.before)
+	conn, err := next.Server(ctx).Request(ctx, request)
+	s.clk.Add(s.after)
+	return conn, err
+}
+
+func (s *sleep) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+type firstServer struct {
+	sleep
+}
+
+func (s *firstServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return s.request(ctx, request)
+}
+
+type secondServer struct {
+	sleep
+}
+
+func (s *secondServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return s.request(ctx, request)
+}
+
+func enable(t *testing.T) {
+	profile.Enable(true)
+	t.Cleanup(func() { profile.Enable(false) })
+}
+
+func TestProfile(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	enable(t)
+
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(sdkmetric.NewMeterProvider()) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := clockmock.New(ctx)
+	ctx = clock.WithClock(ctx, clk)
+
+	for _, tracing := range []bool{false, true} {
+		log.EnableTracing(tracing)
+		logrus.SetLevel(logrus.TraceLevel)
+
+		server := chain.NewNetworkServiceServer(
+			&firstServer{sleep{clk: clk, before: time.Millisecond, after: 2 * time.Millisecond}},
+			chain.NewNetworkServiceServer(
+				&secondServer{sleep{clk: clk, before: 4 * time.Millisecond}},
+			),
+		)
+
+		profileCtx, p := profile.WithProfile(ctx)
+		_, err := server.Request(profileCtx, new(networkservice.NetworkServiceRequest))
+		require.NoError(t, err)
+
+		require.Equal(t, firstRequest+" 3000000\n"+firstRequest+";"+secondRequest+" 4000000\n", p.String())
+	}
+	log.EnableTracing(false)
+	logrus.SetLevel(logrus.InfoLevel)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	sums := make(map[string]float64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			histogram, ok := m.Data.(metricdata.Histogram[float64])
+			require.True(t, ok)
+			require.Len(t, histogram.DataPoints, 1)
+			require.EqualValues(t, 2, histogram.DataPoints[0].Count)
+			sums[m.Name] = histogram.DataPoints[0].Sum
+		}
+	}
+	require.Len(t, sums, 4)
+	require.InDelta(t, 0.014, sums[firstRequest+".total"], 1e-9)
+	require.InDelta(t, 0.006, sums[firstRequest+".self"], 1e-9)
+	require.InDelta(t, 0.008, sums[secondRequest+".total"], 1e-9)
+	require.InDelta(t, 0.008, sums[secondRequest+".self"], 1e-9)
+}
+
+func TestProfile_Disabled(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := clockmock.New(ctx)
+	server := next.NewNetworkServiceServer(&firstServer{sleep{clk: clk, before: time.Millisecond}})
+
+	ctx, p := profile.WithProfile(clock.WithClock(ctx, clk))
+	_, err := server.Request(ctx, new(networkservice.NetworkServiceRequest))
+	require.NoError(t, err)
+	require.Empty(t, p.String())
+}
+
+func TestProfile_EndedCall(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	enable(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := clockmock.New(ctx)
+	first := &firstServer{sleep{clk: clk, before: time.Millisecond}}
+	second := &secondServer{sleep{clk: clk, before: 2 * time.Millisecond}}
+	server := next.NewNetworkServiceServer(first)
+
+	ctx, p := profile.WithProfile(clock.WithClock(ctx, clk))
+	_, err := server.Request(ctx, new(networkservice.NetworkServiceRequest))
+	require.NoError(t, err)
+
+	// Calls with the context of the ended call, e.g. refreshes, are not the part of its profile
+	_, err = next.NewNetworkServiceServer(second).Request(first.ctx, new(networkservice.NetworkServiceRequest))
+	require.NoError(t, err)
+
+	require.Equal(t, firstRequest+" 1000000\n", p.String())
+}
+
+type registerServer struct {
+	clk *clockmock.Mock
+}
+
+func (s *registerServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	s.clk.Add(time.Millisecond)
+	return registrynext.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
+}
+
+func (s *registerServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	s.clk.Add(time.Millisecond)
+	return registrynext.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, server)
+}
+
+func (s *registerServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
+	return registrynext.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, nse)
+}
+
+func TestProfile_Registry(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	enable(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := clockmock.New(ctx)
+	server := registrychain.NewNetworkServiceEndpointRegistryServer(
+		&registerServer{clk: clk},
+		&registerServer{clk: clk},
+	)
+
+	ctx, p := profile.WithProfile(clock.WithClock(ctx, clk))
+	_, err := server.Register(ctx, new(registry.NetworkServiceEndpoint))
+	require.NoError(t, err)
+
+	const register = "sdk/pkg/tools/profile_test/registerServer.Register"
+	require.Equal(t, register+" 1000000\n"+register+";"+register+" 1000000\n", p.String())
+
+	// Watch Finds last as long as their streams, they are not measured
+	findServer := streamchannel.NewNetworkServiceEndpointFindServer(ctx, make(chan *registry.NetworkServiceEndpointResponse))
+	require.NoError(t, server.Find(&registry.NetworkServiceEndpointQuery{Watch: true}, findServer))
+	require.Equal(t, register+" 1000000\n"+register+";"+register+" 1000000\n", p.String())
+
+	require.NoError(t, server.Find(new(registry.NetworkServiceEndpointQuery), findServer))
+	const find = "sdk/pkg/tools/profile_test/registerServer.Find"
+	require.Contains(t, p.String(), find+";"+find+" 1000000\n")
+}
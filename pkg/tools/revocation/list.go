@@ -0,0 +1,170 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package revocation provides a list of revoked tokens and SPIFFE IDs checked by the authorize chain elements
+package revocation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+)
+
+// ErrRevoked is returned when a token or a SPIFFE ID is revoked
+var ErrRevoked = errors.New("revoked")
+
+// Entries is a set of revoked credentials. Token is revoked if its "jti" claim, its "sub" claim or its hash is listed.
+type Entries struct {
+	JTIs        []string `json:"jtis,omitempty"`
+	SpiffeIDs   []string `json:"spiffe_ids,omitempty"`
+	TokenHashes []string `json:"token_hashes,omitempty"`
+}
+
+// TokenHash returns hex encoded SHA-256 of the token used in Entries.TokenHashes
+func TokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// List is a concurrently updated revocation list. Subscribers are notified on every update, so the already established
+// connections and registrations can be cut off once their credentials are revoked.
+type List struct {
+	mu          sync.RWMutex
+	jtis        map[string]struct{}
+	spiffeIDs   map[string]struct{}
+	tokenHashes map[string]struct{}
+
+	subscribersMu sync.Mutex
+	subscribers   map[uint64]func()
+	nextID        uint64
+}
+
+// NewList creates a new List with the initial entries
+func NewList(entries *Entries) *List {
+	l := &List{
+		subscribers: make(map[uint64]func()),
+	}
+	l.set(entries)
+	return l
+}
+
+// Update replaces the revoked entries and notifies the subscribers
+func (l *List) Update(entries *Entries) {
+	l.set(entries)
+
+	l.subscribersMu.Lock()
+	subscribers := make([]func(), 0, len(l.subscribers))
+	for _, subscriber := range l.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	l.subscribersMu.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber()
+	}
+}
+
+// Subscribe adds callback called after every Update. Returned function removes the callback.
+func (l *List) Subscribe(callback func()) (unsubscribe func()) {
+	l.subscribersMu.Lock()
+	defer l.subscribersMu.Unlock()
+
+	id := l.nextID
+	l.nextID++
+	l.subscribers[id] = callback
+
+	return func() {
+		l.subscribersMu.Lock()
+		defer l.subscribersMu.Unlock()
+
+		delete(l.subscribers, id)
+	}
+}
+
+// CheckSpiffeID returns ErrRevoked if the SPIFFE ID is revoked
+func (l *List) CheckSpiffeID(id spiffeid.ID) error {
+	if l == nil || id.IsZero() {
+		return nil
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if _, ok := l.spiffeIDs[id.String()]; ok {
+		return errors.Wrapf(ErrRevoked, "spiffe id %s", id.String())
+	}
+	return nil
+}
+
+// CheckTokens returns ErrRevoked if any of the tokens is revoked. Tokens are not verified, the claims are only used
+// to find the token in the list.
+func (l *List) CheckTokens(tokens ...string) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, tok := range tokens {
+		if tok == "" {
+			continue
+		}
+		if _, ok := l.tokenHashes[TokenHash(tok)]; ok {
+			return errors.Wrap(ErrRevoked, "token hash")
+		}
+
+		claims := jwt.MapClaims{}
+		if _, _, err := jwt.NewParser().ParseUnverified(tok, &claims); err != nil {
+			continue
+		}
+		if jti, ok := claims["jti"].(string); ok {
+			if _, revoked := l.jtis[jti]; revoked {
+				return errors.Wrapf(ErrRevoked, "token id %s", jti)
+			}
+		}
+		if sub, ok := claims["sub"].(string); ok {
+			if _, revoked := l.spiffeIDs[sub]; revoked {
+				return errors.Wrapf(ErrRevoked, "token subject %s", sub)
+			}
+		}
+	}
+	return nil
+}
+
+func (l *List) set(entries *Entries) {
+	jtis, spiffeIDs, tokenHashes := toSet(nil), toSet(nil), toSet(nil)
+	if entries != nil {
+		jtis, spiffeIDs, tokenHashes = toSet(entries.JTIs), toSet(entries.SpiffeIDs), toSet(entries.TokenHashes)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.jtis, l.spiffeIDs, l.tokenHashes = jtis, spiffeIDs, tokenHashes
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
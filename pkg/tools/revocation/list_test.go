@@ -0,0 +1,109 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revocation_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/revocation"
+)
+
+func token(t *testing.T, jti, sub string) string {
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"jti": jti, "sub": sub}).SignedString([]byte("key"))
+	require.NoError(t, err)
+	return tok
+}
+
+func TestList_CheckTokens(t *testing.T) {
+	tok := token(t, "id-1", "spiffe://test.com/nsc")
+
+	list := revocation.NewList(nil)
+	require.NoError(t, list.CheckTokens(tok))
+
+	for _, entries := range []*revocation.Entries{
+		{JTIs: []string{"id-1"}},
+		{SpiffeIDs: []string{"spiffe://test.com/nsc"}},
+		{TokenHashes: []string{revocation.TokenHash(tok)}},
+	} {
+		list.Update(entries)
+		err := list.CheckTokens("", token(t, "id-2", "spiffe://test.com/nse"), tok)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, revocation.ErrRevoked))
+	}
+
+	list.Update(nil)
+	require.NoError(t, list.CheckTokens(tok))
+}
+
+func TestList_CheckSpiffeID(t *testing.T) {
+	list := revocation.NewList(&revocation.Entries{SpiffeIDs: []string{"spiffe://test.com/nsc"}})
+
+	require.Error(t, list.CheckSpiffeID(spiffeid.RequireFromString("spiffe://test.com/nsc")))
+	require.NoError(t, list.CheckSpiffeID(spiffeid.RequireFromString("spiffe://test.com/nse")))
+	require.NoError(t, list.CheckSpiffeID(spiffeid.ID{}))
+
+	var nilList *revocation.List
+	require.NoError(t, nilList.CheckSpiffeID(spiffeid.RequireFromString("spiffe://test.com/nsc")))
+}
+
+func TestList_Subscribe(t *testing.T) {
+	list := revocation.NewList(nil)
+
+	var updates int32
+	unsubscribe := list.Subscribe(func() { atomic.AddInt32(&updates, 1) })
+
+	list.Update(&revocation.Entries{JTIs: []string{"id-1"}})
+	require.Equal(t, int32(1), atomic.LoadInt32(&updates))
+
+	unsubscribe()
+	list.Update(nil)
+	require.Equal(t, int32(1), atomic.LoadInt32(&updates))
+}
+
+func TestWatchFile(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	filePath := filepath.Join(t.TempDir(), "revoked.yaml")
+	tok := token(t, "id-1", "spiffe://test.com/nsc")
+
+	list := revocation.NewList(nil)
+	revocation.WatchFile(ctx, filePath, list)
+
+	require.NoError(t, os.WriteFile(filePath, []byte("jtis:\n- id-1\n"), os.ModePerm))
+	require.Eventually(t, func() bool {
+		return list.CheckTokens(tok) != nil
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.Remove(filePath))
+	require.Eventually(t, func() bool {
+		return list.CheckTokens(tok) == nil
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revocation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// WatchFile updates the list with the YAML or JSON encoded Entries from the file on every file change until the context
+// is done. Missing or removed file clears the list.
+func WatchFile(ctx context.Context, filePath string, list *List) {
+	logger := log.FromContext(ctx).WithField("revocation", "WatchFile")
+	filePath = filepath.Clean(filePath)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.Errorf("failed to watch %s: %s", filePath, err.Error())
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err == nil {
+		err = watcher.Add(filepath.Dir(filePath))
+	}
+	if err != nil {
+		_ = watcher.Close()
+		logger.Errorf("failed to watch %s: %s", filePath, err.Error())
+		return
+	}
+
+	updateCh := make(chan *Entries)
+	go func() {
+		defer close(updateCh)
+		defer func() { _ = watcher.Close() }()
+
+		for {
+			if entries, err := readEntries(filePath); err != nil {
+				logger.Errorf("%s", err.Error())
+			} else {
+				select {
+				case updateCh <- entries:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if !waitForChange(ctx, filePath, watcher) {
+				return
+			}
+		}
+	}()
+	Follow(ctx, list, updateCh)
+}
+
+// readEntries returns the entries from the file, no entries if the file doesn't exist
+func readEntries(filePath string) (*Entries, error) {
+	entries := new(Entries)
+	bytes, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", filePath)
+	}
+	if err := yaml.Unmarshal(bytes, entries); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", filePath)
+	}
+	return entries, nil
+}
+
+// waitForChange returns true once the file has been changed, false if the context is done or the watcher has failed
+func waitForChange(ctx context.Context, filePath string, watcher *fsnotify.Watcher) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case err, ok := <-watcher.Errors:
+			if ok {
+				log.FromContext(ctx).WithField("revocation", "WatchFile").Errorf("failed to watch %s: %s", filePath, err.Error())
+			}
+			return false
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return false
+			}
+			if filepath.Clean(event.Name) == filePath && event.Op != fsnotify.Chmod {
+				return true
+			}
+		}
+	}
+}
+
+// Follow updates the list with the entries pushed to the channel until the context is done or the channel is closed.
+// It is used to connect push sources, e.g. a gRPC stream of a central revocation service, to the list.
+func Follow(ctx context.Context, list *List, updateCh <-chan *Entries) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case entries, ok := <-updateCh:
+				if !ok {
+					return
+				}
+				list.Update(entries)
+			}
+		}
+	}()
+}
@@ -59,6 +59,7 @@ type Builder struct {
 	registryDefaultExpiration time.Duration
 
 	useUnixSockets bool
+	faults         *Faults
 
 	domain *Domain
 }
@@ -157,6 +158,12 @@ func (b *Builder) SetRegistryDefaultExpiration(d time.Duration) *Builder {
 	return b
 }
 
+// SetFaults sets the faults injected into the calls between the domain components
+func (b *Builder) SetFaults(faults *Faults) *Builder {
+	b.faults = faults
+	return b
+}
+
 // UseUnixSockets sets 1 node and mark it to use unix socket to listen on.
 func (b *Builder) UseUnixSockets() *Builder {
 	require.NotEqual(b.t, "windows", runtime.GOOS, "Unix sockets are not available for windows")
@@ -174,6 +181,7 @@ func (b *Builder) Build() *Domain {
 	b.domain = &Domain{
 		Name:        b.name,
 		DNSResolver: b.dnsResolver,
+		faults:      b.faults,
 	}
 
 	if b.useUnixSockets {
@@ -244,11 +252,12 @@ func (b *Builder) newRegistryProxy() *RegistryEntry {
 		URL: b.domain.supplyURL("reg-proxy"),
 	}
 	entry.restartableServer = newRestartableServer(b.ctx, b.t, entry.URL, func(ctx context.Context) {
+		ctx = b.domain.componentContext(ctx, b.domain.Name+"/registry-proxy", entry.URL)
 		entry.Registry = b.supplyRegistryProxy(
 			ctx,
 			b.generateTokenFunc,
 			b.dnsResolver,
-			proxydns.WithDialOptions(DialOptions(WithTokenGenerator(b.generateTokenFunc))...),
+			proxydns.WithDialOptions(b.domain.dialOptions(b.domain.Name+"/registry-proxy", b.generateTokenFunc)...),
 		)
 		serve(ctx, b.t, entry.URL, entry.Register)
 
@@ -272,12 +281,13 @@ func (b *Builder) newRegistry() *RegistryEntry {
 		URL: b.domain.supplyURL("reg"),
 	}
 	entry.restartableServer = newRestartableServer(b.ctx, b.t, entry.URL, func(ctx context.Context) {
+		ctx = b.domain.componentContext(ctx, b.domain.Name+"/registry", entry.URL)
 		entry.Registry = b.supplyRegistry(
 			ctx,
 			b.generateTokenFunc,
 			b.registryDefaultExpiration,
 			nsmgrProxyURL,
-			DialOptions(WithTokenGenerator(b.generateTokenFunc))...,
+			b.domain.dialOptions(b.domain.Name+"/registry", b.generateTokenFunc)...,
 		)
 		serve(ctx, b.t, entry.URL, entry.Register)
 
@@ -297,7 +307,8 @@ func (b *Builder) newNSMgrProxy() *NSMgrEntry {
 		URL:  b.domain.NSMgrProxy.URL,
 	}
 	entry.restartableServer = newRestartableServer(b.ctx, b.t, entry.URL, func(ctx context.Context) {
-		dialOptions := DialOptions(WithTokenGenerator(b.generateTokenFunc))
+		ctx = b.domain.componentContext(ctx, b.domain.Name+"/nsmgr-proxy", entry.URL)
+		dialOptions := b.domain.dialOptions(b.domain.Name+"/nsmgr-proxy", b.generateTokenFunc)
 		entry.Nsmgr = b.supplyNSMgrProxy(ctx,
 			CloneURL(b.domain.Registry.URL),
 			CloneURL(b.domain.RegistryProxy.URL),
@@ -316,9 +327,11 @@ func (b *Builder) newNSMgrProxy() *NSMgrEntry {
 
 func (b *Builder) newNode(nodeNum int) *Node {
 	node := &Node{
+		Name:       fmt.Sprintf("node-%d", nodeNum),
 		t:          b.t,
 		domain:     b.domain,
 		Forwarders: make(map[string]*EndpointEntry),
+		Endpoints:  make(map[string]*EndpointEntry),
 	}
 
 	b.setupNode(b.ctx, node, nodeNum)
@@ -27,6 +27,8 @@ import (
 
 type dialOpts struct {
 	tokenGenerator token.GeneratorFunc
+	faults         *Faults
+	component      string
 }
 
 // DialOption is an option pattern for DialOptions
@@ -39,6 +41,14 @@ func WithTokenGenerator(tokenGenerator token.GeneratorFunc) DialOption {
 	}
 }
 
+// WithFaults injects the faults into the calls made by the component, see Faults
+func WithFaults(faults *Faults, component string) DialOption {
+	return func(opts *dialOpts) {
+		opts.faults = faults
+		opts.component = component
+	}
+}
+
 // DialOptions is a helper method for building []grpc.DialOption for testing
 func DialOptions(options ...DialOption) []grpc.DialOption {
 	tokenResetCh := make(chan struct{})
@@ -51,7 +61,7 @@ func DialOptions(options ...DialOption) []grpc.DialOption {
 		o(opts)
 	}
 
-	return append([]grpc.DialOption{
+	dialOptions := append([]grpc.DialOption{
 		grpc.WithTransportCredentials(
 			grpcfdTransportCredentials(insecure.NewCredentials()),
 		),
@@ -63,4 +73,12 @@ func DialOptions(options ...DialOption) []grpc.DialOption {
 		WithInsecureRPCCredentials(),
 		WithInsecureStreamRPCCredentials(),
 	}, tracing.WithTracingDial()...)
+
+	if opts.faults != nil {
+		dialOptions = append(dialOptions,
+			grpc.WithChainUnaryInterceptor(opts.faults.unaryInterceptor(opts.component)),
+			grpc.WithChainStreamInterceptor(opts.faults.streamInterceptor(opts.component)),
+		)
+	}
+	return dialOptions
 }
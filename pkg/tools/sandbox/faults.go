@@ -0,0 +1,310 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+)
+
+// AnyComponent matches all components in the Faults methods
+const AnyComponent = "*"
+
+type faultRule struct {
+	a, b      string
+	partition bool
+	latency   time.Duration
+}
+
+type faultStream struct {
+	source, target string
+	cancel         context.CancelFunc
+}
+
+// Faults injects faults into the gRPC calls between the sandbox components.
+//
+// Components are identified by paths: "<domain>/registry", "<domain>/registry-proxy", "<domain>/nsmgr-proxy",
+// "<domain>/<node>/nsmgr", "<domain>/<node>/<forwarder or endpoint name>" and "<domain>/<node>/nsc" for the node
+// clients. A path in the Faults methods matches the component with the path and all the components nested into it,
+// e.g. "cluster1/node-0" matches all the components of the node. AnyComponent matches all the components.
+//
+// The faults are injected on the client side, so only the calls made with DialOptions containing WithFaults are
+// affected, the components built by the Builder with SetFaults use them.
+type Faults struct {
+	ctx        context.Context
+	mockClocks bool
+
+	mu         sync.Mutex
+	components map[string]string
+	rules      []*faultRule
+	streams    map[*faultStream]struct{}
+	clocks     map[string]*clockmock.Mock
+}
+
+// NewFaults returns new Faults. Mocked clocks of the components stop running when ctx is done.
+func NewFaults(ctx context.Context) *Faults {
+	return &Faults{
+		ctx:        ctx,
+		components: make(map[string]string),
+		streams:    make(map[*faultStream]struct{}),
+		clocks:     make(map[string]*clockmock.Mock),
+	}
+}
+
+// MockClocks makes the components started after the call use clockmock clocks running with the real speed, so the
+// clocks can be skewed with SkewClock
+func (f *Faults) MockClocks() *Faults {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.mockClocks = true
+	return f
+}
+
+// Partition fails all the calls between the components a and b with codes.Unavailable and drops the streams opened
+// between them
+func (f *Faults) Partition(a, b string) {
+	f.addRule(&faultRule{a: a, b: b, partition: true})
+	f.DropStreams(a, b)
+}
+
+// AddLatency delays the calls and the stream messages between the components a and b
+func (f *Faults) AddLatency(a, b string, latency time.Duration) {
+	f.addRule(&faultRule{a: a, b: b, latency: latency})
+}
+
+// Heal removes the partitions and the latencies between the components a and b
+func (f *Faults) Heal(a, b string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rules := f.rules[:0]
+	for _, rule := range f.rules {
+		if !(rule.a == a && rule.b == b || rule.a == b && rule.b == a) {
+			rules = append(rules, rule)
+		}
+	}
+	f.rules = rules
+}
+
+// HealAll removes all the partitions and the latencies
+func (f *Faults) HealAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rules = nil
+}
+
+// DropStreams cancels the streams currently opened between the components a and b
+func (f *Faults) DropStreams(a, b string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for s := range f.streams {
+		if matchPair(a, b, s.source, s.target) {
+			s.cancel()
+			delete(f.streams, s)
+		}
+	}
+}
+
+// SkewClock moves the clocks of the components forward by d. The components should be started after MockClocks.
+func (f *Faults) SkewClock(component string, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for path, clk := range f.clocks {
+		if match(component, path) {
+			clk.Add(d)
+		}
+	}
+}
+
+// Clock returns the mocked clock of the component, or nil if the component doesn't use the mocked clock
+func (f *Faults) Clock(component string) *clockmock.Mock {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.clocks[component]
+}
+
+// register registers the component serving on u
+func (f *Faults) register(component string, u *url.URL) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.components[grpcutils.URLToTarget(u)] = component
+}
+
+// withClock returns the context of the component with its mocked clock if clocks are mocked
+func (f *Faults) withClock(ctx context.Context, component string) context.Context {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.mockClocks {
+		return ctx
+	}
+	clk, ok := f.clocks[component]
+	if !ok {
+		clk = clockmock.New(f.ctx)
+		clk.Set(time.Now())
+		clk.SetSpeed(1)
+		f.clocks[component] = clk
+	}
+	return clock.WithClock(ctx, clk)
+}
+
+func (f *Faults) addRule(rule *faultRule) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rules = append(f.rules, rule)
+}
+
+// component returns the path of the component serving on the dial target
+func (f *Faults) component(target string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.components[target]
+}
+
+// check returns the error and the latency of the call from the source to the target component
+func (f *Faults) check(source, target string) (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var latency time.Duration
+	for _, rule := range f.rules {
+		if !matchPair(rule.a, rule.b, source, target) {
+			continue
+		}
+		if rule.partition {
+			return 0, status.Errorf(codes.Unavailable, "sandbox: %s is partitioned from %s", source, target)
+		}
+		latency += rule.latency
+	}
+	return latency, nil
+}
+
+func (f *Faults) addStream(s *faultStream) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.streams[s] = struct{}{}
+}
+
+func (f *Faults) deleteStream(s *faultStream) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.streams, s)
+}
+
+func (f *Faults) unaryInterceptor(source string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		latency, err := f.check(source, f.component(cc.Target()))
+		if err != nil {
+			return err
+		}
+		if err := delay(ctx, latency); err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func (f *Faults) streamInterceptor(source string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		target := f.component(cc.Target())
+		latency, err := f.check(source, target)
+		if err != nil {
+			return nil, err
+		}
+		if err = delay(ctx, latency); err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		s := &faultStream{source: source, target: target, cancel: cancel}
+		f.addStream(s)
+		context.AfterFunc(ctx, func() { f.deleteStream(s) })
+
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		return &faultClientStream{ClientStream: stream, faults: f, stream: s}, nil
+	}
+}
+
+type faultClientStream struct {
+	grpc.ClientStream
+	faults *Faults
+	stream *faultStream
+}
+
+func (s *faultClientStream) SendMsg(m interface{}) error {
+	latency, _ := s.faults.check(s.stream.source, s.stream.target)
+	if err := delay(s.Context(), latency); err != nil {
+		return err
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *faultClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.stream.cancel()
+		return err
+	}
+	latency, _ := s.faults.check(s.stream.source, s.stream.target)
+	return delay(s.Context(), latency)
+}
+
+func delay(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// match returns true if the pattern matches the component path
+func match(pattern, path string) bool {
+	return pattern == AnyComponent || pattern == path || strings.HasPrefix(path, pattern+"/")
+}
+
+func matchPair(a, b, source, target string) bool {
+	return match(a, source) && match(b, target) || match(b, source) && match(a, target)
+}
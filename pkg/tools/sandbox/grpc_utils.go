@@ -26,6 +26,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
 	"github.com/networkservicemesh/sdk/pkg/tools/log"
 	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
@@ -34,6 +35,8 @@ import (
 func serve(ctx context.Context, t *testing.T, u *url.URL, register func(server *grpc.Server)) {
 	server := grpc.NewServer(append([]grpc.ServerOption{
 		grpc.Creds(grpcfdTransportCredentials(insecure.NewCredentials())),
+		grpc.ChainUnaryInterceptor(clockUnaryInterceptor(clock.FromContext(ctx))),
+		grpc.ChainStreamInterceptor(clockStreamInterceptor(clock.FromContext(ctx))),
 	}, tracing.WithTracing()...)...)
 	register(server)
 
@@ -51,6 +54,33 @@ func serve(ctx context.Context, t *testing.T, u *url.URL, register func(server *
 	}()
 }
 
+// clockUnaryInterceptor sets the clock of the server into the contexts of the calls, so the calls use the clock mocked
+// with Faults.MockClocks
+func clockUnaryInterceptor(clk clock.Clock) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(clock.WithClock(ctx, clk), req)
+	}
+}
+
+// clockStreamInterceptor is the same as clockUnaryInterceptor for the streams
+func clockStreamInterceptor(clk clock.Clock) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &clockServerStream{
+			ServerStream: ss,
+			ctx:          clock.WithClock(ss.Context(), clk),
+		})
+	}
+}
+
+type clockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *clockServerStream) Context() context.Context {
+	return s.ctx
+}
+
 // CheckURLFree returns is given url is free for Listen
 func CheckURLFree(u *url.URL) bool {
 	ln, err := net.Listen(grpcutils.TargetToNetAddr(grpcutils.URLToTarget(u)))
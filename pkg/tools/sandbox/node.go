@@ -38,6 +38,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/retry"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/roundrobin"
 	registryclient "github.com/networkservicemesh/sdk/pkg/registry/chains/client"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/querycache"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/recvfd"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/sendfd"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/chain"
@@ -48,11 +49,20 @@ import (
 
 // Node is a NSMgr with Forwarder, NSE registry clients
 type Node struct {
+	// Name is the name of the node in the domain, e.g. "node-0"
+	Name string
+
 	t      *testing.T
 	domain *Domain
 
 	NSMgr      *NSMgrEntry
 	Forwarders map[string]*EndpointEntry
+	Endpoints  map[string]*EndpointEntry
+}
+
+// path returns the path of the node component used by Faults
+func (n *Node) path(component string) string {
+	return n.domain.Name + "/" + n.Name + "/" + component
 }
 
 // NewNSMgr creates a new NSMgr
@@ -67,7 +77,7 @@ func (n *Node) NewNSMgr(
 		serveURL = n.domain.supplyURL("nsmgr")
 	}
 
-	dialOptions := DialOptions(WithTokenGenerator(generatorFunc))
+	dialOptions := n.domain.dialOptions(n.path("nsmgr"), generatorFunc)
 
 	options := []nsmgr.Option{
 		nsmgr.WithName(name),
@@ -90,6 +100,7 @@ func (n *Node) NewNSMgr(
 		URL:  serveURL,
 	}
 	entry.restartableServer = newRestartableServer(ctx, n.t, entry.URL, func(ctx context.Context) {
+		ctx = n.domain.componentContext(ctx, n.path("nsmgr"), entry.URL)
 		entry.Nsmgr = supplyNSMgr(ctx, generatorFunc, options...)
 		serve(ctx, n.t, entry.URL, entry.Register)
 
@@ -124,7 +135,7 @@ func (n *Node) NewForwarder(
 	}
 
 	nseClone := nse.Clone()
-	dialOptions := DialOptions(WithTokenGenerator(generatorFunc))
+	dialOptions := n.domain.dialOptions(n.path(nse.Name), generatorFunc)
 
 	entry := &EndpointEntry{
 		Name: nse.Name,
@@ -137,10 +148,14 @@ func (n *Node) NewForwarder(
 			registryclient.WithDialOptions(dialOptions...),
 		),
 	)
-	nsClient := registryclient.NewNetworkServiceRegistryClient(ctx,
-		registryclient.WithClientURL(CloneURL(n.NSMgr.URL)),
-		registryclient.WithDialOptions(dialOptions...))
+	nsClient := chain.NewNetworkServiceRegistryClient(
+		querycache.NewNetworkServiceRegistryClient(ctx),
+		registryclient.NewNetworkServiceRegistryClient(ctx,
+			registryclient.WithClientURL(CloneURL(n.NSMgr.URL)),
+			registryclient.WithDialOptions(dialOptions...)),
+	)
 	entry.restartableServer = newRestartableServer(ctx, n.t, entry.URL, func(ctx context.Context) {
+		ctx = n.domain.componentContext(ctx, n.path(entry.Name), entry.URL)
 		entry.Endpoint = endpoint.NewServer(ctx, generatorFunc,
 			endpoint.WithName(entry.Name),
 			endpoint.WithAdditionalFunctionality(
@@ -202,13 +217,14 @@ func (n *Node) NewEndpoint(
 	}
 
 	nseClone := nse.Clone()
-	dialOptions := DialOptions(WithTokenGenerator(generatorFunc))
+	dialOptions := n.domain.dialOptions(n.path(nse.Name), generatorFunc)
 
 	entry := &EndpointEntry{
 		Name: nse.Name,
 		URL:  serveURL,
 	}
 	entry.restartableServer = newRestartableServer(ctx, n.t, entry.URL, func(ctx context.Context) {
+		ctx = n.domain.componentContext(ctx, n.path(entry.Name), entry.URL)
 		entry.Endpoint = endpoint.NewServer(ctx, generatorFunc,
 			endpoint.WithName(entry.Name),
 			endpoint.WithAdditionalFunctionality(additionalFunctionality...),
@@ -228,6 +244,8 @@ func (n *Node) NewEndpoint(
 		n.registerEndpoint(ctx, nse, nseClone, entry.NetworkServiceEndpointRegistryClient)
 	})
 
+	n.Endpoints[entry.Name] = entry
+
 	return entry
 }
 
@@ -252,7 +270,7 @@ func (n *Node) NewClient(
 ) networkservice.NetworkServiceClient {
 	opts := []client.Option{
 		client.WithClientURL(CloneURL(n.NSMgr.URL)),
-		client.WithDialOptions(DialOptions(WithTokenGenerator(generatorFunc))...),
+		client.WithDialOptions(n.domain.dialOptions(n.path("nsc"), generatorFunc)...),
 		client.WithAuthorizeClient(authorize.NewClient(authorize.Any())),
 		client.WithHealClient(heal.NewClient(ctx)),
 		client.WithDialTimeout(DialTimeout),
@@ -0,0 +1,143 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	registryapi "github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/dnsresolve"
+)
+
+// Topology is a declarative description of the sandbox domains
+type Topology struct {
+	Domains []*DomainSpec
+	// MockClocks makes the components use clocks which can be skewed with Faults.SkewClock
+	MockClocks bool
+}
+
+// DomainSpec describes a domain of the Topology
+type DomainSpec struct {
+	// Name is the DNS name of the domain, it is the first element of the domain component paths in Faults
+	Name string
+	// Local domain has no registry proxy and NSMgr proxy, so it doesn't take part in the interdomain scenarios
+	Local bool
+	// Nodes are named "node-<index>" in the domain
+	Nodes []*NodeSpec
+	// NetworkServices are registered in the domain registry
+	NetworkServices []string
+}
+
+// NodeSpec describes a node of the domain
+type NodeSpec struct {
+	// Forwarders are the names of the node forwarders, a single forwarder with an unique name is started if empty
+	Forwarders []string
+	Endpoints  []*EndpointSpec
+}
+
+// EndpointSpec describes an endpoint placed on the node
+type EndpointSpec struct {
+	Name                    string
+	NetworkServices         []string
+	AdditionalFunctionality []networkservice.NetworkServiceServer
+}
+
+// Sandbox is a set of the domains built by BuildTopology
+type Sandbox struct {
+	Domains map[string]*Domain
+	Faults  *Faults
+}
+
+// BuildTopology builds the domains described by the topology. All the domains share the same DNS resolver and Faults.
+func BuildTopology(ctx context.Context, t *testing.T, topology *Topology) *Sandbox {
+	faults := NewFaults(ctx)
+	if topology.MockClocks {
+		faults.MockClocks()
+	}
+
+	s := &Sandbox{
+		Domains: make(map[string]*Domain),
+		Faults:  faults,
+	}
+
+	dnsResolver := NewFakeResolver()
+	for i, spec := range topology.Domains {
+		require.NotEmpty(t, spec.Name, "domain name should be set: %d", i)
+		require.NotContains(t, s.Domains, spec.Name, "domain names should be unique: %s", spec.Name)
+
+		s.Domains[spec.Name] = buildDomain(ctx, t, spec, dnsResolver, faults)
+	}
+
+	return s
+}
+
+func buildDomain(ctx context.Context, t *testing.T, spec *DomainSpec, dnsResolver dnsresolve.Resolver, faults *Faults) *Domain {
+	b := NewBuilder(ctx, t).
+		SetNodesCount(len(spec.Nodes)).
+		SetDNSDomainName(spec.Name).
+		SetDNSResolver(dnsResolver).
+		SetFaults(faults)
+	if spec.Local {
+		b.SetNSMgrProxySupplier(nil).
+			SetRegistryProxySupplier(nil)
+	}
+	b.SetNodeSetup(func(ctx context.Context, node *Node, nodeNum int) {
+		node.NewNSMgr(ctx, UniqueName("nsmgr"), nil, b.generateTokenFunc, b.supplyNSMgr)
+
+		forwarders := spec.Nodes[nodeNum].Forwarders
+		if len(forwarders) == 0 {
+			forwarders = []string{UniqueName("forwarder")}
+		}
+		for _, name := range forwarders {
+			node.NewForwarder(ctx, &registryapi.NetworkServiceEndpoint{
+				Name:                name,
+				NetworkServiceNames: []string{"forwarder"},
+				NetworkServiceLabels: map[string]*registryapi.NetworkServiceLabels{
+					"forwarder": {
+						Labels: map[string]string{
+							"p2p": "true",
+						},
+					},
+				},
+			}, b.generateTokenFunc)
+		}
+	})
+	domain := b.Build()
+
+	nsRegistryClient := domain.NewNSRegistryClient(ctx, b.generateTokenFunc)
+	for _, name := range spec.NetworkServices {
+		_, err := nsRegistryClient.Register(ctx, &registryapi.NetworkService{
+			Name: name,
+		})
+		require.NoError(t, err, "failed to register network service: %s", name)
+	}
+
+	for i, node := range domain.Nodes {
+		for _, nse := range spec.Nodes[i].Endpoints {
+			node.NewEndpoint(ctx, &registryapi.NetworkServiceEndpoint{
+				Name:                nse.Name,
+				NetworkServiceNames: nse.NetworkServices,
+			}, b.generateTokenFunc, nse.AdditionalFunctionality...)
+		}
+	}
+
+	return domain
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
+	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	registryapi "github.com/networkservicemesh/api/pkg/api/registry"
+
+	registryclient "github.com/networkservicemesh/sdk/pkg/registry/chains/client"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+func request(nsName string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Cls: cls.LOCAL, Type: kernelmech.MECHANISM},
+		},
+		Connection: &networkservice.Connection{
+			Id:             uuid.NewString(),
+			NetworkService: nsName,
+			Context:        &networkservice.ConnectionContext{},
+			Labels:         make(map[string]string),
+		},
+	}
+}
+
+func TestBuildTopology_Partition(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	s := sandbox.BuildTopology(ctx, t, &sandbox.Topology{
+		Domains: []*sandbox.DomainSpec{
+			{
+				Name:  "cluster",
+				Local: true,
+				Nodes: []*sandbox.NodeSpec{
+					{},
+					{
+						Endpoints: []*sandbox.EndpointSpec{
+							{Name: "nse", NetworkServices: []string{"ns"}},
+						},
+					},
+				},
+				NetworkServices: []string{"ns"},
+			},
+		},
+	})
+
+	domain := s.Domains["cluster"]
+	require.Len(t, domain.Nodes, 2)
+	require.Contains(t, domain.Nodes[1].Endpoints, "nse")
+
+	nsc := domain.Nodes[0].NewClient(ctx, sandbox.GenerateTestToken)
+
+	conn, err := nsc.Request(ctx, request("ns"))
+	require.NoError(t, err)
+	_, err = nsc.Close(ctx, conn)
+	require.NoError(t, err)
+
+	s.Faults.Partition("cluster/node-0", "cluster/node-1")
+
+	requestCtx, requestCancel := context.WithTimeout(ctx, time.Second)
+	defer requestCancel()
+
+	_, err = nsc.Request(requestCtx, request("ns"))
+	require.Error(t, err)
+
+	s.Faults.Heal("cluster/node-0", "cluster/node-1")
+
+	conn, err = nsc.Request(ctx, request("ns"))
+	require.NoError(t, err)
+	_, err = nsc.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+func TestBuildTopology_Interdomain(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	s := sandbox.BuildTopology(ctx, t, &sandbox.Topology{
+		Domains: []*sandbox.DomainSpec{
+			{
+				Name:  "cluster1",
+				Nodes: []*sandbox.NodeSpec{{}},
+			},
+			{
+				Name: "cluster2",
+				Nodes: []*sandbox.NodeSpec{
+					{
+						Forwarders: []string{"forwarder-1", "forwarder-2"},
+						Endpoints: []*sandbox.EndpointSpec{
+							{Name: "nse", NetworkServices: []string{"ns"}},
+						},
+					},
+				},
+				NetworkServices: []string{"ns"},
+			},
+		},
+		MockClocks: true,
+	})
+
+	require.Len(t, s.Domains["cluster2"].Nodes[0].Forwarders, 2)
+
+	nsc := s.Domains["cluster1"].Nodes[0].NewClient(ctx, sandbox.GenerateTestToken)
+
+	s.Faults.AddLatency("cluster1", "cluster2", time.Millisecond*100)
+
+	conn, err := nsc.Request(ctx, request("ns@cluster2"))
+	require.NoError(t, err)
+	_, err = nsc.Close(ctx, conn)
+	require.NoError(t, err)
+
+	clk := s.Faults.Clock("cluster2/node-0/nsmgr")
+	require.NotNil(t, clk)
+
+	now := clk.Now()
+	s.Faults.SkewClock("cluster2", time.Hour)
+	require.True(t, clk.Now().Sub(now) >= time.Hour)
+}
+
+func TestBuildTopology_SkewClockExpires(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	s := sandbox.BuildTopology(ctx, t, &sandbox.Topology{
+		Domains: []*sandbox.DomainSpec{
+			{
+				Name:  "cluster",
+				Local: true,
+				Nodes: []*sandbox.NodeSpec{
+					{
+						Endpoints: []*sandbox.EndpointSpec{
+							{Name: "nse", NetworkServices: []string{"ns"}},
+						},
+					},
+				},
+				NetworkServices: []string{"ns"},
+			},
+		},
+		MockClocks: true,
+	})
+
+	nseRegistryClient := registryclient.NewNetworkServiceEndpointRegistryClient(ctx,
+		registryclient.WithClientURL(sandbox.CloneURL(s.Domains["cluster"].Registry.URL)),
+		registryclient.WithDialOptions(sandbox.DialOptions()...))
+
+	find := func() []*registryapi.NetworkServiceEndpoint {
+		stream, err := nseRegistryClient.Find(ctx, &registryapi.NetworkServiceEndpointQuery{
+			NetworkServiceEndpoint: &registryapi.NetworkServiceEndpoint{Name: "nse"},
+		})
+		require.NoError(t, err)
+		return registryapi.ReadNetworkServiceEndpointList(stream)
+	}
+	require.Len(t, find(), 1)
+
+	// The registry calls use the registry clock, so the NSE expires in the registry only
+	s.Faults.SkewClock("cluster/registry", time.Hour)
+
+	require.Eventually(t, func() bool { return len(find()) == 0 }, time.Second, time.Millisecond*10)
+}
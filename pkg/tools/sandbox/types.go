@@ -87,6 +87,7 @@ type Domain struct {
 	Name        string
 
 	supplyURL func(prefix string) *url.URL
+	faults    *Faults
 }
 
 // NewNSRegistryClient creates new NS registry client for the domain
@@ -103,7 +104,21 @@ func (d *Domain) NewNSRegistryClient(ctx context.Context, generatorFunc token.Ge
 
 	opts = append(opts,
 		registryclient.WithClientURL(registryURL),
-		registryclient.WithDialOptions(DialOptions(WithTokenGenerator(generatorFunc))...))
+		registryclient.WithDialOptions(d.dialOptions(d.Name+"/nsc", generatorFunc)...))
 
 	return registryclient.NewNetworkServiceRegistryClient(ctx, opts...)
 }
+
+// dialOptions returns DialOptions for the calls made by the component
+func (d *Domain) dialOptions(component string, generatorFunc token.GeneratorFunc) []grpc.DialOption {
+	return DialOptions(WithTokenGenerator(generatorFunc), WithFaults(d.faults, component))
+}
+
+// componentContext registers the component serving on u in the faults and returns the context for it
+func (d *Domain) componentContext(ctx context.Context, component string, u *url.URL) context.Context {
+	if d.faults == nil {
+		return ctx
+	}
+	d.faults.register(component, u)
+	return d.faults.withClock(ctx, component)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spiffejwt
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/svid/jwtsvid"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+)
+
+const fetchTimeout = 5 * time.Second
+
+type cachedSVID struct {
+	svid      *jwtsvid.SVID
+	fetchedAt time.Time
+}
+
+// JWTSVIDGeneratorFunc - creates a token.GeneratorFunc returning JWT-SVIDs fetched from the source (usually
+// workloadapi.JWTSource). The audience is the SPIFFE ID of the peer, or the one set by WithAudience if the peer is
+// unknown. JWT-SVIDs are cached per audience until half of their lifetime has passed.
+//   - ctx - context for the lifecycle of the generator, it bounds the fetch requests
+func JWTSVIDGeneratorFunc(ctx context.Context, source jwtsvid.Source, opts ...Option) token.GeneratorFunc {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var mu sync.Mutex
+	cache := make(map[string]cachedSVID)
+
+	return func(authInfo credentials.AuthInfo) (string, time.Time, error) {
+		audience, _, err := peerAudience(authInfo)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		if audience == nil {
+			audience = o.audience
+		}
+		if len(audience) == 0 {
+			return "", time.Time{}, errors.New("JWT-SVID requires an audience: peer SPIFFE ID is unknown and no default audience is set")
+		}
+
+		key := strings.Join(audience, " ")
+		now := time.Now()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if cached, ok := cache[key]; ok && now.Before(cached.fetchedAt.Add(cached.svid.Expiry.Sub(cached.fetchedAt)/2)) {
+			return cached.svid.Marshal(), cached.svid.Expiry, nil
+		}
+
+		fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+		defer cancel()
+
+		svid, err := source.FetchJWTSVID(fetchCtx, jwtsvid.Params{
+			Audience:       audience[0],
+			ExtraAudiences: audience[1:],
+		})
+		if err != nil {
+			return "", time.Time{}, errors.Wrapf(err, "failed to fetch JWT-SVID for audience %v", audience)
+		}
+
+		for k, cached := range cache {
+			if !now.Before(cached.svid.Expiry) {
+				delete(cache, k)
+			}
+		}
+		cache[key] = cachedSVID{svid: svid, fetchedAt: now}
+
+		return svid.Marshal(), svid.Expiry, nil
+	}
+}
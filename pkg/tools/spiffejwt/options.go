@@ -0,0 +1,137 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spiffejwt
+
+import (
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/spiffe/go-spiffe/v2/bundle/jwtbundle"
+	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+)
+
+const (
+	// NetworkServiceClaim - claim set by ConnectionClaims with the network service of the connection
+	NetworkServiceClaim = "nsm_network_service"
+	// PathIndexClaim - claim set by ConnectionClaims with the path index of the token
+	PathIndexClaim = "nsm_path_index"
+)
+
+// ClaimsFunc - adds custom claims to the token generated for the authInfo. connInfo is nil for tokens generated by
+// TokenGeneratorFunc.
+type ClaimsFunc func(authInfo credentials.AuthInfo, connInfo *token.ConnectionInfo, claims jwt.MapClaims) error
+
+type options struct {
+	claims           []ClaimsFunc
+	certificateChain bool
+	audience         []string
+}
+
+// Option - option for token generators
+type Option func(o *options)
+
+// WithClaims - adds claims hooks called for every token generated by TokenGeneratorFunc. JWT-SVIDs are signed by
+// the SPIFFE server, so JWTSVIDGeneratorFunc ignores claims hooks.
+func WithClaims(claims ...ClaimsFunc) Option {
+	return func(o *options) {
+		o.claims = append(o.claims, claims...)
+	}
+}
+
+// WithCertificateChain - makes TokenGeneratorFunc put the X.509-SVID chain to the "x5c" header, so the token can
+// be verified against the trust bundle instead of the peer certificate
+func WithCertificateChain() Option {
+	return func(o *options) {
+		o.certificateChain = true
+	}
+}
+
+// WithAudience - sets the audience used when the peer SPIFFE ID is not known
+func WithAudience(audience ...string) Option {
+	return func(o *options) {
+		o.audience = audience
+	}
+}
+
+// ConnectionClaims - returns claims hook setting the network service and the path index claims for tokens
+// generated by ConnectionTokenGeneratorFunc
+func ConnectionClaims() ClaimsFunc {
+	return func(_ credentials.AuthInfo, connInfo *token.ConnectionInfo, claims jwt.MapClaims) error {
+		if connInfo != nil {
+			claims[NetworkServiceClaim] = connInfo.NetworkService
+			claims[PathIndexClaim] = connInfo.PathIndex
+		}
+		return nil
+	}
+}
+
+// StaticClaims - returns claims hook setting the same custom claims for every token
+func StaticClaims(custom map[string]interface{}) ClaimsFunc {
+	return func(_ credentials.AuthInfo, _ *token.ConnectionInfo, claims jwt.MapClaims) error {
+		for k, v := range custom {
+			claims[k] = v
+		}
+		return nil
+	}
+}
+
+type verifierOptions struct {
+	jwtBundles  jwtbundle.Source
+	x509Bundles x509bundle.Source
+	audience    []string
+}
+
+// VerifierOption - option for NewVerifier
+type VerifierOption func(o *verifierOptions)
+
+// WithJWTBundles - sets JWT bundles used to verify JWT-SVIDs
+func WithJWTBundles(bundles jwtbundle.Source) VerifierOption {
+	return func(o *verifierOptions) {
+		o.jwtBundles = bundles
+	}
+}
+
+// WithX509Bundles - sets X.509 bundles used to verify tokens carrying the certificate chain
+func WithX509Bundles(bundles x509bundle.Source) VerifierOption {
+	return func(o *verifierOptions) {
+		o.x509Bundles = bundles
+	}
+}
+
+// BundleSource - source of both X.509 and JWT bundles, e.g. workloadapi.BundleSource which also provides the bundles
+// of federated trust domains
+type BundleSource interface {
+	x509bundle.Source
+	jwtbundle.Source
+}
+
+// WithBundles - sets both X.509 and JWT bundles from the source. Tokens are verified against the bundle of the trust
+// domain of their SPIFFE ID, so a federated source lets the verifier check tokens issued in other domains.
+func WithBundles(bundles BundleSource) VerifierOption {
+	return func(o *verifierOptions) {
+		o.jwtBundles = bundles
+		o.x509Bundles = bundles
+	}
+}
+
+// WithExpectedAudience - sets the audience the verified tokens must have at least one of
+func WithExpectedAudience(audience ...string) VerifierOption {
+	return func(o *verifierOptions) {
+		o.audience = audience
+	}
+}
@@ -19,9 +19,14 @@
 package spiffejwt
 
 import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
 	"google.golang.org/grpc/credentials"
@@ -30,8 +35,21 @@ import (
 )
 
 // TokenGeneratorFunc - creates a token.TokenGeneratorFunc that creates spiffe JWT tokens from the cert returned by getCert()
-func TokenGeneratorFunc(source x509svid.Source, maxTokenLifeTime time.Duration) token.GeneratorFunc {
+func TokenGeneratorFunc(source x509svid.Source, maxTokenLifeTime time.Duration, opts ...Option) token.GeneratorFunc {
+	generator := ConnectionTokenGeneratorFunc(source, maxTokenLifeTime, opts...)
 	return func(authInfo credentials.AuthInfo) (string, time.Time, error) {
+		return generator(authInfo, nil)
+	}
+}
+
+// ConnectionTokenGeneratorFunc - same as TokenGeneratorFunc, but the claims hooks also get the connection the token
+// is generated for. Use it with updatetoken.NewConnectionServer.
+func ConnectionTokenGeneratorFunc(source x509svid.Source, maxTokenLifeTime time.Duration, opts ...Option) token.ConnectionGeneratorFunc {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return func(authInfo credentials.AuthInfo, connInfo *token.ConnectionInfo) (string, time.Time, error) {
 		ownSVID, err := source.GetX509SVID()
 		if err != nil {
 			return "", time.Time{}, errors.Wrap(err, "Error creating Token")
@@ -41,26 +59,68 @@ func TokenGeneratorFunc(source x509svid.Source, maxTokenLifeTime time.Duration)
 		if ownSVID.Certificates[0].NotAfter.Before(expireTime) {
 			expireTime = ownSVID.Certificates[0].NotAfter
 		}
-		claims := jwt.RegisteredClaims{
-			Subject:   ownSVID.ID.String(),
-			ExpiresAt: jwt.NewNumericDate(expireTime),
+		claims := jwt.MapClaims{
+			"sub": ownSVID.ID.String(),
+			"jti": uuid.New().String(),
+		}
+		audience, peerNotAfter, err := peerAudience(authInfo)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		if !peerNotAfter.IsZero() && peerNotAfter.Before(expireTime) {
+			expireTime = peerNotAfter
+		}
+		if audience == nil {
+			audience = o.audience
+		}
+		if len(audience) > 0 {
+			claims["aud"] = audience
+		}
+		claims["exp"] = jwt.NewNumericDate(expireTime)
+
+		for _, claimsFunc := range o.claims {
+			if err = claimsFunc(authInfo, connInfo, claims); err != nil {
+				return "", time.Time{}, errors.Wrap(err, "failed to set custom claims")
+			}
+		}
+
+		tok := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		if tok.Header["kid"], err = keyID(ownSVID.Certificates[0].PublicKey); err != nil {
+			return "", time.Time{}, err
 		}
-		if authInfo != nil {
-			if tlsInfo, ok := authInfo.(credentials.TLSInfo); ok {
-				if len(tlsInfo.State.PeerCertificates) > 0 {
-					peerCert := tlsInfo.State.PeerCertificates[0]
-					peerSpiffeID, err2 := x509svid.IDFromCert(peerCert)
-					if err2 != nil {
-						return "", time.Time{}, errors.Wrap(err2, "failed to extract the SPIFFE ID from the URI SAN of the provided peer certificate")
-					}
-					if peerCert.NotAfter.Before(expireTime) {
-						expireTime = peerCert.NotAfter
-					}
-					claims.Audience = []string{peerSpiffeID.String()}
-				}
+		if o.certificateChain {
+			chain := make([]string, 0, len(ownSVID.Certificates))
+			for _, cert := range ownSVID.Certificates {
+				chain = append(chain, base64.StdEncoding.EncodeToString(cert.Raw))
 			}
+			tok.Header["x5c"] = chain
 		}
-		tok, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(ownSVID.PrivateKey)
-		return tok, expireTime, errors.Wrapf(err, "failed to create a new Token, method %s, subject %s", jwt.SigningMethodHS256.Name, claims.Subject)
+
+		signed, err := tok.SignedString(ownSVID.PrivateKey)
+		return signed, expireTime, errors.Wrapf(err, "failed to create a new Token, method %s, subject %s", jwt.SigningMethodES256.Name, ownSVID.ID.String())
+	}
+}
+
+// peerAudience returns the SPIFFE ID of the peer as the audience and the expiration time of the peer certificate
+func peerAudience(authInfo credentials.AuthInfo) ([]string, time.Time, error) {
+	tlsInfo, ok := authInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return nil, time.Time{}, nil
+	}
+	peerCert := tlsInfo.State.PeerCertificates[0]
+	peerSpiffeID, err := x509svid.IDFromCert(peerCert)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "failed to extract the SPIFFE ID from the URI SAN of the provided peer certificate")
+	}
+	return []string{peerSpiffeID.String()}, peerCert.NotAfter, nil
+}
+
+// keyID returns base64url encoded SHA-256 of the public key used as "kid" header
+func keyID(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the public key")
 	}
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
 }
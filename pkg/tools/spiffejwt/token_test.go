@@ -0,0 +1,244 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spiffejwt_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"math/big"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/spiffe/go-spiffe/v2/bundle/jwtbundle"
+	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/jwtsvid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+)
+
+const (
+	nscID = "spiffe://example.org/nsc"
+	nseID = "spiffe://example.org/nse"
+)
+
+var trustDomain = spiffeid.RequireTrustDomainFromString("example.org")
+
+type x509Source struct {
+	svid *x509svid.SVID
+}
+
+func (s *x509Source) GetX509SVID() (*x509svid.SVID, error) {
+	return s.svid, nil
+}
+
+func newCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func newX509SVID(t *testing.T, id string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) *x509svid.SVID {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	u, err := url.Parse(id)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		URIs:         []*url.URL{u},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &x509svid.SVID{
+		ID:           spiffeid.RequireFromString(id),
+		Certificates: []*x509.Certificate{cert},
+		PrivateKey:   key,
+	}
+}
+
+func TestTokenGeneratorFunc_CertificateChain(t *testing.T) {
+	ca, caKey := newCA(t)
+	source := &x509Source{svid: newX509SVID(t, nscID, ca, caKey)}
+
+	generator := spiffejwt.ConnectionTokenGeneratorFunc(source, time.Hour,
+		spiffejwt.WithCertificateChain(),
+		spiffejwt.WithAudience(nseID),
+		spiffejwt.WithClaims(
+			spiffejwt.ConnectionClaims(),
+			spiffejwt.StaticClaims(map[string]interface{}{"tenant": "blue"}),
+		),
+	)
+
+	tok, expireTime, err := generator(nil, &token.ConnectionInfo{NetworkService: "ns", PathIndex: 1})
+	require.NoError(t, err)
+	require.True(t, expireTime.After(time.Now()))
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(tok, jwt.MapClaims{})
+	require.NoError(t, err)
+	require.NotEmpty(t, parsed.Header["kid"])
+
+	verifier := spiffejwt.NewVerifier(
+		spiffejwt.WithX509Bundles(x509bundle.FromX509Authorities(trustDomain, []*x509.Certificate{ca})),
+		spiffejwt.WithExpectedAudience(nseID),
+	)
+	id, claims, err := verifier.Verify(tok)
+	require.NoError(t, err)
+	require.Equal(t, nscID, id.String())
+	require.Equal(t, "ns", claims[spiffejwt.NetworkServiceClaim])
+	require.Equal(t, float64(1), claims[spiffejwt.PathIndexClaim])
+	require.Equal(t, "blue", claims["tenant"])
+
+	// Another trust bundle
+	otherCA, _ := newCA(t)
+	_, _, err = spiffejwt.NewVerifier(
+		spiffejwt.WithX509Bundles(x509bundle.FromX509Authorities(trustDomain, []*x509.Certificate{otherCA})),
+	).Verify(tok)
+	require.Error(t, err)
+
+	// Another audience
+	_, _, err = spiffejwt.NewVerifier(
+		spiffejwt.WithX509Bundles(x509bundle.FromX509Authorities(trustDomain, []*x509.Certificate{ca})),
+		spiffejwt.WithExpectedAudience("spiffe://example.org/other"),
+	).Verify(tok)
+	require.Error(t, err)
+}
+
+func TestTokenGeneratorFunc_WithoutCertificateChain(t *testing.T) {
+	ca, caKey := newCA(t)
+	source := &x509Source{svid: newX509SVID(t, nscID, ca, caKey)}
+
+	tok, _, err := spiffejwt.TokenGeneratorFunc(source, time.Hour)(nil)
+	require.NoError(t, err)
+
+	_, _, err = spiffejwt.NewVerifier(
+		spiffejwt.WithX509Bundles(x509bundle.FromX509Authorities(trustDomain, []*x509.Certificate{ca})),
+	).Verify(tok)
+	require.Error(t, err)
+}
+
+type jwtSource struct {
+	key     *ecdsa.PrivateKey
+	fetches int32
+}
+
+func (s *jwtSource) FetchJWTSVID(_ context.Context, params jwtsvid.Params) (*jwtsvid.SVID, error) {
+	atomic.AddInt32(&s.fetches, 1)
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"sub": nscID,
+		"aud": append([]string{params.Audience}, params.ExtraAudiences...),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	tok.Header["kid"] = "authority"
+	signed, err := tok.SignedString(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return jwtsvid.ParseInsecure(signed, []string{params.Audience})
+}
+
+func TestJWTSVIDGeneratorFunc(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	source := &jwtSource{key: key}
+
+	generator := spiffejwt.JWTSVIDGeneratorFunc(context.Background(), source, spiffejwt.WithAudience(nseID))
+
+	tok, expireTime, err := generator(nil)
+	require.NoError(t, err)
+	require.True(t, expireTime.After(time.Now()))
+
+	// Cached
+	cached, _, err := generator(credentials.TLSInfo{})
+	require.NoError(t, err)
+	require.Equal(t, tok, cached)
+	require.Equal(t, int32(1), atomic.LoadInt32(&source.fetches))
+
+	verifier := spiffejwt.NewVerifier(
+		spiffejwt.WithJWTBundles(jwtbundle.FromJWTAuthorities(trustDomain, map[string]crypto.PublicKey{
+			"authority": &key.PublicKey,
+		})),
+		spiffejwt.WithExpectedAudience(nseID),
+	)
+	id, _, err := verifier.Verify(tok)
+	require.NoError(t, err)
+	require.Equal(t, nscID, id.String())
+
+	// No audience
+	_, _, err = spiffejwt.JWTSVIDGeneratorFunc(context.Background(), source)(nil)
+	require.Error(t, err)
+}
+
+func TestPrevTokenPolicy(t *testing.T) {
+	ca, caKey := newCA(t)
+	source := &x509Source{svid: newX509SVID(t, nscID, ca, caKey)}
+
+	tok, _, err := spiffejwt.TokenGeneratorFunc(source, time.Hour, spiffejwt.WithCertificateChain())(nil)
+	require.NoError(t, err)
+
+	policy := spiffejwt.PrevTokenPolicy(spiffejwt.NewVerifier(
+		spiffejwt.WithX509Bundles(x509bundle.FromX509Authorities(trustDomain, []*x509.Certificate{ca})),
+	))
+
+	path := &networkservice.Path{
+		Index: 1,
+		PathSegments: []*networkservice.PathSegment{
+			{Token: tok},
+			{},
+		},
+	}
+	require.NoError(t, policy.Check(context.Background(), path))
+
+	path.Index = 0
+	require.Error(t, policy.Check(context.Background(), path))
+
+	path.Index = 1
+	path.PathSegments[0].Token = "invalid"
+	require.Error(t, policy.Check(context.Background(), path))
+}
@@ -0,0 +1,287 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spiffejwt
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/jwtsvid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Verifier verifies tokens against the trust bundles instead of the peer certificate, so tokens stay valid across
+// certificate rotation and can be verified in federated trust domains.
+type Verifier struct {
+	verifierOptions
+}
+
+// NewVerifier - creates a new Verifier. At least one of WithJWTBundles, WithX509Bundles is required to verify
+// anything.
+func NewVerifier(opts ...VerifierOption) *Verifier {
+	v := new(Verifier)
+	for _, opt := range opts {
+		opt(&v.verifierOptions)
+	}
+	return v
+}
+
+type verificationCacheKey struct{}
+
+type verificationKey struct {
+	verifier *Verifier
+	token    string
+}
+
+type verification struct {
+	id     spiffeid.ID
+	claims map[string]interface{}
+	err    error
+}
+
+type verificationCache struct {
+	mu      sync.Mutex
+	results map[verificationKey]verification
+}
+
+// WithVerificationCache - returns a new context caching the results of the token verification by TokenPolicy and
+// PathTokensPolicy, so every token is verified once for all the policies checked with the context. authorize chain
+// elements set it for every check.
+func WithVerificationCache(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(verificationCacheKey{}).(*verificationCache); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, verificationCacheKey{}, &verificationCache{
+		results: make(map[verificationKey]verification),
+	})
+}
+
+// Verify - verifies the token and returns its SPIFFE ID and claims. Tokens with the "x5c" header are verified against
+// the X.509 bundles, other tokens are verified as JWT-SVIDs against the JWT bundles.
+func (v *Verifier) Verify(tok string) (spiffeid.ID, map[string]interface{}, error) {
+	unverified, _, err := jwt.NewParser().ParseUnverified(tok, jwt.MapClaims{})
+	if err != nil {
+		return spiffeid.ID{}, nil, errors.Wrap(err, "failed to parse token")
+	}
+
+	switch _, hasChain := unverified.Header["x5c"]; {
+	case hasChain && v.x509Bundles != nil:
+		return v.verifyCertificateChain(tok)
+	case v.jwtBundles != nil:
+		svid, err := jwtsvid.ParseAndValidate(tok, v.jwtBundles, v.audience)
+		if err != nil {
+			return spiffeid.ID{}, nil, errors.Wrap(err, "failed to verify JWT-SVID")
+		}
+		return svid.ID, svid.Claims, nil
+	default:
+		return spiffeid.ID{}, nil, errors.New("no trust bundle to verify the token")
+	}
+}
+
+// verify - verifies the token using the verification cache of the context if any
+func (v *Verifier) verify(ctx context.Context, tok string) (spiffeid.ID, map[string]interface{}, error) {
+	cache, ok := ctx.Value(verificationCacheKey{}).(*verificationCache)
+	if !ok {
+		return v.Verify(tok)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	key := verificationKey{verifier: v, token: tok}
+	if result, ok := cache.results[key]; ok {
+		return result.id, result.claims, result.err
+	}
+	id, claims, err := v.Verify(tok)
+	cache.results[key] = verification{id: id, claims: claims, err: err}
+	return id, claims, err
+}
+
+func (v *Verifier) verifyCertificateChain(tok string) (spiffeid.ID, map[string]interface{}, error) {
+	var id spiffeid.ID
+	claims := jwt.MapClaims{}
+
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}))
+	_, err := parser.ParseWithClaims(tok, claims, func(t *jwt.Token) (interface{}, error) {
+		certs, err := parseCertificateChain(t.Header["x5c"])
+		if err != nil {
+			return nil, err
+		}
+		if id, _, err = x509svid.Verify(certs, v.x509Bundles); err != nil {
+			return nil, errors.Wrap(err, "certificate chain is not trusted")
+		}
+		return certs[0].PublicKey, nil
+	})
+	if err != nil {
+		return spiffeid.ID{}, nil, errors.Wrap(err, "failed to verify token")
+	}
+
+	if sub, _ := claims["sub"].(string); sub != id.String() {
+		return spiffeid.ID{}, nil, errors.Errorf("token subject %q doesn't match certificate SPIFFE ID %q", sub, id)
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return spiffeid.ID{}, nil, errors.New("token has expired or has no exp claim")
+	}
+	if len(v.audience) > 0 {
+		var ok bool
+		for _, aud := range v.audience {
+			ok = ok || claims.VerifyAudience(aud, true)
+		}
+		if !ok {
+			return spiffeid.ID{}, nil, errors.Errorf("expected audience in %q", v.audience)
+		}
+	}
+	return id, claims, nil
+}
+
+func parseCertificateChain(header interface{}) ([]*x509.Certificate, error) {
+	encoded, ok := header.([]interface{})
+	if !ok || len(encoded) == 0 {
+		return nil, errors.New("x5c header must be a non-empty array")
+	}
+	certs := make([]*x509.Certificate, 0, len(encoded))
+	for _, item := range encoded {
+		s, ok := item.(string)
+		if !ok {
+			return nil, errors.New("x5c header must contain strings")
+		}
+		der, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode x5c certificate")
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse x5c certificate")
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// TokenPolicy - authorization policy verifying the token of the previous path segment with the Verifier. It can
+// be used instead of prev_token_signed.rego, which verifies the token against the peer certificate.
+type TokenPolicy struct {
+	verifier *Verifier
+}
+
+// PrevTokenPolicy - returns a new TokenPolicy
+func PrevTokenPolicy(verifier *Verifier) *TokenPolicy {
+	return &TokenPolicy{
+		verifier: verifier,
+	}
+}
+
+// Name returns the policy name
+func (p *TokenPolicy) Name() string {
+	return "prev_token_bundle_signed"
+}
+
+// Check returns nil if the token of the previous path segment is valid. The input is *networkservice.Path.
+func (p *TokenPolicy) Check(ctx context.Context, input interface{}) error {
+	path, ok := input.(*networkservice.Path)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected policy input: %T", input)
+	}
+	index := int(path.GetIndex())
+	if index == 0 || index > len(path.GetPathSegments()) {
+		return status.Error(codes.PermissionDenied, "no previous path segment")
+	}
+	if _, _, err := p.verifier.verify(ctx, path.GetPathSegments()[index-1].GetToken()); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+// PathTokensPolicy - authorization policy verifying the tokens of all path segments, each against the bundle of its
+// own trust domain, and checking that the audience of every token includes the SPIFFE ID of the next one. With a
+// federated bundle source it keeps the full path validation for interdomain connections.
+//
+// Tokens are verified against the bundles only, so every hop must put the certificate chain into its tokens (see
+// WithCertificateChain) or use JWT-SVIDs (see JWTSVIDGeneratorFunc).
+type PathTokensPolicy struct {
+	verifier *Verifier
+}
+
+// PathPolicy - returns a new PathTokensPolicy
+func PathPolicy(verifier *Verifier) *PathTokensPolicy {
+	return &PathTokensPolicy{
+		verifier: verifier,
+	}
+}
+
+// Name returns the policy name
+func (p *PathTokensPolicy) Name() string {
+	return "path_tokens_federated"
+}
+
+// Check returns nil if all tokens of the path are valid and chained. The input is *networkservice.Path.
+func (p *PathTokensPolicy) Check(ctx context.Context, input interface{}) error {
+	path, ok := input.(*networkservice.Path)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected policy input: %T", input)
+	}
+
+	var prevAudience []string
+	for i, segment := range path.GetPathSegments() {
+		id, claims, err := p.verifier.verify(ctx, segment.GetToken())
+		if err != nil {
+			return status.Errorf(codes.PermissionDenied, "path segment %d (%s): %s", i, segment.GetName(), err.Error())
+		}
+		if i > 0 && !contains(prevAudience, id.String()) {
+			return status.Errorf(codes.PermissionDenied, "path segment %d (%s): %s is not in the audience of the previous token %q",
+				i, segment.GetName(), id, prevAudience)
+		}
+		prevAudience = audience(claims)
+	}
+	return nil
+}
+
+func audience(claims map[string]interface{}) []string {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return []string{aud}
+	case []string:
+		return aud
+	case []interface{}:
+		result := make([]string, 0, len(aud))
+		for _, item := range aud {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spiffejwt_test
+
+import (
+	"context"
+	"crypto/x509"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
+	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
+)
+
+func TestPathPolicy_Federation(t *testing.T) {
+	ca1, caKey1 := newCA(t)
+	ca2, caKey2 := newCA(t)
+
+	ids := []string{
+		"spiffe://cluster1.example/nsc",
+		"spiffe://cluster1.example/nsmgr-proxy",
+		"spiffe://cluster2.example/nse",
+	}
+	cas := []*x509.Certificate{ca1, ca1, ca2}
+
+	path := &networkservice.Path{Index: 2}
+	for i, id := range ids {
+		caKey := caKey1
+		if cas[i] == ca2 {
+			caKey = caKey2
+		}
+		opts := []spiffejwt.Option{spiffejwt.WithCertificateChain()}
+		if i+1 < len(ids) {
+			opts = append(opts, spiffejwt.WithAudience(ids[i+1]))
+		}
+		tok, _, err := spiffejwt.TokenGeneratorFunc(&x509Source{svid: newX509SVID(t, id, cas[i], caKey)}, time.Hour, opts...)(nil)
+		require.NoError(t, err)
+		path.PathSegments = append(path.PathSegments, &networkservice.PathSegment{Name: id, Token: tok})
+	}
+
+	bundle1 := spiffebundle.FromX509Authorities(spiffeid.RequireTrustDomainFromString("cluster1.example"), []*x509.Certificate{ca1})
+	bundle2 := spiffebundle.FromX509Authorities(spiffeid.RequireTrustDomainFromString("cluster2.example"), []*x509.Certificate{ca2})
+
+	federated := spiffejwt.PathPolicy(spiffejwt.NewVerifier(spiffejwt.WithBundles(spiffebundle.NewSet(bundle1, bundle2))))
+	require.NoError(t, federated.Check(context.Background(), path))
+
+	// The bundle of cluster2.example is unknown
+	local := spiffejwt.PathPolicy(spiffejwt.NewVerifier(spiffejwt.WithBundles(spiffebundle.NewSet(bundle1))))
+	require.Error(t, local.Check(context.Background(), path))
+
+	// Broken chain
+	path.PathSegments[0], path.PathSegments[1] = path.PathSegments[1], path.PathSegments[0]
+	require.Error(t, federated.Check(context.Background(), path))
+}
+
+// countingBundles counts the lookups of X.509 bundles, one per verified token
+type countingBundles struct {
+	x509bundle.Source
+	lookups atomic.Int32
+}
+
+func (b *countingBundles) GetX509BundleForTrustDomain(td spiffeid.TrustDomain) (*x509bundle.Bundle, error) {
+	b.lookups.Add(1)
+	return b.Source.GetX509BundleForTrustDomain(td)
+}
+
+func TestVerificationCache(t *testing.T) {
+	ca, caKey := newCA(t)
+
+	path := &networkservice.Path{Index: 1}
+	for _, id := range []string{nscID, nseID} {
+		tok, _, err := spiffejwt.TokenGeneratorFunc(&x509Source{svid: newX509SVID(t, id, ca, caKey)}, time.Hour,
+			spiffejwt.WithCertificateChain(),
+			spiffejwt.WithAudience(nseID),
+		)(nil)
+		require.NoError(t, err)
+		path.PathSegments = append(path.PathSegments, &networkservice.PathSegment{Name: id, Token: tok})
+	}
+
+	bundles := &countingBundles{Source: x509bundle.FromX509Authorities(trustDomain, []*x509.Certificate{ca})}
+	verifier := spiffejwt.NewVerifier(spiffejwt.WithX509Bundles(bundles))
+	policies := []interface {
+		Check(ctx context.Context, input interface{}) error
+	}{
+		spiffejwt.PrevTokenPolicy(verifier),
+		spiffejwt.PathPolicy(verifier),
+	}
+
+	ctx := spiffejwt.WithVerificationCache(context.Background())
+	for _, policy := range policies {
+		require.NoError(t, policy.Check(ctx, path))
+	}
+	require.Equal(t, int32(len(path.GetPathSegments())), bundles.lookups.Load())
+
+	// Without the cache every policy verifies the tokens again
+	for _, policy := range policies {
+		require.NoError(t, policy.Check(context.Background(), path))
+	}
+	require.Equal(t, int32(2*len(path.GetPathSegments())+1), bundles.lookups.Load())
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// ConnectionInfo - describes the path segment of the connection the token is generated for
+type ConnectionInfo struct {
+	// NetworkService of the connection
+	NetworkService string
+	// PathIndex of the path segment the token is generated for
+	PathIndex uint32
+}
+
+// ConnectionGeneratorFunc - opt-in GeneratorFunc which also gets the connection the token is generated for, so the
+// generator can put it into claims. See updatetoken.NewConnectionServer.
+type ConnectionGeneratorFunc func(peerAuthInfo credentials.AuthInfo, connInfo *ConnectionInfo) (token string, expireTime time.Time, err error)
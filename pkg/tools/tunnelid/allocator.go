@@ -0,0 +1,360 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tunnelid provides allocator of the tunnel IDs (VXLAN/GENEVE VNIs, GRE keys) unique per peer and ID space
+package tunnelid
+
+import (
+	"crypto/rand"
+	"math/big"
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Space is a bounded space of the tunnel IDs
+type Space struct {
+	// Name - name of the space, IDs of different spaces never collide
+	Name string
+	// Min, Max - bounds of the space, inclusive
+	Min, Max uint32
+}
+
+// Key is a key of the allocated ID
+type Key struct {
+	PeerIP string
+	Space  string
+	ID     uint32
+}
+
+// Allocation is the ID allocated by the owner, usually the connection ID
+type Allocation struct {
+	Key
+	Owner string
+}
+
+// Persistence is a set of hooks to persist allocations, so the restarted allocator doesn't reuse IDs of the alive
+// tunnels
+type Persistence interface {
+	Load() ([]*Allocation, error)
+	Store(allocation *Allocation) error
+	Delete(allocation *Allocation) error
+}
+
+// anyPeer is a PeerIP of the IDs reserved for all peers
+const anyPeer = ""
+
+// poolKey is a key of the IDs pool of the peer and space
+type poolKey struct {
+	peerIP string
+	space  string
+}
+
+// pool keeps IDs of the peer and space in bitmaps, so the full ranges of IDs are skipped at once
+type pool struct {
+	lock      sync.Mutex
+	allocated bitmap
+	reserved  bitmap
+	owners    map[uint32]string
+}
+
+// Allocator allocates tunnel IDs. Each peer and space has its own pool with its own lock, so the allocations for
+// different peers don't wait for each other.
+type Allocator struct {
+	persistence Persistence
+
+	loadLock sync.Mutex
+	loaded   bool
+
+	poolsLock sync.Mutex
+	pools     map[poolKey]*pool
+
+	ownersLock sync.Mutex
+	owned      map[string]map[Key]struct{}
+}
+
+// Option is an option pattern for Allocator
+type Option func(a *Allocator)
+
+// WithPersistence sets persistence hooks of the Allocator
+func WithPersistence(persistence Persistence) Option {
+	return func(a *Allocator) {
+		a.persistence = persistence
+	}
+}
+
+// NewAllocator creates a new Allocator. Persisted allocations are loaded on the first allocation.
+func NewAllocator(opts ...Option) *Allocator {
+	a := &Allocator{
+		pools: make(map[poolKey]*pool),
+		owned: make(map[string]map[Key]struct{}),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Reserve reserves externally used IDs, so they are never allocated. Nil peerIP reserves IDs for all peers.
+func (a *Allocator) Reserve(peerIP net.IP, space *Space, ids ...uint32) {
+	p := a.pool(peerKey(peerIP), space.Name)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, id := range ids {
+		p.reserved.set(id)
+	}
+}
+
+// Allocate allocates a random free ID of the space for the peer. filter, if not nil, limits allowed IDs.
+func (a *Allocator) Allocate(peerIP net.IP, space *Space, owner string, filter func(id uint32) bool) (uint32, error) {
+	if err := a.load(); err != nil {
+		return 0, err
+	}
+
+	size := uint64(space.Max) - uint64(space.Min) + 1
+	start, err := rand.Int(rand.Reader, new(big.Int).SetUint64(size))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to generate a random tunnel ID")
+	}
+
+	p, anyPool := a.pool(peerKey(peerIP), space.Name), a.pool(anyPeer, space.Name)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var anyReserved bitmap
+	if p != anyPool {
+		// Lock order is always the peer pool first, then the pool of all peers
+		anyPool.lock.Lock()
+		defer anyPool.lock.Unlock()
+		anyReserved = anyPool.reserved
+	} else {
+		anyReserved = p.reserved
+	}
+
+	id, ok := p.find(anyReserved, space, start.Uint64(), filter)
+	if !ok {
+		return 0, errors.Errorf("no free %s IDs for the peer %s", space.Name, peerIP)
+	}
+	if err := a.store(p, key(peerIP, space, id), owner); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Claim allocates the given ID, e.g. the one selected by the peer. Claiming the ID already allocated by the same owner
+// is a no-op. The ID already allocated by another owner is left to it and false is returned, the peer is trusted to
+// know which IDs are in use on its side. The reserved IDs are never claimed, false is returned for them.
+func (a *Allocator) Claim(peerIP net.IP, space *Space, id uint32, owner string) (bool, error) {
+	if id < space.Min || id > space.Max {
+		return false, errors.Errorf("%s ID %d is out of range %d-%d", space.Name, id, space.Min, space.Max)
+	}
+	if err := a.load(); err != nil {
+		return false, err
+	}
+
+	p, anyPool := a.pool(peerKey(peerIP), space.Name), a.pool(anyPeer, space.Name)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p != anyPool {
+		anyPool.lock.Lock()
+		defer anyPool.lock.Unlock()
+	}
+
+	if p.reserved.has(id) || anyPool.reserved.has(id) {
+		return false, nil
+	}
+	if current, ok := p.owners[id]; ok {
+		return current == owner, nil
+	}
+	return true, a.store(p, key(peerIP, space, id), owner)
+}
+
+// Release releases the ID allocated by the owner. Returns error if the ID is not allocated by the owner.
+func (a *Allocator) Release(peerIP net.IP, space *Space, id uint32, owner string) error {
+	if err := a.load(); err != nil {
+		return err
+	}
+
+	p := a.pool(peerKey(peerIP), space.Name)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if current, ok := p.owners[id]; !ok || current != owner {
+		return errors.Errorf("%s ID %d for the peer %s is not allocated by %s", space.Name, id, peerIP, owner)
+	}
+	return a.delete(p, key(peerIP, space, id), owner)
+}
+
+// ReleaseOwner releases all IDs of the space allocated by the owner and returns them. It is used on Close to detect
+// and release IDs leaked by the owner.
+func (a *Allocator) ReleaseOwner(space *Space, owner string) (released []Key, err error) {
+	if err = a.load(); err != nil {
+		return nil, err
+	}
+
+	a.ownersLock.Lock()
+	var keys []Key
+	for k := range a.owned[owner] {
+		if k.Space == space.Name {
+			keys = append(keys, k)
+		}
+	}
+	a.ownersLock.Unlock()
+
+	for _, k := range keys {
+		p := a.pool(k.PeerIP, k.Space)
+		p.lock.Lock()
+		if current, ok := p.owners[k.ID]; ok && current == owner {
+			if deleteErr := a.delete(p, k, owner); deleteErr != nil && err == nil {
+				err = deleteErr
+			}
+			released = append(released, k)
+		}
+		p.lock.Unlock()
+	}
+	return released, err
+}
+
+// Allocations returns all current allocations
+func (a *Allocator) Allocations() []*Allocation {
+	a.ownersLock.Lock()
+	defer a.ownersLock.Unlock()
+
+	var rv []*Allocation
+	for owner, keys := range a.owned {
+		for k := range keys {
+			rv = append(rv, &Allocation{Key: k, Owner: owner})
+		}
+	}
+	return rv
+}
+
+// load loads persisted allocations if it is not done yet
+func (a *Allocator) load() error {
+	a.loadLock.Lock()
+	defer a.loadLock.Unlock()
+
+	if a.loaded || a.persistence == nil {
+		return nil
+	}
+	allocations, err := a.persistence.Load()
+	if err != nil {
+		return errors.Wrap(err, "failed to load tunnel ID allocations")
+	}
+	for _, allocation := range allocations {
+		p := a.pool(allocation.PeerIP, allocation.Space)
+		p.lock.Lock()
+		a.own(p, allocation.Key, allocation.Owner)
+		p.lock.Unlock()
+	}
+	a.loaded = true
+	return nil
+}
+
+func (a *Allocator) pool(peerIP, space string) *pool {
+	a.poolsLock.Lock()
+	defer a.poolsLock.Unlock()
+
+	k := poolKey{peerIP: peerIP, space: space}
+	p, ok := a.pools[k]
+	if !ok {
+		p = &pool{
+			allocated: make(bitmap),
+			reserved:  make(bitmap),
+			owners:    make(map[uint32]string),
+		}
+		a.pools[k] = p
+	}
+	return p
+}
+
+// store allocates the ID in the locked pool
+func (a *Allocator) store(p *pool, k Key, owner string) error {
+	if a.persistence != nil {
+		if err := a.persistence.Store(&Allocation{Key: k, Owner: owner}); err != nil {
+			return errors.Wrapf(err, "failed to persist %s ID %d", k.Space, k.ID)
+		}
+	}
+	a.own(p, k, owner)
+	return nil
+}
+
+func (a *Allocator) own(p *pool, k Key, owner string) {
+	p.allocated.set(k.ID)
+	p.owners[k.ID] = owner
+
+	a.ownersLock.Lock()
+	defer a.ownersLock.Unlock()
+
+	if a.owned[owner] == nil {
+		a.owned[owner] = make(map[Key]struct{})
+	}
+	a.owned[owner][k] = struct{}{}
+}
+
+// delete releases the ID in the locked pool
+func (a *Allocator) delete(p *pool, k Key, owner string) error {
+	p.allocated.clear(k.ID)
+	delete(p.owners, k.ID)
+
+	a.ownersLock.Lock()
+	delete(a.owned[owner], k)
+	if len(a.owned[owner]) == 0 {
+		delete(a.owned, owner)
+	}
+	a.ownersLock.Unlock()
+
+	if a.persistence != nil {
+		if err := a.persistence.Delete(&Allocation{Key: k, Owner: owner}); err != nil {
+			return errors.Wrapf(err, "failed to delete persisted %s ID %d", k.Space, k.ID)
+		}
+	}
+	return nil
+}
+
+// find returns a free ID of the space starting from the given offset in the space. The words of the bitmaps with all
+// IDs used are skipped at once.
+func (p *pool) find(anyReserved bitmap, space *Space, start uint64, filter func(id uint32) bool) (uint32, bool) {
+	size := uint64(space.Max) - uint64(space.Min) + 1
+	for i := uint64(0); i < size; {
+		id := space.Min + uint32((start+i)%size)
+		used := p.allocated.word(id) | p.reserved.word(id) | anyReserved.word(id)
+		if used == fullWord {
+			// Skip to the next word, but not past the end of the space
+			i += min(wordSize-uint64(id%wordSize), uint64(space.Max-id)+1)
+			continue
+		}
+		if used&(1<<(id%wordSize)) == 0 && (filter == nil || filter(id)) {
+			return id, true
+		}
+		i++
+	}
+	return 0, false
+}
+
+func peerKey(peerIP net.IP) string {
+	if peerIP == nil {
+		return anyPeer
+	}
+	return peerIP.String()
+}
+
+func key(peerIP net.IP, space *Space, id uint32) Key {
+	return Key{PeerIP: peerKey(peerIP), Space: space.Name, ID: id}
+}
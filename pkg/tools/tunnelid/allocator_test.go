@@ -0,0 +1,193 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunnelid_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/tunnelid"
+)
+
+var (
+	peer1 = net.ParseIP("192.0.2.1")
+	peer2 = net.ParseIP("192.0.2.2")
+)
+
+type mapPersistence map[tunnelid.Key]string
+
+func (p mapPersistence) Load() (rv []*tunnelid.Allocation, _ error) {
+	for k, owner := range p {
+		rv = append(rv, &tunnelid.Allocation{Key: k, Owner: owner})
+	}
+	return rv, nil
+}
+
+func (p mapPersistence) Store(allocation *tunnelid.Allocation) error {
+	p[allocation.Key] = allocation.Owner
+	return nil
+}
+
+func (p mapPersistence) Delete(allocation *tunnelid.Allocation) error {
+	delete(p, allocation.Key)
+	return nil
+}
+
+func TestAllocator_AllocateWholeSpace(t *testing.T) {
+	space := &tunnelid.Space{Name: "test", Min: 10, Max: 14}
+
+	a := tunnelid.NewAllocator()
+	a.Reserve(peer1, space, 10)
+	a.Reserve(nil, space, 11)
+
+	ids := make(map[uint32]bool)
+	for i := 0; i < 3; i++ {
+		id, err := a.Allocate(peer1, space, "conn", nil)
+		require.NoError(t, err)
+		require.True(t, id >= 12 && id <= 14)
+		ids[id] = true
+	}
+	require.Len(t, ids, 3)
+
+	_, err := a.Allocate(peer1, space, "conn", nil)
+	require.Error(t, err)
+
+	// Other peer has its own IDs, only the IDs reserved for all peers are excluded
+	for i := 0; i < 4; i++ {
+		id, err := a.Allocate(peer2, space, "conn", nil)
+		require.NoError(t, err)
+		require.NotEqual(t, uint32(11), id)
+	}
+
+	// Other space has its own IDs
+	_, err = a.Allocate(peer1, &tunnelid.Space{Name: "other", Min: 10, Max: 14}, "conn", nil)
+	require.NoError(t, err)
+}
+
+func TestAllocator_Filter(t *testing.T) {
+	space := &tunnelid.Space{Name: "test", Min: 1, Max: 100}
+
+	a := tunnelid.NewAllocator()
+
+	for i := 0; i < 50; i++ {
+		id, err := a.Allocate(peer1, space, "conn", func(id uint32) bool { return id%2 == 0 })
+		require.NoError(t, err)
+		require.Zero(t, id%2)
+	}
+	_, err := a.Allocate(peer1, space, "conn", func(id uint32) bool { return id%2 == 0 })
+	require.Error(t, err)
+}
+
+func TestAllocator_ClaimAndRelease(t *testing.T) {
+	space := &tunnelid.Space{Name: "test", Min: 1, Max: 100}
+
+	a := tunnelid.NewAllocator()
+
+	claim := func(id uint32, owner string) bool {
+		claimed, err := a.Claim(peer1, space, id, owner)
+		require.NoError(t, err)
+		return claimed
+	}
+
+	require.True(t, claim(5, "conn-1"))
+	require.True(t, claim(5, "conn-1"))
+	// The ID in use is left to its owner
+	require.False(t, claim(5, "conn-2"))
+	_, err := a.Claim(peer1, space, 101, "conn-2")
+	require.Error(t, err)
+
+	require.Error(t, a.Release(peer1, space, 5, "conn-2"))
+	require.NoError(t, a.Release(peer1, space, 5, "conn-1"))
+	require.Error(t, a.Release(peer1, space, 5, "conn-1"))
+
+	require.True(t, claim(5, "conn-2"))
+	require.True(t, claim(6, "conn-2"))
+	claimed, err := a.Claim(peer1, &tunnelid.Space{Name: "other", Min: 1, Max: 100}, 5, "conn-2")
+	require.NoError(t, err)
+	require.True(t, claimed)
+	released, err := a.ReleaseOwner(space, "conn-2")
+	require.NoError(t, err)
+	require.Len(t, released, 2)
+	require.Len(t, a.Allocations(), 1)
+}
+
+func TestAllocator_ClaimReserved(t *testing.T) {
+	space := &tunnelid.Space{Name: "test", Min: 1, Max: 100}
+
+	a := tunnelid.NewAllocator()
+	a.Reserve(peer1, space, 5)
+	a.Reserve(nil, space, 6)
+
+	for _, id := range []uint32{5, 6} {
+		claimed, err := a.Claim(peer1, space, id, "conn")
+		require.NoError(t, err)
+		require.False(t, claimed, id)
+	}
+
+	// The ID reserved for the other peer is free, the IDs reserved for all peers are not
+	claimed, err := a.Claim(peer2, space, 5, "conn")
+	require.NoError(t, err)
+	require.True(t, claimed)
+	claimed, err = a.Claim(peer2, space, 6, "conn")
+	require.NoError(t, err)
+	require.False(t, claimed)
+	require.Len(t, a.Allocations(), 1)
+}
+
+func TestAllocator_Persistence(t *testing.T) {
+	space := &tunnelid.Space{Name: "test", Min: 1, Max: 2}
+	persistence := make(mapPersistence)
+
+	a := tunnelid.NewAllocator(tunnelid.WithPersistence(persistence))
+	id, err := a.Allocate(peer1, space, "conn-1", nil)
+	require.NoError(t, err)
+	require.Len(t, persistence, 1)
+
+	// Restarted allocator doesn't reuse the persisted ID
+	a = tunnelid.NewAllocator(tunnelid.WithPersistence(persistence))
+	claimed, err := a.Claim(peer1, space, id, "conn-2")
+	require.NoError(t, err)
+	require.False(t, claimed)
+
+	otherID, err := a.Allocate(peer1, space, "conn-2", nil)
+	require.NoError(t, err)
+	require.NotEqual(t, id, otherID)
+
+	require.NoError(t, a.Release(peer1, space, id, "conn-1"))
+	require.Len(t, persistence, 1)
+}
+
+func TestAllocator_LargeSpace(t *testing.T) {
+	space := &tunnelid.Space{Name: "test", Min: 1, Max: 1<<20 - 1}
+
+	a := tunnelid.NewAllocator()
+	// Reserve all IDs except the last one, so the full words are skipped
+	ids := make([]uint32, 0, space.Max-1)
+	for id := space.Min; id < space.Max; id++ {
+		ids = append(ids, id)
+	}
+	a.Reserve(nil, space, ids...)
+
+	id, err := a.Allocate(peer1, space, "conn", nil)
+	require.NoError(t, err)
+	require.Equal(t, space.Max, id)
+
+	_, err = a.Allocate(peer1, space, "conn", nil)
+	require.Error(t, err)
+}
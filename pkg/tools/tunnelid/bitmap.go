@@ -0,0 +1,46 @@
+// Copyright (c) 2024 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunnelid
+
+const (
+	wordSize = 64
+	fullWord = ^uint64(0)
+)
+
+// bitmap is a sparse bitmap of the IDs, words with no IDs set are not stored
+type bitmap map[uint32]uint64
+
+func (b bitmap) word(id uint32) uint64 {
+	return b[id/wordSize]
+}
+
+func (b bitmap) has(id uint32) bool {
+	return b.word(id)&(1<<(id%wordSize)) != 0
+}
+
+func (b bitmap) set(id uint32) {
+	b[id/wordSize] |= 1 << (id % wordSize)
+}
+
+func (b bitmap) clear(id uint32) {
+	w := b[id/wordSize] &^ (1 << (id % wordSize))
+	if w == 0 {
+		delete(b, id/wordSize)
+		return
+	}
+	b[id/wordSize] = w
+}